@@ -14,4 +14,10 @@ type Config struct {
 	BidTimeout      time.Duration
 	Attributes      types.Attributes
 	MaxGroupVolumes int
+	DecisionLogSize int
+	TenantPolicy    *TenantPolicy
+
+	// MaxInFlightOrders limits number of orders reserving resources and bidding at the same time.
+	// Orders over the limit are queued by priority. Zero means no limit
+	MaxInFlightOrders int
 }
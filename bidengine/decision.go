@@ -0,0 +1,145 @@
+package bidengine
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+)
+
+// DefaultDecisionLogSize is amount of bid decisions kept when not set by config
+const DefaultDecisionLogSize = 1000
+
+const (
+	DecisionBid     = "bid"
+	DecisionDecline = "decline"
+	DecisionError   = "error"
+)
+
+// Reason codes recorded along with bid decision
+const (
+	ReasonBidPlaced             = "bid-placed"
+	ReasonGroupQueryFailed      = "group-query-failed"
+	ReasonShouldBidFailed       = "should-bid-failed"
+	ReasonProviderAttributes    = "provider-attributes"
+	ReasonOrderAttributes       = "order-attributes"
+	ReasonResourceRequirements  = "resource-requirements"
+	ReasonVolumeCount           = "volume-count"
+	ReasonSignatureRequirements = "signature-requirements"
+	ReasonGroupValidation       = "group-validation"
+	ReasonInsufficientCapacity  = "insufficient-capacity"
+	ReasonReservationFailed     = "reservation-failed"
+	ReasonPricingFailed         = "pricing-failed"
+	ReasonUnsupportedDenom      = "unsupported-denom"
+	ReasonPriceTooHigh          = "price-too-high"
+	ReasonBidFailed             = "bid-failed"
+)
+
+// BidDecision is the record of the outcome of bidding process for the order
+type BidDecision struct {
+	OrderID   mtypes.OrderID `json:"order_id"`
+	Decision  string         `json:"decision"`
+	Reason    string         `json:"reason"`
+	Message   string         `json:"message,omitempty"`
+	Price     *sdk.DecCoin   `json:"price,omitempty"`
+	MaxPrice  *sdk.DecCoin   `json:"max_price,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// DecisionFilter selects bid decisions. Zero value fields match any decision
+type DecisionFilter struct {
+	Owner string
+	DSeq  uint64
+	GSeq  uint32
+	OSeq  uint32
+}
+
+func (f DecisionFilter) matches(d BidDecision) bool {
+	if f.Owner != "" && f.Owner != d.OrderID.Owner {
+		return false
+	}
+
+	if f.DSeq != 0 && f.DSeq != d.OrderID.DSeq {
+		return false
+	}
+
+	if f.GSeq != 0 && f.GSeq != d.OrderID.GSeq {
+		return false
+	}
+
+	if f.OSeq != 0 && f.OSeq != d.OrderID.OSeq {
+		return false
+	}
+
+	return true
+}
+
+// DecisionClient provides access to recorded bid decisions
+type DecisionClient interface {
+	// Decisions returns recorded decisions matching filter, most recent first
+	Decisions(context.Context, DecisionFilter) ([]BidDecision, error)
+}
+
+// decisionLog is the bounded store of bid decisions.
+// Once full the oldest decision is evicted
+type decisionLog struct {
+	lock    sync.RWMutex
+	entries []BidDecision
+	next    int
+	full    bool
+}
+
+func newDecisionLog(size int) *decisionLog {
+	if size <= 0 {
+		size = DefaultDecisionLogSize
+	}
+
+	return &decisionLog{
+		entries: make([]BidDecision, size),
+	}
+}
+
+func (dl *decisionLog) record(d BidDecision) {
+	if d.Timestamp.IsZero() {
+		d.Timestamp = time.Now().UTC()
+	}
+
+	dl.lock.Lock()
+	defer dl.lock.Unlock()
+
+	dl.entries[dl.next] = d
+	dl.next++
+
+	if dl.next == len(dl.entries) {
+		dl.next = 0
+		dl.full = true
+	}
+}
+
+func (dl *decisionLog) list(filter DecisionFilter) []BidDecision {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	count := dl.next
+	if dl.full {
+		count = len(dl.entries)
+	}
+
+	res := make([]BidDecision, 0)
+
+	for i := 0; i < count; i++ {
+		idx := dl.next - 1 - i
+		if idx < 0 {
+			idx += len(dl.entries)
+		}
+
+		if d := dl.entries[idx]; filter.matches(d) {
+			res = append(res, d)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,50 @@
+package bidengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/akash-network/node/testutil"
+)
+
+func Test_DecisionLogEvictsOldest(t *testing.T) {
+	dl := newDecisionLog(3)
+
+	oid := testutil.OrderID(t)
+	for i := uint64(1); i <= 5; i++ {
+		oid.DSeq = i
+		dl.record(BidDecision{OrderID: oid, Decision: DecisionDecline})
+	}
+
+	decisions := dl.list(DecisionFilter{})
+	require.Len(t, decisions, 3)
+
+	// most recent first
+	require.Equal(t, uint64(5), decisions[0].OrderID.DSeq)
+	require.Equal(t, uint64(4), decisions[1].OrderID.DSeq)
+	require.Equal(t, uint64(3), decisions[2].OrderID.DSeq)
+	require.False(t, decisions[0].Timestamp.IsZero())
+}
+
+func Test_DecisionLogFilter(t *testing.T) {
+	dl := newDecisionLog(0)
+
+	first := testutil.OrderID(t)
+	second := testutil.OrderID(t)
+
+	dl.record(BidDecision{OrderID: first, Decision: DecisionDecline, Reason: ReasonOrderAttributes})
+	dl.record(BidDecision{OrderID: second, Decision: DecisionBid, Reason: ReasonBidPlaced})
+
+	require.Len(t, dl.list(DecisionFilter{}), 2)
+
+	decisions := dl.list(DecisionFilter{Owner: first.Owner})
+	require.Len(t, decisions, 1)
+	require.Equal(t, first, decisions[0].OrderID)
+
+	decisions = dl.list(DecisionFilter{Owner: second.Owner, DSeq: second.DSeq, GSeq: second.GSeq, OSeq: second.OSeq})
+	require.Len(t, decisions, 1)
+	require.Equal(t, ReasonBidPlaced, decisions[0].Reason)
+
+	require.Len(t, dl.list(DecisionFilter{Owner: first.Owner, DSeq: second.DSeq + 1}), 0)
+}
@@ -0,0 +1,212 @@
+package bidengine
+
+import (
+	"context"
+	"fmt"
+
+	atypes "github.com/akash-network/akash-api/go/node/audit/v1beta3"
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+
+	"github.com/akash-network/provider/session"
+)
+
+// GroupCheck is the outcome of the single check bid engine runs before bidding on a group.
+// Name is the reason code reported when check fails
+type GroupCheck struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// GroupCheckClient runs bid engine checks against the group without bidding on it
+type GroupCheckClient interface {
+	CheckGroup(context.Context, *dtypes.Group) ([]GroupCheck, error)
+}
+
+// FailedGroupCheck returns first failed check or nil if all checks passed
+func FailedGroupCheck(checks []GroupCheck) *GroupCheck {
+	for i := range checks {
+		if !checks[i].Passed {
+			return &checks[i]
+		}
+	}
+
+	return nil
+}
+
+// groupChecker holds everything needed to decide if provider is willing to bid on a group
+type groupChecker struct {
+	cfg     Config
+	session session.Session
+	pass    ProviderAttrSignatureService
+	usage   TenantUsageClient
+	// dryRun checks do not hold tenant quota, nothing releases it as no order follows them
+	dryRun bool
+}
+
+type groupCheckFn func(context.Context, *dtypes.Group) (bool, string, error)
+
+// run executes checks in the order bid engine applies them.
+// When failFast is set it stops at the first failed check
+func (gc groupChecker) run(ctx context.Context, group *dtypes.Group, failFast bool) ([]GroupCheck, error) {
+	steps := []struct {
+		name  string
+		check groupCheckFn
+	}{
+		{name: ReasonTenantDenied, check: gc.checkTenantDenied},
+		{name: ReasonTenantNotAllowed, check: gc.checkTenantAllowed},
+		{name: ReasonProviderAttributes, check: gc.checkProviderAttributes},
+		{name: ReasonOrderAttributes, check: gc.checkOrderAttributes},
+		{name: ReasonResourceRequirements, check: gc.checkResourceRequirements},
+		{name: ReasonVolumeCount, check: gc.checkVolumeCount},
+		{name: ReasonSignatureRequirements, check: gc.checkSignatureRequirements},
+		{name: ReasonGroupValidation, check: gc.checkGroupValidation},
+		{name: ReasonTenantQuota, check: gc.checkTenantQuota},
+	}
+
+	res := make([]GroupCheck, 0, len(steps))
+
+	for _, step := range steps {
+		passed, msg, err := step.check(ctx, group)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, GroupCheck{
+			Name:    step.name,
+			Passed:  passed,
+			Message: msg,
+		})
+
+		if !passed && failFast {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+func (gc groupChecker) checkTenantDenied(_ context.Context, group *dtypes.Group) (bool, string, error) {
+	if gc.cfg.TenantPolicy == nil || !gc.cfg.TenantPolicy.denied(group.GroupID.Owner) {
+		return true, "", nil
+	}
+
+	return false, fmt.Sprintf("owner %s is denied by tenant policy", group.GroupID.Owner), nil
+}
+
+func (gc groupChecker) checkTenantAllowed(_ context.Context, group *dtypes.Group) (bool, string, error) {
+	if gc.cfg.TenantPolicy == nil || gc.cfg.TenantPolicy.allowed(group.GroupID.Owner) {
+		return true, "", nil
+	}
+
+	return false, fmt.Sprintf("owner %s is not in tenant allow list", group.GroupID.Owner), nil
+}
+
+// does provider have required attributes?
+func (gc groupChecker) checkProviderAttributes(_ context.Context, group *dtypes.Group) (bool, string, error) {
+	if !group.GroupSpec.MatchAttributes(gc.session.Provider().Attributes) {
+		return false, "incompatible provider attributes", nil
+	}
+
+	return true, "", nil
+}
+
+// does order have required attributes?
+func (gc groupChecker) checkOrderAttributes(_ context.Context, group *dtypes.Group) (bool, string, error) {
+	if !gc.cfg.Attributes.SubsetOf(group.GroupSpec.Requirements.Attributes) {
+		return false, "incompatible order attributes", nil
+	}
+
+	return true, "", nil
+}
+
+// does provider have required capabilities?
+func (gc groupChecker) checkResourceRequirements(_ context.Context, group *dtypes.Group) (bool, string, error) {
+	attr, err := gc.pass.GetAttributes()
+	if err != nil {
+		return false, "", err
+	}
+
+	if !group.GroupSpec.MatchResourcesRequirements(attr) {
+		return false, "incompatible attributes for resources requirements", nil
+	}
+
+	return true, "", nil
+}
+
+func (gc groupChecker) checkVolumeCount(_ context.Context, group *dtypes.Group) (bool, string, error) {
+	for _, resources := range group.GroupSpec.GetResources() {
+		if len(resources.Resources.Storage) > gc.cfg.MaxGroupVolumes {
+			return false, fmt.Sprintf("group volumes count exceeds (%d > %d)", len(resources.Resources.Storage), gc.cfg.MaxGroupVolumes), nil
+		}
+	}
+
+	return true, "", nil
+}
+
+// check that the signature requirements are met for each attribute
+func (gc groupChecker) checkSignatureRequirements(_ context.Context, group *dtypes.Group) (bool, string, error) {
+	signatureRequirements := group.GroupSpec.Requirements.SignedBy
+	if signatureRequirements.Size() == 0 {
+		return true, "", nil
+	}
+
+	provAttr := []atypes.Provider{
+		{
+			Owner:      gc.session.Provider().Owner,
+			Auditor:    "",
+			Attributes: gc.session.Provider().Attributes,
+		},
+	}
+
+	auditors := make([]string, 0)
+	auditors = append(auditors, signatureRequirements.AllOf...)
+	auditors = append(auditors, signatureRequirements.AnyOf...)
+
+	gotten := make(map[string]struct{})
+	for _, auditor := range auditors {
+		if _, done := gotten[auditor]; done {
+			continue
+		}
+
+		result, err := gc.pass.GetAuditorAttributeSignatures(auditor)
+		if err != nil {
+			return false, "", err
+		}
+
+		provAttr = append(provAttr, result...)
+		gotten[auditor] = struct{}{}
+	}
+
+	if !group.GroupSpec.MatchRequirements(provAttr) {
+		return false, "attribute signature requirements not met", nil
+	}
+
+	return true, "", nil
+}
+
+func (gc groupChecker) checkGroupValidation(_ context.Context, group *dtypes.Group) (bool, string, error) {
+	if err := group.GroupSpec.ValidateBasic(); err != nil {
+		return false, err.Error(), nil
+	}
+
+	return true, "", nil
+}
+
+// does owner have room left within its quota?
+func (gc groupChecker) checkTenantQuota(ctx context.Context, group *dtypes.Group) (bool, string, error) {
+	if gc.cfg.TenantPolicy == nil {
+		return true, "", nil
+	}
+
+	ok, err := gc.cfg.TenantPolicy.checkQuota(ctx, gc.usage, group, !gc.dryRun)
+	if err != nil {
+		return false, "", err
+	}
+
+	if !ok {
+		return false, fmt.Sprintf("owner %s quota exceeded", group.GroupID.Owner), nil
+	}
+
+	return true, "", nil
+}
@@ -0,0 +1,110 @@
+package bidengine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	ptypes "github.com/akash-network/akash-api/go/node/provider/v1beta3"
+	atypes "github.com/akash-network/akash-api/go/node/types/v1beta3"
+	"github.com/akash-network/node/testutil"
+
+	"github.com/akash-network/provider/session"
+)
+
+func makeGroupCheckerForTest(t *testing.T, cfg Config) groupChecker {
+	provider := &ptypes.Provider{
+		Owner: testutil.AccAddress(t).String(),
+	}
+
+	return groupChecker{
+		cfg:     cfg,
+		session: session.New(testutil.Logger(t), nil, provider, testBidCreatedAt),
+		pass:    nullProviderAttrSignatureService{},
+		usage:   fixedOwnerUsage{},
+	}
+}
+
+func checkNames(checks []GroupCheck) []string {
+	res := make([]string, 0, len(checks))
+	for _, check := range checks {
+		res = append(res, check.Name)
+	}
+
+	return res
+}
+
+func Test_GroupCheckerReportsAllChecks(t *testing.T) {
+	group := &dtypes.Group{
+		GroupID:   dtypes.MakeGroupID(testutil.DeploymentID(t), 1),
+		GroupSpec: testutil.GroupSpec(t),
+	}
+	group.GroupSpec.Requirements.Attributes = nil
+	group.GroupSpec.Requirements.SignedBy = atypes.SignedBy{}
+
+	policy, err := NewTenantPolicy(TenantPolicyConfig{
+		Deny: []string{group.GroupID.Owner},
+	})
+	require.NoError(t, err)
+
+	checker := makeGroupCheckerForTest(t, Config{
+		Attributes: atypes.Attributes{
+			{
+				Key:   "owner",
+				Value: "me",
+			},
+		},
+		MaxGroupVolumes: 100,
+		TenantPolicy:    policy,
+	})
+
+	checks, err := checker.run(context.Background(), group, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		ReasonTenantDenied,
+		ReasonTenantNotAllowed,
+		ReasonProviderAttributes,
+		ReasonOrderAttributes,
+		ReasonResourceRequirements,
+		ReasonVolumeCount,
+		ReasonSignatureRequirements,
+		ReasonGroupValidation,
+		ReasonTenantQuota,
+	}, checkNames(checks))
+
+	failed := make([]string, 0)
+	for _, check := range checks {
+		if !check.Passed {
+			require.NotEmpty(t, check.Message)
+			failed = append(failed, check.Name)
+		}
+	}
+	require.Equal(t, []string{ReasonTenantDenied, ReasonOrderAttributes}, failed)
+
+	first := FailedGroupCheck(checks)
+	require.NotNil(t, first)
+	require.Equal(t, ReasonTenantDenied, first.Name)
+
+	// order stops at first failed check
+	checks, err = checker.run(context.Background(), group, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{ReasonTenantDenied}, checkNames(checks))
+}
+
+func Test_GroupCheckerPasses(t *testing.T) {
+	group := &dtypes.Group{
+		GroupID:   dtypes.MakeGroupID(testutil.DeploymentID(t), 1),
+		GroupSpec: testutil.GroupSpec(t),
+	}
+	group.GroupSpec.Requirements.Attributes = nil
+	group.GroupSpec.Requirements.SignedBy = atypes.SignedBy{}
+
+	checker := makeGroupCheckerForTest(t, Config{MaxGroupVolumes: 100})
+
+	checks, err := checker.run(context.Background(), group, true)
+	require.NoError(t, err)
+	require.Len(t, checks, 9)
+	require.Nil(t, FailedGroupCheck(checks))
+}
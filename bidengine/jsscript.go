@@ -0,0 +1,167 @@
+package bidengine
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/pkg/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// jsPriceFunction is the name of the function pricing script must declare.
+// It is called as calculatePrice(data, env) where data is the same JSON document
+// shell script pricing receives on stdin and env holds owner and denom of the order
+const jsPriceFunction = "calculatePrice"
+
+const jsMaxCallStackSize = 1024
+
+var (
+	errJSNoPriceFunction = errors.Errorf("script must declare function %s(data, env)", jsPriceFunction)
+	errJSInvalidResult   = errors.New("script result must be a number or numeric string")
+)
+
+type jsScriptPricing struct {
+	program      *goja.Program
+	processLimit chan int
+	runtimeLimit time.Duration
+}
+
+// MakeJSScriptPricing creates strategy which evaluates pricing script in-process with embedded javascript
+// interpreter. script is compiled once, each bid is evaluated in fresh sandbox without access to filesystem,
+// network or environment, and aborted once runtime limit is exceeded
+func MakeJSScriptPricing(path string, processLimit uint, runtimeLimit time.Duration) (BidPricingStrategy, error) {
+	if len(path) == 0 {
+		return nil, errPathEmpty
+	}
+	if processLimit == 0 {
+		return nil, errProcessLimitZero
+	}
+	if runtimeLimit == 0 {
+		return nil, errProcessRuntimeLimitZero
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := goja.Compile(path, string(src), true)
+	if err != nil {
+		return nil, err
+	}
+
+	// make sure script declares pricing function before accepting it
+	vm := goja.New()
+	if _, err = vm.RunProgram(program); err != nil {
+		return nil, err
+	}
+
+	if _, valid := goja.AssertFunction(vm.Get(jsPriceFunction)); !valid {
+		return nil, errJSNoPriceFunction
+	}
+
+	result := jsScriptPricing{
+		program:      program,
+		processLimit: make(chan int, processLimit),
+		runtimeLimit: runtimeLimit,
+	}
+
+	// same as with shell script pricing the channel is used as a semaphore
+	// to bound amount of CPU consumed by concurrent evaluations
+	for i := uint(0); i != processLimit; i++ {
+		result.processLimit <- 0
+	}
+
+	return result, nil
+}
+
+func (jsp jsScriptPricing) CalculatePrice(ctx context.Context, req Request) (sdk.DecCoin, error) {
+	// round-trip through JSON so script observes exactly the same document as shell script does
+	buf, err := json.Marshal(makeDataForScript(req))
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+
+	var data interface{}
+	if err = json.Unmarshal(buf, &data); err != nil {
+		return sdk.DecCoin{}, err
+	}
+
+	denom := req.GSpec.Price().Denom
+
+	// Take 1 from the channel
+	<-jsp.processLimit
+	defer func() {
+		// Always return it when this function is complete
+		jsp.processLimit <- 0
+	}()
+
+	processCtx, cancel := context.WithTimeout(ctx, jsp.runtimeLimit)
+	defer cancel()
+
+	vm := goja.New()
+	vm.SetMaxCallStackSize(jsMaxCallStackSize)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-processCtx.Done():
+			vm.Interrupt(processCtx.Err())
+		case <-done:
+		}
+	}()
+
+	res, err := jsp.run(vm, data, denom, req.Owner)
+
+	if ctxErr := processCtx.Err(); ctxErr != nil {
+		return sdk.DecCoin{}, ctxErr
+	}
+
+	if err != nil {
+		return sdk.DecCoin{}, errors.Wrap(err, "script failure")
+	}
+
+	var price string
+
+	switch val := res.Export().(type) {
+	case int64:
+		price = strconv.FormatInt(val, 10)
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return sdk.DecCoin{}, ErrBidQuantityInvalid
+		}
+		price = strconv.FormatFloat(val, 'f', -1, 64)
+	case string:
+		price = val
+	default:
+		return sdk.DecCoin{}, errors.Wrap(errJSInvalidResult, "script failure")
+	}
+
+	return parseScriptPrice(denom, price)
+}
+
+func (jsp jsScriptPricing) run(vm *goja.Runtime, data interface{}, denom string, owner string) (goja.Value, error) {
+	if _, err := vm.RunProgram(jsp.program); err != nil {
+		return nil, err
+	}
+
+	fn, valid := goja.AssertFunction(vm.Get(jsPriceFunction))
+	if !valid {
+		return nil, errJSNoPriceFunction
+	}
+
+	env := map[string]interface{}{
+		"owner": owner,
+		"denom": denom,
+	}
+
+	return fn(goja.Undefined(), vm.ToValue(data), vm.ToValue(env))
+}
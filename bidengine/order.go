@@ -2,6 +2,7 @@ package bidengine
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"time"
@@ -13,7 +14,6 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/tendermint/tendermint/libs/log"
 
-	atypes "github.com/akash-network/akash-api/go/node/audit/v1beta3"
 	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
 	"github.com/akash-network/node/pubsub"
@@ -37,9 +37,18 @@ type order struct {
 	sub                        pubsub.Subscriber
 	reservationFulfilledNotify chan<- int
 
-	log  log.Logger
-	lc   lifecycle.Lifecycle
-	pass ProviderAttrSignatureService
+	log       log.Logger
+	lc        lifecycle.Lifecycle
+	pass      ProviderAttrSignatureService
+	decisions *decisionLog
+	scheduler *orderScheduler
+}
+
+// shouldBidResult holds outcome of the shouldBid check along with the reason of decline
+type shouldBidResult struct {
+	bid     bool
+	reason  string
+	message string
 }
 
 var (
@@ -104,6 +113,8 @@ func newOrderInternal(svc *service, oid mtypes.OrderID, cfg Config, pass Provide
 		lc:                         lifecycle.New(),
 		reservationFulfilledNotify: reservationFulfilledNotify, // Normally nil in production
 		pass:                       pass,
+		decisions:                  svc.decisions,
+		scheduler:                  svc.scheduler,
 	}
 
 	// Shut down when parent begins shutting down
@@ -150,6 +161,11 @@ func (o *order) isStaleBid(bid mtypes.Bid) bool {
 	return atLeastThisOld > o.cfg.BidTimeout
 }
 
+func (o *order) recordDecision(decision BidDecision) {
+	decision.OrderID = o.orderID
+	o.decisions.record(decision)
+}
+
 func (o *order) run(checkForExistingBid bool) {
 	defer o.lc.ShutdownCompleted()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -164,9 +180,11 @@ func (o *order) run(checkForExistingBid bool) {
 		queryBidCh    <-chan runner.Result
 		shouldBidCh   <-chan runner.Result
 		bidTimeout    <-chan time.Time
+		ticketReady   <-chan struct{}
 
 		group       *dtypes.Group
 		reservation ctypes.Reservation
+		ticket      *orderTicket
 
 		won bool
 		msg *mtypes.MsgCreateBid
@@ -307,6 +325,11 @@ loop:
 
 			if result.Error() != nil {
 				o.log.Error("fetching group", "err", result.Error())
+				o.recordDecision(BidDecision{
+					Decision: DecisionError,
+					Reason:   ReasonGroupQueryFailed,
+					Message:  result.Error().Error(),
+				})
 				break loop
 			}
 
@@ -314,7 +337,7 @@ loop:
 			group = &res
 
 			shouldBidCh = runner.Do(func() runner.Result {
-				return runner.NewResult(o.shouldBid(group))
+				return runner.NewResult(o.shouldBid(ctx, group))
 			})
 
 		case result := <-shouldBidCh:
@@ -323,17 +346,36 @@ loop:
 			if result.Error() != nil {
 				shouldBidCounter.WithLabelValues(metricsutils.FailLabel).Inc()
 				o.log.Error("failure during checking should bid", "err", result.Error())
+				o.recordDecision(BidDecision{
+					Decision: DecisionError,
+					Reason:   ReasonShouldBidFailed,
+					Message:  result.Error().Error(),
+				})
 				break loop
 			}
 
-			shouldBid := result.Value().(bool)
-			if !shouldBid {
-				shouldBidCounter.WithLabelValues("decline").Inc()
-				o.log.Debug("declined to bid")
+			shouldBid := result.Value().(shouldBidResult)
+			if !shouldBid.bid {
+				shouldBidCounter.WithLabelValues(shouldBidDeclineLabel(shouldBid.reason)).Inc()
+				o.log.Debug("declined to bid", "reason", shouldBid.reason)
+				o.recordDecision(BidDecision{
+					Decision: DecisionDecline,
+					Reason:   shouldBid.reason,
+					Message:  shouldBid.message,
+				})
 				break loop
 			}
 
 			shouldBidCounter.WithLabelValues("accept").Inc()
+
+			// Wait for turn to reserve resources, orders are let through by priority
+			ticket = o.scheduler.schedule(o.orderID, makeOrderPriority(group, o.cfg.TenantPolicy))
+			ticketReady = ticket.ready
+			o.log.Debug("order queued for reservation")
+
+		case <-ticketReady:
+			ticketReady = nil
+
 			o.log.Info("requesting reservation")
 			// Begin reserving resources from cluster.
 			clusterch = runner.Do(metricsutils.ObserveRunner(func() runner.Result {
@@ -344,9 +386,23 @@ loop:
 		case result := <-clusterch:
 			clusterch = nil
 
+			// reservation is settled in inventory, quota check does not need to count it as pending anymore
+			o.releaseQuota()
+
 			if result.Error() != nil {
 				reservationCounter.WithLabelValues(metricsutils.OpenLabel, metricsutils.FailLabel)
 				o.log.Error("reserving resources", "err", result.Error())
+
+				reason := ReasonReservationFailed
+				if errors.Is(result.Error(), ctypes.ErrInsufficientCapacity) {
+					reason = ReasonInsufficientCapacity
+				}
+
+				o.recordDecision(BidDecision{
+					Decision: DecisionDecline,
+					Reason:   reason,
+					Message:  result.Error().Error(),
+				})
 				break loop
 			}
 
@@ -365,6 +421,7 @@ loop:
 			// Resources reserved
 			reservation = result.Value().(ctypes.Reservation)
 			if bidPlaced {
+				o.scheduler.release(ticket)
 				o.log.Info("Fulfillment already exists")
 				// fulfillment already created (state recovered via queryExistingOrders)
 				break
@@ -381,6 +438,13 @@ loop:
 			pricech = nil
 			if result.Error() != nil {
 				o.log.Error("error calculating price", "err", result.Error())
+				maxPrice := group.GroupSpec.Price()
+				o.recordDecision(BidDecision{
+					Decision: DecisionError,
+					Reason:   ReasonPricingFailed,
+					Message:  result.Error().Error(),
+					MaxPrice: &maxPrice,
+				})
 				break loop
 			}
 
@@ -389,11 +453,23 @@ loop:
 
 			if maxPrice.GetDenom() != price.GetDenom() {
 				o.log.Error("Unsupported Denomination", "calculated", price.String(), "max-price", maxPrice.String())
+				o.recordDecision(BidDecision{
+					Decision: DecisionDecline,
+					Reason:   ReasonUnsupportedDenom,
+					Price:    &price,
+					MaxPrice: &maxPrice,
+				})
 				break loop
 			}
 
 			if maxPrice.IsLT(price) {
 				o.log.Info("Price too high, not bidding", "price", price.String(), "max-price", maxPrice.String())
+				o.recordDecision(BidDecision{
+					Decision: DecisionDecline,
+					Reason:   ReasonPriceTooHigh,
+					Price:    &price,
+					MaxPrice: &maxPrice,
+				})
 				break loop
 			}
 
@@ -407,13 +483,29 @@ loop:
 
 		case result := <-bidch:
 			bidch = nil
+			o.scheduler.release(ticket)
 			if result.Error() != nil {
 				bidCounter.WithLabelValues(metricsutils.OpenLabel, metricsutils.FailLabel).Inc()
 				o.log.Error("bid failed", "err", result.Error())
+				maxPrice := group.GroupSpec.Price()
+				o.recordDecision(BidDecision{
+					Decision: DecisionError,
+					Reason:   ReasonBidFailed,
+					Message:  result.Error().Error(),
+					Price:    &msg.Price,
+					MaxPrice: &maxPrice,
+				})
 				break loop
 			}
 
 			o.log.Info("bid complete")
+			maxPrice := group.GroupSpec.Price()
+			o.recordDecision(BidDecision{
+				Decision: DecisionBid,
+				Reason:   ReasonBidPlaced,
+				Price:    &msg.Price,
+				MaxPrice: &maxPrice,
+			})
 			bidCounter.WithLabelValues(metricsutils.OpenLabel, metricsutils.SuccessLabel).Inc()
 
 			// Fulfillment placed.
@@ -432,6 +524,10 @@ loop:
 	o.lc.ShutdownInitiated(nil)
 	o.sub.Close()
 
+	// give reservation slot to the next order
+	o.scheduler.release(ticket)
+	o.releaseQuota()
+
 	// cancel reservation
 	if !won {
 		if clusterch != nil {
@@ -483,75 +579,40 @@ loop:
 	}
 }
 
-func (o *order) shouldBid(group *dtypes.Group) (bool, error) {
-	// does provider have required attributes?
-	if !group.GroupSpec.MatchAttributes(o.session.Provider().Attributes) {
-		o.log.Debug("unable to fulfill: incompatible provider attributes")
-		return false, nil
+func (o *order) releaseQuota() {
+	if o.cfg.TenantPolicy != nil {
+		o.cfg.TenantPolicy.releaseQuota(o.orderID.GroupID())
 	}
+}
 
-	// does order have required attributes?
-	if !o.cfg.Attributes.SubsetOf(group.GroupSpec.Requirements.Attributes) {
-		o.log.Debug("unable to fulfill: incompatible order attributes")
-		return false, nil
+// shouldBidDeclineLabel returns result label of the declined order.
+// Orders rejected by tenant policy are counted separately
+func shouldBidDeclineLabel(reason string) string {
+	switch reason {
+	case ReasonTenantDenied, ReasonTenantNotAllowed, ReasonTenantQuota:
+		return reason
 	}
 
-	attr, err := o.pass.GetAttributes()
-	if err != nil {
-		return false, err
-	}
+	return "decline"
+}
 
-	// does provider have required capabilities?
-	if !group.GroupSpec.MatchResourcesRequirements(attr) {
-		o.log.Debug("unable to fulfill: incompatible attributes for resources requirements", "wanted", group.GroupSpec, "have", attr)
-		return false, nil
+func (o *order) shouldBid(ctx context.Context, group *dtypes.Group) (shouldBidResult, error) {
+	checker := groupChecker{
+		cfg:     o.cfg,
+		session: o.session,
+		pass:    o.pass,
+		usage:   o.cluster,
 	}
 
-	for _, resources := range group.GroupSpec.GetResources() {
-		if len(resources.Resources.Storage) > o.cfg.MaxGroupVolumes {
-			o.log.Info(fmt.Sprintf("unable to fulfill: group volumes count exceeds (%d > %d)", len(resources.Resources.Storage), o.cfg.MaxGroupVolumes))
-			return false, nil
-		}
+	checks, err := checker.run(ctx, group, true)
+	if err != nil {
+		return shouldBidResult{}, err
 	}
-	signatureRequirements := group.GroupSpec.Requirements.SignedBy
-	if signatureRequirements.Size() != 0 {
-		// Check that the signature requirements are met for each attribute
-		var provAttr []atypes.Provider
-		ownAttrs := atypes.Provider{
-			Owner:      o.session.Provider().Owner,
-			Auditor:    "",
-			Attributes: o.session.Provider().Attributes,
-		}
-		provAttr = append(provAttr, ownAttrs)
-		auditors := make([]string, 0)
-		auditors = append(auditors, group.GroupSpec.Requirements.SignedBy.AllOf...)
-		auditors = append(auditors, group.GroupSpec.Requirements.SignedBy.AnyOf...)
-
-		gotten := make(map[string]struct{})
-		for _, auditor := range auditors {
-			_, done := gotten[auditor]
-			if done {
-				continue
-			}
-			result, err := o.pass.GetAuditorAttributeSignatures(auditor)
-			if err != nil {
-				return false, err
-			}
-			provAttr = append(provAttr, result...)
-			gotten[auditor] = struct{}{}
-		}
 
-		ok := group.GroupSpec.MatchRequirements(provAttr)
-		if !ok {
-			o.log.Debug("attribute signature requirements not met")
-			return false, nil
-		}
+	if failed := FailedGroupCheck(checks); failed != nil {
+		o.log.Info("unable to fulfill", "reason", failed.Name, "details", failed.Message)
+		return shouldBidResult{reason: failed.Name, message: failed.Message}, nil
 	}
 
-	if err := group.GroupSpec.ValidateBasic(); err != nil {
-		o.log.Error("unable to fulfill: group validation error",
-			"err", err)
-		return false, nil
-	}
-	return true, nil
+	return shouldBidResult{bid: true}, nil
 }
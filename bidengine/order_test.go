@@ -28,6 +28,7 @@ import (
 	"github.com/akash-network/node/testutil"
 
 	clustermocks "github.com/akash-network/provider/cluster/mocks"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	"github.com/akash-network/provider/session"
 )
 
@@ -59,6 +60,7 @@ var _ BidPricingStrategy = (*alwaysFailsBidPricingStrategy)(nil)
 
 func makeMocks(s *orderTestScaffold) {
 	groupResult := &dtypes.QueryGroupResponse{}
+	groupResult.Group.GroupID = s.groupID
 	groupResult.Group.GroupSpec.Name = "testGroupName"
 	groupResult.Group.GroupSpec.Resources = make([]dtypes.Resource, 1)
 
@@ -138,13 +140,21 @@ func (nullProviderAttrSignatureService) GetAttributes() (atypes.Attributes, erro
 const testBidCreatedAt = 1234556789
 
 func makeOrderForTest(t *testing.T, checkForExistingBid bool, bidState mtypes.Bid_State, pricing BidPricingStrategy, callerConfig *Config, sessionHeight int64) (*order, orderTestScaffold, <-chan int) {
+	return makeOrderForTestInternal(t, testutil.DeploymentID(t), checkForExistingBid, bidState, pricing, callerConfig, sessionHeight)
+}
+
+func makeOrderForTestWithDeployment(t *testing.T, deploymentID dtypes.DeploymentID, callerConfig *Config, setupMocks ...func(*orderTestScaffold)) (*order, orderTestScaffold, <-chan int) {
+	return makeOrderForTestInternal(t, deploymentID, false, mtypes.BidStateInvalid, nil, callerConfig, testBidCreatedAt, setupMocks...)
+}
+
+func makeOrderForTestInternal(t *testing.T, deploymentID dtypes.DeploymentID, checkForExistingBid bool, bidState mtypes.Bid_State, pricing BidPricingStrategy, callerConfig *Config, sessionHeight int64, setupMocks ...func(*orderTestScaffold)) (*order, orderTestScaffold, <-chan int) {
 	if pricing == nil {
 		pricing = testBidPricingStrategy(1)
 		require.NotNil(t, pricing)
 	}
 
 	var scaffold orderTestScaffold
-	scaffold.deploymentID = testutil.DeploymentID(t)
+	scaffold.deploymentID = deploymentID
 
 	scaffold.groupID = dtypes.MakeGroupID(scaffold.deploymentID, 2)
 
@@ -154,6 +164,10 @@ func makeOrderForTest(t *testing.T, checkForExistingBid bool, bidState mtypes.Bi
 
 	makeMocks(&scaffold)
 
+	for _, setup := range setupMocks {
+		setup(&scaffold)
+	}
+
 	scaffold.testAddr = testutil.AccAddress(t)
 
 	myProvider := &ptypes.Provider{
@@ -294,6 +308,14 @@ func Test_BidOrderPriceTooHigh(t *testing.T) {
 	// Should have called unreserve once, nothing happened after the bid
 	scaffold.cluster.AssertCalled(t, "Unreserve", scaffold.orderID, mock.Anything)
 
+	decisions := order.decisions.list(DecisionFilter{})
+	require.Len(t, decisions, 1)
+	require.Equal(t, scaffold.orderID, decisions[0].OrderID)
+	require.Equal(t, DecisionDecline, decisions[0].Decision)
+	require.Equal(t, ReasonPriceTooHigh, decisions[0].Reason)
+	require.NotNil(t, decisions[0].Price)
+	require.NotNil(t, decisions[0].MaxPrice)
+	require.True(t, decisions[0].MaxPrice.IsLT(*decisions[0].Price))
 }
 
 func Test_BidOrderAndThenClosedUnreserve(t *testing.T) {
@@ -704,7 +726,144 @@ func Test_ShouldntBidIfOrderAttrsDontMatch(t *testing.T) {
 
 	// Should not have called unreserve ever, as nothing was ever reserved
 	scaffold.cluster.AssertNotCalled(t, "Unreserve", scaffold.orderID, mock.Anything)
+
+	decisions := order.decisions.list(DecisionFilter{})
+	require.Len(t, decisions, 1)
+	require.Equal(t, DecisionDecline, decisions[0].Decision)
+	require.Equal(t, ReasonOrderAttributes, decisions[0].Reason)
 }
 
 // TODO - add test failing the call to Broadcast on TxClient and
 // and then confirm that the reservation is cancelled
+
+func Test_ShouldntBidIfOwnerDenied(t *testing.T) {
+	deploymentID := testutil.DeploymentID(t)
+
+	policy, err := NewTenantPolicy(TenantPolicyConfig{
+		Deny: []string{deploymentID.Owner},
+	})
+	require.NoError(t, err)
+
+	order, scaffold, _ := makeOrderForTestWithDeployment(t, deploymentID, &Config{TenantPolicy: policy})
+
+	<-order.lc.Done() // Stops whenever it figures it shouldn't bid
+
+	// Should not have called reserve ever
+	scaffold.cluster.AssertNotCalled(t, "Reserve", scaffold.orderID, mock.Anything)
+	scaffold.cluster.AssertNotCalled(t, "OwnerUsage", mock.Anything, mock.Anything)
+
+	decisions := order.decisions.list(DecisionFilter{})
+	require.Len(t, decisions, 1)
+	require.Equal(t, DecisionDecline, decisions[0].Decision)
+	require.Equal(t, ReasonTenantDenied, decisions[0].Reason)
+}
+
+func Test_ShouldntBidIfOwnerQuotaExceeded(t *testing.T) {
+	deploymentID := testutil.DeploymentID(t)
+
+	policy, err := NewTenantPolicy(TenantPolicyConfig{
+		Quota: TenantQuotaConfig{
+			Leases: 2,
+		},
+	})
+	require.NoError(t, err)
+
+	order, scaffold, _ := makeOrderForTestWithDeployment(t, deploymentID, &Config{TenantPolicy: policy}, func(s *orderTestScaffold) {
+		s.cluster.On("OwnerUsage", mock.Anything, deploymentID.Owner).Return(ctypes.InventoryOwnerUsage{Leases: 2}, nil)
+	})
+
+	<-order.lc.Done() // Stops whenever it figures it shouldn't bid
+
+	// Should not have called reserve ever
+	scaffold.cluster.AssertNotCalled(t, "Reserve", scaffold.orderID, mock.Anything)
+	scaffold.cluster.AssertCalled(t, "OwnerUsage", mock.Anything, deploymentID.Owner)
+
+	decisions := order.decisions.list(DecisionFilter{})
+	require.Len(t, decisions, 1)
+	require.Equal(t, ReasonTenantQuota, decisions[0].Reason)
+}
+
+func Test_BidOrderWithinOwnerQuota(t *testing.T) {
+	deploymentID := testutil.DeploymentID(t)
+
+	policy, err := NewTenantPolicy(TenantPolicyConfig{
+		Allow: []string{deploymentID.Owner},
+		Quota: TenantQuotaConfig{
+			Leases: 2,
+			CPU:    "1",
+		},
+	})
+	require.NoError(t, err)
+
+	order, scaffold, _ := makeOrderForTestWithDeployment(t, deploymentID, &Config{TenantPolicy: policy}, func(s *orderTestScaffold) {
+		s.cluster.On("OwnerUsage", mock.Anything, deploymentID.Owner).Return(ctypes.InventoryOwnerUsage{Leases: 1}, nil)
+	})
+
+	broadcast := testutil.ChannelWaitForValue(t, scaffold.broadcasts)
+	require.IsType(t, &mtypes.MsgCreateBid{}, broadcast)
+
+	order.lc.Shutdown(nil)
+}
+
+func Test_BidOrderAfterValidateWithinOwnerQuota(t *testing.T) {
+	deploymentID := testutil.DeploymentID(t)
+
+	policy, err := NewTenantPolicy(TenantPolicyConfig{
+		Quota: TenantQuotaConfig{
+			Leases: 1,
+		},
+	})
+	require.NoError(t, err)
+
+	var validate orderTestScaffold
+	makeMocks(&validate)
+	validate.cluster.On("OwnerUsage", mock.Anything, deploymentID.Owner).Return(ctypes.InventoryOwnerUsage{}, nil)
+
+	provider := &ptypes.Provider{Owner: testutil.AccAddress(t).String()}
+	svc, err := NewService(context.Background(), session.New(testutil.Logger(t), validate.client, provider, testBidCreatedAt),
+		validate.cluster, pubsub.NewBus(), waiter.NewNullWaiter(), Config{TenantPolicy: policy, MaxGroupVolumes: constants.DefaultMaxGroupVolumes})
+	require.NoError(t, err)
+	defer func() {
+		_ = svc.Close()
+	}()
+
+	// validate runs checks on group of the owner which is never ordered
+	group := &dtypes.Group{
+		GroupID:   dtypes.GroupID{Owner: deploymentID.Owner},
+		GroupSpec: *defaultGroupSpec(),
+	}
+
+	for i := 0; i < 2; i++ {
+		checks, err := svc.CheckGroup(context.Background(), group)
+		require.NoError(t, err)
+		require.Equal(t, ReasonTenantQuota, checks[len(checks)-1].Name)
+		require.True(t, checks[len(checks)-1].Passed)
+	}
+
+	order, scaffold, _ := makeOrderForTestWithDeployment(t, deploymentID, &Config{TenantPolicy: policy}, func(s *orderTestScaffold) {
+		s.cluster.On("OwnerUsage", mock.Anything, deploymentID.Owner).Return(ctypes.InventoryOwnerUsage{}, nil)
+	})
+
+	broadcast := testutil.ChannelWaitForValue(t, scaffold.broadcasts)
+	require.IsType(t, &mtypes.MsgCreateBid{}, broadcast)
+
+	order.lc.Shutdown(nil)
+}
+
+func Test_BidOrderReleasesSchedulerSlot(t *testing.T) {
+	order, scaffold, _ := makeOrderForTest(t, false, mtypes.BidStateInvalid, nil, &Config{
+		MaxInFlightOrders: 1,
+	}, testBidCreatedAt)
+
+	broadcast := testutil.ChannelWaitForValue(t, scaffold.broadcasts)
+	require.IsType(t, &mtypes.MsgCreateBid{}, broadcast)
+
+	// slot is given back once bid is placed
+	require.Eventually(t, func() bool {
+		queued, inflight := order.scheduler.depth()
+		return queued == 0 && inflight == 0
+	}, 10*time.Second, 50*time.Millisecond)
+
+	order.lc.Shutdown(nil)
+	scaffold.cluster.AssertCalled(t, "Unreserve", scaffold.orderID, mock.Anything)
+}
@@ -3,7 +3,9 @@ package bidengine
 import (
 	"context"
 	"crypto/rand"
+	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
@@ -15,6 +17,7 @@ import (
 	atypes "github.com/akash-network/akash-api/go/node/types/v1beta3"
 	"github.com/akash-network/node/sdl"
 
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	"github.com/akash-network/provider/cluster/util"
 )
 
@@ -30,6 +33,7 @@ type BidPricingStrategy interface {
 var (
 	errAllScalesZero               = errors.New("at least one bid price must be a non-zero number")
 	errNoPriceScaleForStorageClass = errors.New("no pricing configured for storage class")
+	errNoPriceScaleForGPU          = errors.New("no pricing configured for gpu")
 	errScaleNegative               = errors.New("scale price cannot be negative")
 )
 
@@ -71,9 +75,95 @@ func (ss Storage) AllLessThenOrEqual(val decimal.Decimal) bool {
 	return true
 }
 
+// GPUWildcard is the GPU scale key used when no vendor/model specific price matches
+const GPUWildcard = "*"
+
+// GPU maps per-unit GPU prices keyed by "<vendor>/<model>" or "<vendor>/<model>/<ram>".
+// "<vendor>/*" applies to any model of that vendor and GPUWildcard to any GPU.
+type GPU map[string]decimal.Decimal
+
+func (gs GPU) IsAnyZero() bool {
+	if len(gs) == 0 {
+		return true
+	}
+
+	for _, val := range gs {
+		if val.IsZero() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (gs GPU) IsAnyNegative() bool {
+	for _, val := range gs {
+		if val.IsNegative() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unitPrice returns price of single GPU unit requested with given attributes.
+// When several models are acceptable the most expensive one is used as the provider
+// may end up scheduling any of them.
+func (gs GPU) unitPrice(attrs atypes.Attributes) (decimal.Decimal, bool) {
+	var (
+		price decimal.Decimal
+		found bool
+	)
+
+	lookup := func(keys ...string) (decimal.Decimal, bool) {
+		for _, key := range keys {
+			if val, exists := gs[key]; exists {
+				return val, true
+			}
+		}
+
+		return decimal.Decimal{}, false
+	}
+
+	for _, attr := range attrs {
+		tokens := strings.Split(attr.Key, "/")
+
+		// vendor/nvidia/model/a100[/ram/80Gi]
+		if len(tokens) < 4 || tokens[0] != "vendor" || tokens[2] != "model" {
+			continue
+		}
+
+		vendor := tokens[1]
+		model := tokens[3]
+
+		keys := make([]string, 0, 4)
+		if len(tokens) == 6 && tokens[4] == "ram" {
+			keys = append(keys, fmt.Sprintf("%s/%s/%s", vendor, model, tokens[5]))
+		}
+		keys = append(keys, fmt.Sprintf("%s/%s", vendor, model), fmt.Sprintf("%s/%s", vendor, GPUWildcard), GPUWildcard)
+
+		val, exists := lookup(keys...)
+		if !exists {
+			return decimal.Decimal{}, false
+		}
+
+		if !found || val.GreaterThan(price) {
+			price = val
+			found = true
+		}
+	}
+
+	if !found {
+		price, found = lookup(GPUWildcard)
+	}
+
+	return price, found
+}
+
 type scalePricing struct {
 	cpuScale      decimal.Decimal
 	memoryScale   decimal.Decimal
+	gpuScale      GPU
 	storageScale  Storage
 	endpointScale decimal.Decimal
 	ipScale       decimal.Decimal
@@ -82,22 +172,29 @@ type scalePricing struct {
 func MakeScalePricing(
 	cpuScale decimal.Decimal,
 	memoryScale decimal.Decimal,
+	gpuScale GPU,
 	storageScale Storage,
 	endpointScale decimal.Decimal,
 	ipScale decimal.Decimal) (BidPricingStrategy, error) {
 
-	if cpuScale.IsZero() && memoryScale.IsZero() && storageScale.IsAnyZero() && endpointScale.IsZero() && ipScale.IsZero() {
+	if cpuScale.IsZero() && memoryScale.IsZero() && gpuScale.IsAnyZero() && storageScale.IsAnyZero() && endpointScale.IsZero() &&
+		ipScale.IsZero() {
 		return nil, errAllScalesZero
 	}
 
-	if cpuScale.IsNegative() || memoryScale.IsNegative() || storageScale.IsAnyNegative() || endpointScale.IsNegative() ||
-		ipScale.IsNegative() {
+	if cpuScale.IsNegative() || memoryScale.IsNegative() || gpuScale.IsAnyNegative() || storageScale.IsAnyNegative() ||
+		endpointScale.IsNegative() || ipScale.IsNegative() {
 		return nil, errScaleNegative
 	}
 
+	if gpuScale == nil {
+		gpuScale = make(GPU)
+	}
+
 	result := scalePricing{
 		cpuScale:      cpuScale,
 		memoryScale:   memoryScale,
+		gpuScale:      gpuScale,
 		storageScale:  storageScale,
 		endpointScale: endpointScale,
 		ipScale:       ipScale,
@@ -127,6 +224,7 @@ func (fp scalePricing) CalculatePrice(_ context.Context, req Request) (sdk.DecCo
 	// a possible configuration
 	cpuTotal := decimal.NewFromInt(0)
 	memoryTotal := decimal.NewFromInt(0)
+	gpuTotal := decimal.NewFromInt(0)
 	storageTotal := make(Storage)
 	denom := req.GSpec.Price().Denom
 
@@ -150,10 +248,27 @@ func (fp scalePricing) CalculatePrice(_ context.Context, req Request) (sdk.DecCo
 		memoryQuantity = memoryQuantity.Mul(groupCount)
 		memoryTotal = memoryTotal.Add(memoryQuantity)
 
+		if gpu := group.Resources.GPU; gpu != nil && gpu.Units.Value() > 0 {
+			unitPrice, exists := fp.gpuScale.unitPrice(gpu.Attributes)
+			if !exists {
+				return sdk.DecCoin{}, errors.Wrapf(errNoPriceScaleForGPU, "%v", gpu.Attributes)
+			}
+
+			gpuQuantity := decimal.NewFromBigInt(gpu.Units.Val.BigInt(), 0)
+			gpuQuantity = gpuQuantity.Mul(groupCount)
+			gpuTotal = gpuTotal.Add(gpuQuantity.Mul(unitPrice))
+		}
+
 		for _, storage := range group.Resources.Storage {
 			storageQuantity := decimal.NewFromBigInt(storage.Quantity.Val.BigInt(), 0)
 			storageQuantity = storageQuantity.Mul(groupCount)
 
+			// memory backed volumes are priced as memory
+			if ctypes.IsRAMStorage(storage.Attributes) {
+				memoryTotal = memoryTotal.Add(storageQuantity)
+				continue
+			}
+
 			storageClass := sdl.StorageEphemeral
 			attr := storage.Attributes.Find(sdl.StorageAttributePersistent)
 			if isPersistent, _ := attr.AsBool(); isPersistent {
@@ -200,6 +315,7 @@ func (fp scalePricing) CalculatePrice(_ context.Context, req Request) (sdk.DecCo
 	// and fit into an Int64
 	if cpuTotal.IsNegative() ||
 		memoryTotal.IsNegative() ||
+		gpuTotal.IsNegative() ||
 		storageTotal.IsAnyNegative() ||
 		endpointTotal.IsNegative() ||
 		ipTotal.IsNegative() {
@@ -208,6 +324,7 @@ func (fp scalePricing) CalculatePrice(_ context.Context, req Request) (sdk.DecCo
 
 	totalCost := cpuTotal
 	totalCost = totalCost.Add(memoryTotal)
+	totalCost = totalCost.Add(gpuTotal)
 	for _, total := range storageTotal {
 		totalCost = totalCost.Add(total)
 	}
@@ -0,0 +1,319 @@
+package bidengine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	PriceModifierFloor       = "floor"
+	PriceModifierCeiling     = "ceiling"
+	PriceModifierOwner       = "owner"
+	PriceModifierRound       = "round"
+	PriceModifierUtilization = "utilization"
+)
+
+const (
+	PriceRoundNearest = "nearest"
+	PriceRoundUp      = "up"
+	PriceRoundDown    = "down"
+)
+
+var (
+	errNoSuchPriceModifier      = errors.New("no such price modifier")
+	errPriceModifierInvalid     = errors.New("invalid price modifier configuration")
+	errPricingPipelineNoBase    = errors.New("pricing pipeline requires base strategy")
+	errCeilingPercentOutOfRange = errors.New("ceiling percent must be greater than zero")
+)
+
+// PriceModifier adjusts price computed by the previous stage of a pricing pipeline
+type PriceModifier interface {
+	ModifyPrice(ctx context.Context, req Request, price sdk.DecCoin) (sdk.DecCoin, error)
+}
+
+type pricingPipeline struct {
+	base      BidPricingStrategy
+	modifiers []PriceModifier
+}
+
+var _ BidPricingStrategy = (*pricingPipeline)(nil)
+
+// MakePricingPipeline chains base strategy with modifiers which are applied in given order
+func MakePricingPipeline(base BidPricingStrategy, modifiers ...PriceModifier) (BidPricingStrategy, error) {
+	if base == nil {
+		return nil, errPricingPipelineNoBase
+	}
+
+	result := &pricingPipeline{
+		base:      base,
+		modifiers: modifiers,
+	}
+
+	return result, nil
+}
+
+func (pp *pricingPipeline) CalculatePrice(ctx context.Context, req Request) (sdk.DecCoin, error) {
+	price, err := pp.base.CalculatePrice(ctx, req)
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+
+	for _, modifier := range pp.modifiers {
+		price, err = modifier.ModifyPrice(ctx, req, price)
+		if err != nil {
+			return sdk.DecCoin{}, err
+		}
+	}
+
+	if price.Amount.IsZero() {
+		return sdk.DecCoin{}, ErrBidZero
+	}
+
+	if price.Amount.IsNegative() || !price.Amount.LTE(sdk.MaxSortableDec) {
+		return sdk.DecCoin{}, ErrBidQuantityInvalid
+	}
+
+	return price, nil
+}
+
+type floorModifier struct {
+	amount sdk.Dec
+}
+
+// MakeFloorModifier raises price to the given minimum amount
+func MakeFloorModifier(amount sdk.Dec) (PriceModifier, error) {
+	if amount.IsNil() || amount.IsNegative() {
+		return nil, errScaleNegative
+	}
+
+	return floorModifier{amount: amount}, nil
+}
+
+func (fm floorModifier) ModifyPrice(_ context.Context, _ Request, price sdk.DecCoin) (sdk.DecCoin, error) {
+	if price.Amount.LT(fm.amount) {
+		return sdk.NewDecCoinFromDec(price.Denom, fm.amount), nil
+	}
+
+	return price, nil
+}
+
+type ceilingModifier struct {
+	percent sdk.Dec
+}
+
+// MakeCeilingModifier caps price at the given percentage of the max price set by the group spec
+func MakeCeilingModifier(percent sdk.Dec) (PriceModifier, error) {
+	if percent.IsNil() || !percent.IsPositive() {
+		return nil, errCeilingPercentOutOfRange
+	}
+
+	return ceilingModifier{percent: percent}, nil
+}
+
+func (cm ceilingModifier) ModifyPrice(_ context.Context, req Request, price sdk.DecCoin) (sdk.DecCoin, error) {
+	maxPrice := req.GSpec.Price()
+	if maxPrice.Denom != price.Denom {
+		return price, nil
+	}
+
+	ceiling := maxPrice.Amount.Mul(cm.percent).QuoInt64(100)
+	if price.Amount.GT(ceiling) {
+		return sdk.NewDecCoinFromDec(price.Denom, ceiling), nil
+	}
+
+	return price, nil
+}
+
+type ownerModifier struct {
+	multipliers map[string]sdk.Dec
+}
+
+// MakeOwnerModifier multiplies price for the listed owners,
+// values below 1 are discounts and values above 1 are surcharges
+func MakeOwnerModifier(multipliers map[string]sdk.Dec) (PriceModifier, error) {
+	for owner, multiplier := range multipliers {
+		if _, err := sdk.AccAddressFromBech32(owner); err != nil {
+			return nil, errors.Wrapf(errPriceModifierInvalid, "owner %q: %s", owner, err)
+		}
+
+		if multiplier.IsNil() || multiplier.IsNegative() {
+			return nil, errors.Wrapf(errScaleNegative, "owner %q", owner)
+		}
+	}
+
+	return ownerModifier{multipliers: multipliers}, nil
+}
+
+func (om ownerModifier) ModifyPrice(_ context.Context, req Request, price sdk.DecCoin) (sdk.DecCoin, error) {
+	multiplier, exists := om.multipliers[req.Owner]
+	if !exists {
+		return price, nil
+	}
+
+	return sdk.NewDecCoinFromDec(price.Denom, price.Amount.Mul(multiplier)), nil
+}
+
+type roundModifier struct {
+	precision int32
+	mode      string
+}
+
+// MakeRoundModifier rounds price to the given number of decimal places
+func MakeRoundModifier(precision int32, mode string) (PriceModifier, error) {
+	if precision < 0 || precision > sdk.Precision {
+		return nil, errors.Wrapf(errPriceModifierInvalid, "round precision must be within [0, %d]", sdk.Precision)
+	}
+
+	switch mode {
+	case "":
+		mode = PriceRoundNearest
+	case PriceRoundNearest, PriceRoundUp, PriceRoundDown:
+	default:
+		return nil, errors.Wrapf(errPriceModifierInvalid, "unknown round mode %q", mode)
+	}
+
+	return roundModifier{precision: precision, mode: mode}, nil
+}
+
+func (rm roundModifier) ModifyPrice(_ context.Context, _ Request, price sdk.DecCoin) (sdk.DecCoin, error) {
+	amount, err := decimal.NewFromString(price.Amount.String())
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+
+	switch rm.mode {
+	case PriceRoundUp:
+		amount = amount.RoundCeil(rm.precision)
+	case PriceRoundDown:
+		amount = amount.RoundFloor(rm.precision)
+	default:
+		amount = amount.Round(rm.precision)
+	}
+
+	res, err := sdk.NewDecFromStr(amount.StringFixed(rm.precision))
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+
+	return sdk.NewDecCoinFromDec(price.Denom, res), nil
+}
+
+// PriceModifierConfig describes single stage of the pricing pipeline
+type PriceModifierConfig struct {
+	Type      string            `json:"type" yaml:"type"`
+	Amount    string            `json:"amount,omitempty" yaml:"amount,omitempty"`
+	Percent   string            `json:"percent,omitempty" yaml:"percent,omitempty"`
+	Owners    map[string]string `json:"owners,omitempty" yaml:"owners,omitempty"`
+	Precision int32             `json:"precision,omitempty" yaml:"precision,omitempty"`
+	Mode      string            `json:"mode,omitempty" yaml:"mode,omitempty"`
+
+	Curve   []UtilizationPointConfig `json:"curve,omitempty" yaml:"curve,omitempty"`
+	Weights map[string]string        `json:"weights,omitempty" yaml:"weights,omitempty"`
+}
+
+// UtilizationPointConfig describes single point of the utilization curve
+type UtilizationPointConfig struct {
+	Utilization string `json:"utilization" yaml:"utilization"`
+	Multiplier  string `json:"multiplier" yaml:"multiplier"`
+}
+
+// PricingPipelineConfig is the pricing section of the provider config file
+type PricingPipelineConfig struct {
+	Modifiers []PriceModifierConfig `json:"modifiers,omitempty" yaml:"modifiers,omitempty"`
+}
+
+func parseDecField(field, val string) (sdk.Dec, error) {
+	res, err := sdk.NewDecFromStr(val)
+	if err != nil {
+		return sdk.Dec{}, errors.Wrapf(errPriceModifierInvalid, "%s %q: %s", field, val, err)
+	}
+
+	return res, nil
+}
+
+// MakePriceModifier creates modifier described by the config.
+// status is used by modifiers which depend on current cluster state
+func MakePriceModifier(cfg PriceModifierConfig, status ClusterStatusClient) (PriceModifier, error) {
+	switch cfg.Type {
+	case PriceModifierFloor:
+		amount, err := parseDecField("amount", cfg.Amount)
+		if err != nil {
+			return nil, err
+		}
+		return MakeFloorModifier(amount)
+	case PriceModifierCeiling:
+		percent, err := parseDecField("percent", cfg.Percent)
+		if err != nil {
+			return nil, err
+		}
+		return MakeCeilingModifier(percent)
+	case PriceModifierOwner:
+		multipliers := make(map[string]sdk.Dec, len(cfg.Owners))
+		for owner, val := range cfg.Owners {
+			multiplier, err := parseDecField(fmt.Sprintf("owners.%s", owner), val)
+			if err != nil {
+				return nil, err
+			}
+			multipliers[owner] = multiplier
+		}
+		return MakeOwnerModifier(multipliers)
+	case PriceModifierRound:
+		return MakeRoundModifier(cfg.Precision, cfg.Mode)
+	case PriceModifierUtilization:
+		curve := make(UtilizationCurve, 0, len(cfg.Curve))
+		for i, point := range cfg.Curve {
+			utilization, err := parseDecField(fmt.Sprintf("curve[%d].utilization", i), point.Utilization)
+			if err != nil {
+				return nil, err
+			}
+
+			multiplier, err := parseDecField(fmt.Sprintf("curve[%d].multiplier", i), point.Multiplier)
+			if err != nil {
+				return nil, err
+			}
+
+			curve = append(curve, UtilizationPoint{
+				Utilization: utilization,
+				Multiplier:  multiplier,
+			})
+		}
+
+		weights := make(map[string]sdk.Dec, len(cfg.Weights))
+		for resource, val := range cfg.Weights {
+			weight, err := parseDecField(fmt.Sprintf("weights.%s", resource), val)
+			if err != nil {
+				return nil, err
+			}
+			weights[resource] = weight
+		}
+
+		return MakeUtilizationModifier(status, curve, weights)
+	}
+
+	return nil, errors.Wrapf(errNoSuchPriceModifier, "%q", cfg.Type)
+}
+
+// MakePricingPipelineFromConfig wraps base strategy into pipeline with modifiers from the config.
+// base strategy is returned as is when config has no modifiers
+func MakePricingPipelineFromConfig(base BidPricingStrategy, cfg PricingPipelineConfig, status ClusterStatusClient) (BidPricingStrategy, error) {
+	if len(cfg.Modifiers) == 0 {
+		return base, nil
+	}
+
+	modifiers := make([]PriceModifier, 0, len(cfg.Modifiers))
+	for i, mcfg := range cfg.Modifiers {
+		modifier, err := MakePriceModifier(mcfg, status)
+		if err != nil {
+			return nil, errors.Wrapf(err, "pricing modifier #%d", i)
+		}
+
+		modifiers = append(modifiers, modifier)
+	}
+
+	return MakePricingPipeline(base, modifiers...)
+}
@@ -0,0 +1,402 @@
+package bidengine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	atypes "github.com/akash-network/akash-api/go/node/types/v1beta3"
+	"github.com/akash-network/node/testutil"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+type fixedPricing sdk.Dec
+
+func (fp fixedPricing) CalculatePrice(_ context.Context, req Request) (sdk.DecCoin, error) {
+	return sdk.NewDecCoinFromDec(req.GSpec.Price().Denom, sdk.Dec(fp)), nil
+}
+
+func mustDec(t *testing.T, val string) sdk.Dec {
+	t.Helper()
+	res, err := sdk.NewDecFromStr(val)
+	require.NoError(t, err)
+	return res
+}
+
+func Test_PricingPipelineRequiresBase(t *testing.T) {
+	pricing, err := MakePricingPipeline(nil)
+	require.ErrorIs(t, err, errPricingPipelineNoBase)
+	require.Nil(t, pricing)
+}
+
+func Test_PricingPipelineWithoutModifiers(t *testing.T) {
+	base := fixedPricing(mustDec(t, "7.5"))
+
+	pricing, err := MakePricingPipelineFromConfig(base, PricingPipelineConfig{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, base, pricing)
+}
+
+func Test_PricingPipelineFloor(t *testing.T) {
+	floor, err := MakeFloorModifier(mustDec(t, "10"))
+	require.NoError(t, err)
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	pricing, err := MakePricingPipeline(fixedPricing(mustDec(t, "3")), floor)
+	require.NoError(t, err)
+
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, mustDec(t, "10"), price.Amount)
+
+	pricing, err = MakePricingPipeline(fixedPricing(mustDec(t, "12")), floor)
+	require.NoError(t, err)
+
+	price, err = pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, mustDec(t, "12"), price.Amount)
+}
+
+func Test_PricingPipelineCeiling(t *testing.T) {
+	// max price of the default group spec is 23
+	ceiling, err := MakeCeilingModifier(mustDec(t, "50"))
+	require.NoError(t, err)
+
+	pricing, err := MakePricingPipeline(fixedPricing(mustDec(t, "20")), ceiling)
+	require.NoError(t, err)
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, mustDec(t, "11.5"), price.Amount)
+
+	_, err = MakeCeilingModifier(sdk.ZeroDec())
+	require.ErrorIs(t, err, errCeilingPercentOutOfRange)
+}
+
+func Test_PricingPipelineOwner(t *testing.T) {
+	discounted := testutil.AccAddress(t).String()
+	other := testutil.AccAddress(t).String()
+
+	owner, err := MakeOwnerModifier(map[string]sdk.Dec{
+		discounted: mustDec(t, "0.5"),
+	})
+	require.NoError(t, err)
+
+	pricing, err := MakePricingPipeline(fixedPricing(mustDec(t, "10")), owner)
+	require.NoError(t, err)
+
+	req := Request{
+		Owner: discounted,
+		GSpec: defaultGroupSpec(),
+	}
+
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, mustDec(t, "5"), price.Amount)
+
+	req.Owner = other
+	price, err = pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, mustDec(t, "10"), price.Amount)
+
+	_, err = MakeOwnerModifier(map[string]sdk.Dec{
+		"not-an-address": mustDec(t, "0.5"),
+	})
+	require.ErrorIs(t, err, errPriceModifierInvalid)
+}
+
+func Test_PricingPipelineRound(t *testing.T) {
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	tests := []struct {
+		mode      string
+		precision int32
+		expected  string
+	}{
+		{mode: "", precision: 0, expected: "1"},
+		{mode: PriceRoundUp, precision: 0, expected: "2"},
+		{mode: PriceRoundDown, precision: 0, expected: "1"},
+		{mode: PriceRoundDown, precision: 2, expected: "1.23"},
+		{mode: PriceRoundUp, precision: 2, expected: "1.24"},
+	}
+
+	for _, test := range tests {
+		round, err := MakeRoundModifier(test.precision, test.mode)
+		require.NoError(t, err)
+
+		pricing, err := MakePricingPipeline(fixedPricing(mustDec(t, "1.2345")), round)
+		require.NoError(t, err)
+
+		price, err := pricing.CalculatePrice(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, mustDec(t, test.expected), price.Amount, "mode %q precision %d", test.mode, test.precision)
+	}
+
+	_, err := MakeRoundModifier(0, "sideways")
+	require.ErrorIs(t, err, errPriceModifierInvalid)
+}
+
+func Test_PricingPipelineRejectsZero(t *testing.T) {
+	round, err := MakeRoundModifier(0, PriceRoundDown)
+	require.NoError(t, err)
+
+	pricing, err := MakePricingPipeline(fixedPricing(mustDec(t, "0.4")), round)
+	require.NoError(t, err)
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.Equal(t, ErrBidZero, err)
+}
+
+func Test_PricingPipelineFromConfig(t *testing.T) {
+	owner := testutil.AccAddress(t).String()
+
+	const cfgTemplate = `
+modifiers:
+  - type: owner
+    owners:
+      %s: "2"
+  - type: floor
+    amount: "5"
+  - type: ceiling
+    percent: "80"
+  - type: round
+    precision: 1
+    mode: down
+`
+	var cfg PricingPipelineConfig
+	err := yaml.Unmarshal([]byte(fmt.Sprintf(cfgTemplate, owner)), &cfg)
+	require.NoError(t, err)
+	require.Len(t, cfg.Modifiers, 4)
+
+	base, err := MakeScalePricing(decimal.NewFromInt(1), decimal.Zero, make(GPU), make(Storage), decimal.Zero, decimal.Zero)
+	require.NoError(t, err)
+
+	pricing, err := MakePricingPipelineFromConfig(base, cfg, nil)
+	require.NoError(t, err)
+
+	gspec := defaultGroupSpecCPUMem()
+
+	// cpu of 11 units priced at 1 is doubled for the owner and capped at 80% of 23
+	req := Request{
+		Owner: owner,
+		GSpec: gspec,
+	}
+
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, mustDec(t, "18.4"), price.Amount)
+
+	// cpu of 2 units priced at 1 is raised to the floor
+	gspec.Resources[0].Resources.CPU.Units.Val = sdk.NewInt(2)
+	req.Owner = testutil.AccAddress(t).String()
+
+	price, err = pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, mustDec(t, "5"), price.Amount)
+
+	_, err = MakePricingPipelineFromConfig(base, PricingPipelineConfig{
+		Modifiers: []PriceModifierConfig{{Type: "unknown"}},
+	}, nil)
+	require.ErrorIs(t, err, errNoSuchPriceModifier)
+}
+
+type fixedClusterStatus ctypes.InventoryStatus
+
+func (fs fixedClusterStatus) Status(_ context.Context) (*ctypes.Status, error) {
+	return &ctypes.Status{
+		Inventory: ctypes.InventoryStatus(fs),
+	}, nil
+}
+
+func Test_UtilizationCurve(t *testing.T) {
+	curve := UtilizationCurve{
+		{Utilization: mustDec(t, "0.2"), Multiplier: mustDec(t, "0.5")},
+		{Utilization: mustDec(t, "0.6"), Multiplier: mustDec(t, "1")},
+		{Utilization: mustDec(t, "1"), Multiplier: mustDec(t, "3")},
+	}
+
+	require.Equal(t, mustDec(t, "0.5"), curve.Multiplier(sdk.ZeroDec()))
+	require.Equal(t, mustDec(t, "0.5"), curve.Multiplier(mustDec(t, "0.2")))
+	require.Equal(t, mustDec(t, "0.75"), curve.Multiplier(mustDec(t, "0.4")))
+	require.Equal(t, mustDec(t, "1"), curve.Multiplier(mustDec(t, "0.6")))
+	require.Equal(t, mustDec(t, "2"), curve.Multiplier(mustDec(t, "0.8")))
+	require.Equal(t, mustDec(t, "3"), curve.Multiplier(sdk.OneDec()))
+}
+
+func Test_UtilizationModifierRejectsInvalidConfig(t *testing.T) {
+	status := fixedClusterStatus{}
+	curve := UtilizationCurve{{Utilization: sdk.ZeroDec(), Multiplier: sdk.OneDec()}}
+
+	_, err := MakeUtilizationModifier(nil, curve, nil)
+	require.ErrorIs(t, err, errUtilizationNoStatus)
+
+	_, err = MakeUtilizationModifier(status, nil, nil)
+	require.ErrorIs(t, err, errUtilizationCurveEmpty)
+
+	_, err = MakeUtilizationModifier(status, UtilizationCurve{{Utilization: mustDec(t, "1.1"), Multiplier: sdk.OneDec()}}, nil)
+	require.ErrorIs(t, err, errUtilizationCurve)
+
+	_, err = MakeUtilizationModifier(status, UtilizationCurve{
+		{Utilization: mustDec(t, "0.5"), Multiplier: sdk.OneDec()},
+		{Utilization: mustDec(t, "0.5"), Multiplier: sdk.OneDec()},
+	}, nil)
+	require.ErrorIs(t, err, errUtilizationCurve)
+
+	_, err = MakeUtilizationModifier(status, curve, map[string]sdk.Dec{"disk": sdk.OneDec()})
+	require.ErrorIs(t, err, errUtilizationWeight)
+}
+
+func Test_UtilizationModifier(t *testing.T) {
+	status := ctypes.InventoryStatus{
+		Active: []ctypes.InventoryMetricTotal{
+			{CPU: 1000, GPU: 3, Memory: 1000, StorageEphemeral: 1000},
+		},
+		Pending: []ctypes.InventoryMetricTotal{
+			{CPU: 1000, GPU: 0, Memory: 1000, StorageEphemeral: 1000},
+		},
+	}
+	status.Available.Nodes = []ctypes.InventoryNodeMetric{
+		{CPU: 3000, GPU: 1, Memory: 9000, StorageEphemeral: 9000},
+	}
+
+	// cpu is 50% utilized, memory and storage are 20% utilized, gpu 75% utilized
+	curve := UtilizationCurve{
+		{Utilization: sdk.ZeroDec(), Multiplier: sdk.OneDec()},
+		{Utilization: sdk.OneDec(), Multiplier: mustDec(t, "3")},
+	}
+
+	modifier, err := MakeUtilizationModifier(fixedClusterStatus(status), curve, map[string]sdk.Dec{
+		UtilizationResourceCPU: sdk.OneDec(),
+	})
+	require.NoError(t, err)
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	price, err := modifier.ModifyPrice(context.Background(), req, sdk.NewDecCoin("uakt", sdk.NewInt(10)))
+	require.NoError(t, err)
+	require.Equal(t, mustDec(t, "20"), price.Amount)
+
+	modifier, err = MakeUtilizationModifier(fixedClusterStatus(status), curve, map[string]sdk.Dec{
+		UtilizationResourceCPU:    sdk.OneDec(),
+		UtilizationResourceMemory: sdk.OneDec(),
+	})
+	require.NoError(t, err)
+
+	price, err = modifier.ModifyPrice(context.Background(), req, sdk.NewDecCoin("uakt", sdk.NewInt(10)))
+	require.NoError(t, err)
+	require.Equal(t, mustDec(t, "17"), price.Amount)
+
+	// gpu weight is ignored unless order requests gpu
+	modifier, err = MakeUtilizationModifier(fixedClusterStatus(status), curve, map[string]sdk.Dec{
+		UtilizationResourceGPU: sdk.OneDec(),
+	})
+	require.NoError(t, err)
+
+	price, err = modifier.ModifyPrice(context.Background(), req, sdk.NewDecCoin("uakt", sdk.NewInt(10)))
+	require.NoError(t, err)
+	require.Equal(t, mustDec(t, "10"), price.Amount)
+
+	req.GSpec.Resources[0].Resources.GPU.Units = atypes.NewResourceValue(1)
+
+	price, err = modifier.ModifyPrice(context.Background(), req, sdk.NewDecCoin("uakt", sdk.NewInt(10)))
+	require.NoError(t, err)
+	require.Equal(t, mustDec(t, "25"), price.Amount)
+}
+
+// countedClusterStatus reports how many times status was queried
+type countedClusterStatus struct {
+	fixedClusterStatus
+	calls int
+}
+
+func (cs *countedClusterStatus) Status(ctx context.Context) (*ctypes.Status, error) {
+	cs.calls++
+	return cs.fixedClusterStatus.Status(ctx)
+}
+
+func Test_UtilizationModifierReusesStatus(t *testing.T) {
+	status := &countedClusterStatus{}
+
+	modifier, err := MakeUtilizationModifier(status, UtilizationCurve{{Utilization: sdk.ZeroDec(), Multiplier: sdk.OneDec()}}, nil)
+	require.NoError(t, err)
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err = modifier.ModifyPrice(context.Background(), req, sdk.NewDecCoin("uakt", sdk.NewInt(10)))
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, status.calls)
+
+	// status older than max age is fetched again
+	modifier.(*utilizationModifier).maxAge = 0
+
+	_, err = modifier.ModifyPrice(context.Background(), req, sdk.NewDecCoin("uakt", sdk.NewInt(10)))
+	require.NoError(t, err)
+	require.Equal(t, 2, status.calls)
+}
+
+func Test_UtilizationModifierFromConfig(t *testing.T) {
+	const cfgData = `
+modifiers:
+  - type: utilization
+    curve:
+      - utilization: "0"
+        multiplier: "0.5"
+      - utilization: "1"
+        multiplier: "1.5"
+    weights:
+      cpu: "1"
+`
+	var cfg PricingPipelineConfig
+	err := yaml.Unmarshal([]byte(cfgData), &cfg)
+	require.NoError(t, err)
+
+	_, err = MakePricingPipelineFromConfig(fixedPricing(sdk.OneDec()), cfg, nil)
+	require.ErrorIs(t, err, errUtilizationNoStatus)
+
+	status := ctypes.InventoryStatus{}
+	status.Available.Nodes = []ctypes.InventoryNodeMetric{{CPU: 1000}}
+
+	pricing, err := MakePricingPipelineFromConfig(fixedPricing(mustDec(t, "10")), cfg, fixedClusterStatus(status))
+	require.NoError(t, err)
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, mustDec(t, "5"), price.Amount)
+}
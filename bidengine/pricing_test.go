@@ -17,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	"github.com/akash-network/provider/cluster/util"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -31,28 +32,28 @@ import (
 )
 
 func Test_ScalePricingRejectsAllZero(t *testing.T) {
-	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, make(Storage), decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, make(GPU), make(Storage), decimal.Zero, decimal.Zero)
 	require.NotNil(t, err)
 	require.Nil(t, pricing)
 }
 
 func Test_ScalePricingAcceptsOneForASingleScale(t *testing.T) {
-	pricing, err := MakeScalePricing(decimal.NewFromInt(1), decimal.Zero, make(Storage), decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.NewFromInt(1), decimal.Zero, make(GPU), make(Storage), decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
-	pricing, err = MakeScalePricing(decimal.Zero, decimal.NewFromInt(1), make(Storage), decimal.Zero, decimal.Zero)
+	pricing, err = MakeScalePricing(decimal.Zero, decimal.NewFromInt(1), make(GPU), make(Storage), decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
 	storageScale := Storage{
 		"": decimal.NewFromInt(1),
 	}
-	pricing, err = MakeScalePricing(decimal.Zero, decimal.Zero, storageScale, decimal.Zero, decimal.Zero)
+	pricing, err = MakeScalePricing(decimal.Zero, decimal.Zero, make(GPU), storageScale, decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
-	pricing, err = MakeScalePricing(decimal.Zero, decimal.Zero, make(Storage), decimal.NewFromInt(1), decimal.Zero)
+	pricing, err = MakeScalePricing(decimal.Zero, decimal.Zero, make(GPU), make(Storage), decimal.NewFromInt(1), decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 }
@@ -127,7 +128,7 @@ func Test_ScalePricingFailsOnOverflow(t *testing.T) {
 		sdl.StorageEphemeral: decimal.NewFromInt(1),
 	}
 
-	pricing, err := MakeScalePricing(decimal.New(math.MaxInt64, 2), decimal.Zero, storageScale, decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.New(math.MaxInt64, 2), decimal.Zero, make(GPU), storageScale, decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
@@ -145,7 +146,7 @@ func Test_ScalePricingFailsOnOverflow(t *testing.T) {
 func Test_ScalePricingOnCpu(t *testing.T) {
 	cpuScale := decimal.NewFromInt(22)
 
-	pricing, err := MakeScalePricing(cpuScale, decimal.Zero, make(Storage), decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(cpuScale, decimal.Zero, make(GPU), make(Storage), decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
@@ -169,7 +170,7 @@ func Test_ScalePricingOnCpu(t *testing.T) {
 func Test_ScalePricingOnMemory(t *testing.T) {
 	memoryScale := uint64(23)
 	memoryPrice := decimal.NewFromInt(int64(memoryScale)).Mul(decimal.NewFromInt(unit.Mi))
-	pricing, err := MakeScalePricing(decimal.Zero, memoryPrice, make(Storage), decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.Zero, memoryPrice, make(GPU), make(Storage), decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
@@ -192,7 +193,7 @@ func Test_ScalePricingOnMemory(t *testing.T) {
 func Test_ScalePricingOnMemoryLessThanOne(t *testing.T) {
 	memoryScale := uint64(1) // 1 uakt per megabyte
 	memoryPrice := decimal.NewFromInt(int64(memoryScale))
-	pricing, err := MakeScalePricing(decimal.Zero, memoryPrice, make(Storage), decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.Zero, memoryPrice, make(GPU), make(Storage), decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
@@ -243,7 +244,7 @@ func Test_ScalePricingOnStorage(t *testing.T) {
 		sdl.StorageEphemeral: decimal.NewFromInt(int64(storageScale)).Mul(decimal.NewFromInt(unit.Mi)),
 	}
 
-	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, storagePrice, decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, make(GPU), storagePrice, decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
@@ -260,13 +261,43 @@ func Test_ScalePricingOnStorage(t *testing.T) {
 	decNearly(t, price.Amount, int64(storageScale*storageQuantity))
 }
 
+func Test_ScalePricingOnRAMStorage(t *testing.T) {
+	memoryScale := uint64(23)
+	memoryPrice := decimal.NewFromInt(int64(memoryScale)).Mul(decimal.NewFromInt(unit.Mi))
+	storagePrice := Storage{
+		sdl.StorageEphemeral: decimal.NewFromInt(1000).Mul(decimal.NewFromInt(unit.Mi)),
+	}
+
+	pricing, err := MakeScalePricing(decimal.Zero, memoryPrice, make(GPU), storagePrice, decimal.Zero, decimal.Zero)
+	require.NoError(t, err)
+	require.NotNil(t, pricing)
+
+	gspec := defaultGroupSpec()
+	memoryQuantity := uint64(10000)
+	storageQuantity := uint64(4096)
+	gspec.Resources[0].Resources.Storage[0].Attributes = atypes.Attributes{
+		{Key: sdl.StorageAttributePersistent, Value: "false"},
+		{Key: sdl.StorageAttributeClass, Value: ctypes.StorageClassRAM},
+	}
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: gspec,
+	}
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+
+	// memory backed volume is priced as memory, not as ephemeral storage
+	decNearly(t, price.Amount, int64(memoryScale*(memoryQuantity+storageQuantity)))
+}
+
 func Test_ScalePricingByCountOfResources(t *testing.T) {
 	storageScale := uint64(3)
 	storagePrice := Storage{
 		sdl.StorageEphemeral: decimal.NewFromInt(int64(storageScale)).Mul(decimal.NewFromInt(unit.Mi)),
 	}
 
-	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, storagePrice, decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, make(GPU), storagePrice, decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
@@ -293,7 +324,7 @@ func Test_ScalePricingForIPs(t *testing.T) {
 	ipPriceInt := int64(testutil.RandRangeInt(100, 1000))
 	ipPrice := decimal.NewFromInt(ipPriceInt)
 
-	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, Storage{
+	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, make(GPU), Storage{
 		sdl.StorageEphemeral: decimal.Zero,
 	}, decimal.Zero, ipPrice)
 	require.NoError(t, err)
@@ -333,6 +364,117 @@ func Test_ScalePricingForIPs(t *testing.T) {
 	decNearly(t, price.Amount, 2*ipPriceInt)
 }
 
+func Test_ScalePricingOnGPU(t *testing.T) {
+	gpuScale := GPU{
+		"nvidia/a100":      decimal.NewFromInt(100),
+		"nvidia/a100/80Gi": decimal.NewFromInt(150),
+		"nvidia/*":         decimal.NewFromInt(70),
+		GPUWildcard:        decimal.NewFromInt(50),
+	}
+
+	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, gpuScale, make(Storage), decimal.Zero, decimal.Zero)
+	require.NoError(t, err)
+	require.NotNil(t, pricing)
+
+	gspec := defaultGroupSpecCPUMem()
+	gspec.Resources[0].Resources.Endpoints = nil
+	gspec.Resources[0].Resources.GPU = &atypes.GPU{
+		Units: atypes.NewResourceValue(2),
+	}
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: gspec,
+	}
+
+	tests := []struct {
+		attrs    atypes.Attributes
+		expected int64
+	}{
+		{
+			attrs:    nil,
+			expected: 2 * 50,
+		},
+		{
+			attrs:    atypes.Attributes{{Key: "vendor/nvidia/model/a100", Value: "true"}},
+			expected: 2 * 100,
+		},
+		{
+			attrs:    atypes.Attributes{{Key: "vendor/nvidia/model/a100/ram/80Gi", Value: "true"}},
+			expected: 2 * 150,
+		},
+		{
+			attrs:    atypes.Attributes{{Key: "vendor/nvidia/model/t4", Value: "true"}},
+			expected: 2 * 70,
+		},
+		{
+			attrs:    atypes.Attributes{{Key: "vendor/amd/model/mi100", Value: "true"}},
+			expected: 2 * 50,
+		},
+		{
+			attrs: atypes.Attributes{
+				{Key: "vendor/nvidia/model/t4", Value: "true"},
+				{Key: "vendor/nvidia/model/a100", Value: "true"},
+			},
+			expected: 2 * 100,
+		},
+	}
+
+	for _, test := range tests {
+		gspec.Resources[0].Resources.GPU.Attributes = test.attrs
+
+		price, err := pricing.CalculatePrice(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, testutil.AkashDecCoin(t, test.expected), price)
+	}
+
+	gspec.Resources[0].Count = 3
+	gspec.Resources[0].Resources.GPU.Attributes = atypes.Attributes{{Key: "vendor/nvidia/model/a100", Value: "true"}}
+
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, testutil.AkashDecCoin(t, 3*2*100), price)
+}
+
+func Test_ScalePricingOnGPUWithoutMatchingScale(t *testing.T) {
+	gpuScale := GPU{
+		"nvidia/a100": decimal.NewFromInt(100),
+	}
+
+	pricing, err := MakeScalePricing(decimal.NewFromInt(1), decimal.Zero, gpuScale, make(Storage), decimal.Zero, decimal.Zero)
+	require.NoError(t, err)
+	require.NotNil(t, pricing)
+
+	gspec := defaultGroupSpecCPUMem()
+	gspec.Resources[0].Resources.GPU = &atypes.GPU{
+		Units:      atypes.NewResourceValue(1),
+		Attributes: atypes.Attributes{{Key: "vendor/amd/model/mi100", Value: "true"}},
+	}
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: gspec,
+	}
+
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.ErrorIs(t, err, errNoPriceScaleForGPU)
+
+	// zero units of gpu do not require pricing
+	gspec.Resources[0].Resources.GPU.Units = atypes.NewResourceValue(0)
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+}
+
+func Test_ScalePricingRejectsNegativeGPU(t *testing.T) {
+	gpuScale := GPU{
+		GPUWildcard: decimal.NewFromInt(-1),
+	}
+
+	pricing, err := MakeScalePricing(decimal.NewFromInt(1), decimal.Zero, gpuScale, make(Storage), decimal.Zero, decimal.Zero)
+	require.ErrorIs(t, err, errScaleNegative)
+	require.Nil(t, pricing)
+}
+
 func Test_ScriptPricingRejectsEmptyStringForPath(t *testing.T) {
 	pricing, err := MakeShellScriptPricing("", 1, 30000*time.Millisecond)
 	require.NotNil(t, err)
@@ -714,6 +856,168 @@ func Test_ScriptPricingFromScript(t *testing.T) {
 	require.Equal(t, sdk.NewDecCoin("uakt", sdk.NewInt(expectedPrice)).String(), price.String())
 }
 
+func writeJSScript(t *testing.T, src string) string {
+	t.Helper()
+
+	scriptPath := path.Join(t.TempDir(), "test_script.js")
+	err := os.WriteFile(scriptPath, []byte(src), 0o600)
+	require.NoError(t, err)
+
+	return scriptPath
+}
+
+func Test_JSScriptPricingRejectsInvalidArguments(t *testing.T) {
+	pricing, err := MakeJSScriptPricing("", 1, time.Second)
+	require.ErrorIs(t, err, errPathEmpty)
+	require.Nil(t, pricing)
+
+	scriptPath := writeJSScript(t, "function calculatePrice(data, env) { return 1; }")
+
+	pricing, err = MakeJSScriptPricing(scriptPath, 0, time.Second)
+	require.ErrorIs(t, err, errProcessLimitZero)
+	require.Nil(t, pricing)
+
+	pricing, err = MakeJSScriptPricing(scriptPath, 1, 0)
+	require.ErrorIs(t, err, errProcessRuntimeLimitZero)
+	require.Nil(t, pricing)
+}
+
+func Test_JSScriptPricingRejectsInvalidScript(t *testing.T) {
+	pricing, err := MakeJSScriptPricing(writeJSScript(t, "function calculatePrice(data, env) {"), 1, time.Second)
+	require.Error(t, err)
+	require.Nil(t, pricing)
+
+	pricing, err = MakeJSScriptPricing(writeJSScript(t, "function price(data, env) { return 1; }"), 1, time.Second)
+	require.ErrorIs(t, err, errJSNoPriceFunction)
+	require.Nil(t, pricing)
+}
+
+func Test_JSScriptPricingResults(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+		err      error
+	}{
+		{src: "return 132;", expected: "132"},
+		{src: "return 1.5;", expected: "1.5"},
+		{src: `return "17.25";`, expected: "17.25"},
+		{src: "return 0;", err: ErrBidZero},
+		{src: "return -1;", err: ErrBidQuantityInvalid},
+		{src: "return NaN;", err: ErrBidQuantityInvalid},
+		{src: fmt.Sprintf(`return "%s0";`, sdk.MaxSortableDec.String()), err: ErrBidQuantityInvalid},
+		{src: "return {};", err: errJSInvalidResult},
+	}
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	for _, test := range tests {
+		src := fmt.Sprintf("function calculatePrice(data, env) { %s }", test.src)
+		pricing, err := MakeJSScriptPricing(writeJSScript(t, src), 1, time.Second)
+		require.NoError(t, err)
+
+		price, err := pricing.CalculatePrice(context.Background(), req)
+		if test.err != nil {
+			require.ErrorIs(t, err, test.err, test.src)
+			continue
+		}
+
+		require.NoError(t, err, test.src)
+		require.Equal(t, "uakt", price.Denom)
+		require.Equal(t, mustDec(t, test.expected), price.Amount, test.src)
+	}
+}
+
+func Test_JSScriptPricingFailsWhenScriptThrows(t *testing.T) {
+	scriptPath := writeJSScript(t, `function calculatePrice(data, env) { throw new Error("boom"); }`)
+
+	pricing, err := MakeJSScriptPricing(scriptPath, 1, time.Second)
+	require.NoError(t, err)
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "boom")
+}
+
+func Test_JSScriptPricingStopsByTimeout(t *testing.T) {
+	scriptPath := writeJSScript(t, "function calculatePrice(data, env) { for (;;) {} }")
+
+	pricing, err := MakeJSScriptPricing(scriptPath, 1, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = pricing.CalculatePrice(ctx, req)
+	require.Equal(t, context.Canceled, err)
+}
+
+func Test_JSScriptPricingReceivesScriptInput(t *testing.T) {
+	scriptPath := writeJSScript(t, `
+function calculatePrice(data, env) {
+	if (env.denom !== "uakt" || env.owner.length === 0) {
+		throw new Error("unexpected env");
+	}
+
+	const group = data[0];
+	return group.cpu + group.memory + group.storage[0].size + group.count + group.endpoint_quantity;
+}
+`)
+
+	pricing, err := MakeJSScriptPricing(scriptPath, 1, time.Second)
+	require.NoError(t, err)
+
+	gspec := defaultGroupSpec()
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: gspec,
+	}
+
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+
+	r := gspec.Resources[0]
+	expected := r.Resources.CPU.Units.Val.Int64() +
+		r.Resources.Memory.Quantity.Val.Int64() +
+		r.Resources.Storage[0].Quantity.Val.Int64() +
+		int64(r.Count) +
+		int64(len(r.Resources.Endpoints))
+
+	require.Equal(t, sdk.NewDec(expected), price.Amount)
+}
+
+func Test_JSScriptPricingExampleScript(t *testing.T) {
+	scriptPath, err := filepath.Abs("../script/scale_pricing.js")
+	require.NoError(t, err)
+
+	pricing, err := MakeJSScriptPricing(scriptPath, 1, time.Second)
+	require.NoError(t, err)
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, price.Amount.IsPositive())
+}
+
 func TestRationalToIntConversion(t *testing.T) {
 	x := ceilBigRatToBigInt(big.NewRat(0, 1))
 	require.Equal(t, big.NewInt(0), x)
@@ -0,0 +1,339 @@
+package bidengine
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/akash-network/node/sdl"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+const (
+	UtilizationResourceCPU     = "cpu"
+	UtilizationResourceMemory  = "memory"
+	UtilizationResourceGPU     = "gpu"
+	UtilizationResourceStorage = "storage"
+)
+
+// utilizationStatusMaxAge is how long cluster status is reused for pricing.
+// Utilization barely moves within few seconds while every priced order would query inventory otherwise
+const utilizationStatusMaxAge = 5 * time.Second
+
+var (
+	errUtilizationNoStatus   = errors.New("utilization pricing requires cluster status")
+	errUtilizationCurveEmpty = errors.New("utilization curve must have at least one point")
+	errUtilizationCurve      = errors.New("invalid utilization curve")
+	errUtilizationWeight     = errors.New("invalid utilization weight")
+)
+
+var (
+	utilizationMultiplierGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "provider_bid_price_utilization_multiplier",
+		Help: "Price multiplier at current cluster utilization of the resource type",
+	}, []string{"resource"})
+
+	utilizationMultiplierApplied = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "provider_bid_price_utilization_multiplier_applied",
+		Help:    "Utilization multipliers applied to prices of orders",
+		Buckets: []float64{0.5, 0.75, 1, 1.25, 1.5, 2, 3, 5},
+	})
+)
+
+// ClusterStatusClient provides current status of the cluster inventory
+type ClusterStatusClient interface {
+	Status(context.Context) (*ctypes.Status, error)
+}
+
+// UtilizationPoint maps cluster utilization in range [0, 1] to price multiplier
+type UtilizationPoint struct {
+	Utilization sdk.Dec
+	Multiplier  sdk.Dec
+}
+
+// UtilizationCurve is piecewise linear function defined by points sorted by utilization.
+// Utilization below first or above last point uses multiplier of that point
+type UtilizationCurve []UtilizationPoint
+
+func (uc UtilizationCurve) Multiplier(utilization sdk.Dec) sdk.Dec {
+	if utilization.LTE(uc[0].Utilization) {
+		return uc[0].Multiplier
+	}
+
+	for i := 1; i < len(uc); i++ {
+		right := uc[i]
+		if utilization.GT(right.Utilization) {
+			continue
+		}
+
+		left := uc[i-1]
+
+		// interpolate between left and right points
+		ratio := utilization.Sub(left.Utilization).Quo(right.Utilization.Sub(left.Utilization))
+		return left.Multiplier.Add(right.Multiplier.Sub(left.Multiplier).Mul(ratio))
+	}
+
+	return uc[len(uc)-1].Multiplier
+}
+
+type utilizationModifier struct {
+	status  ClusterStatusClient
+	curve   UtilizationCurve
+	weights map[string]sdk.Dec
+
+	lock      sync.Mutex
+	maxAge    time.Duration
+	inventory ctypes.InventoryStatus
+	fetchedAt time.Time
+}
+
+// MakeUtilizationModifier multiplies price by the curve value at current utilization of resources
+// requested by the order. Each resource type is evaluated separately and per-resource multipliers
+// are combined as weighted average. Resource types with zero or absent weight are ignored.
+func MakeUtilizationModifier(status ClusterStatusClient, curve UtilizationCurve, weights map[string]sdk.Dec) (PriceModifier, error) {
+	if status == nil {
+		return nil, errUtilizationNoStatus
+	}
+
+	if len(curve) == 0 {
+		return nil, errUtilizationCurveEmpty
+	}
+
+	sorted := make(UtilizationCurve, len(curve))
+	copy(sorted, curve)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Utilization.LT(sorted[j].Utilization)
+	})
+
+	for i, point := range sorted {
+		if point.Utilization.IsNil() || point.Utilization.IsNegative() || point.Utilization.GT(sdk.OneDec()) {
+			return nil, errors.Wrap(errUtilizationCurve, "utilization must be within [0, 1]")
+		}
+
+		if point.Multiplier.IsNil() || point.Multiplier.IsNegative() {
+			return nil, errors.Wrap(errUtilizationCurve, "multiplier cannot be negative")
+		}
+
+		if i > 0 && point.Utilization.Equal(sorted[i-1].Utilization) {
+			return nil, errors.Wrapf(errUtilizationCurve, "duplicate utilization %s", point.Utilization)
+		}
+	}
+
+	if len(weights) == 0 {
+		weights = map[string]sdk.Dec{
+			UtilizationResourceCPU:     sdk.OneDec(),
+			UtilizationResourceMemory:  sdk.OneDec(),
+			UtilizationResourceGPU:     sdk.OneDec(),
+			UtilizationResourceStorage: sdk.OneDec(),
+		}
+	}
+
+	for resource, weight := range weights {
+		switch resource {
+		case UtilizationResourceCPU, UtilizationResourceMemory, UtilizationResourceGPU, UtilizationResourceStorage:
+		default:
+			return nil, errors.Wrapf(errUtilizationWeight, "unknown resource %q", resource)
+		}
+
+		if weight.IsNil() || weight.IsNegative() {
+			return nil, errors.Wrapf(errUtilizationWeight, "weight of %q cannot be negative", resource)
+		}
+	}
+
+	res := &utilizationModifier{
+		status:  status,
+		curve:   sorted,
+		weights: weights,
+		maxAge:  utilizationStatusMaxAge,
+	}
+
+	return res, nil
+}
+
+// clusterUtilization holds used and total capacity per resource type
+type clusterUtilization struct {
+	used  map[string]sdk.Int
+	total map[string]sdk.Int
+}
+
+func storageUtilizationKey(class string) string {
+	return UtilizationResourceStorage + "/" + class
+}
+
+func newClusterUtilization(status ctypes.InventoryStatus) clusterUtilization {
+	cu := clusterUtilization{
+		used:  make(map[string]sdk.Int),
+		total: make(map[string]sdk.Int),
+	}
+
+	add := func(dst map[string]sdk.Int, key string, val sdk.Int) {
+		if curr, exists := dst[key]; exists {
+			val = val.Add(curr)
+		}
+		dst[key] = val
+	}
+
+	addMetric := func(metric ctypes.InventoryMetricTotal, active bool) {
+		vals := map[string]sdk.Int{
+			UtilizationResourceCPU:                      sdk.NewIntFromUint64(metric.CPU),
+			UtilizationResourceGPU:                      sdk.NewIntFromUint64(metric.GPU),
+			UtilizationResourceMemory:                   sdk.NewIntFromUint64(metric.Memory),
+			storageUtilizationKey(sdl.StorageEphemeral): sdk.NewIntFromUint64(metric.StorageEphemeral),
+		}
+
+		for class, size := range metric.Storage {
+			vals[storageUtilizationKey(class)] = sdk.NewInt(size)
+		}
+
+		for key, val := range vals {
+			add(cu.used, key, val)
+
+			// active leases are already deducted from available resources
+			// while pending reservations are not
+			if active {
+				add(cu.total, key, val)
+			}
+		}
+	}
+
+	for _, metric := range status.Active {
+		addMetric(metric, true)
+	}
+
+	for _, metric := range status.Pending {
+		addMetric(metric, false)
+	}
+
+	for _, node := range status.Available.Nodes {
+		add(cu.total, UtilizationResourceCPU, sdk.NewIntFromUint64(node.CPU))
+		add(cu.total, UtilizationResourceGPU, sdk.NewIntFromUint64(node.GPU))
+		add(cu.total, UtilizationResourceMemory, sdk.NewIntFromUint64(node.Memory))
+		add(cu.total, storageUtilizationKey(sdl.StorageEphemeral), sdk.NewIntFromUint64(node.StorageEphemeral))
+	}
+
+	for _, storage := range status.Available.Storage {
+		add(cu.total, storageUtilizationKey(storage.Class), sdk.NewInt(storage.Size))
+	}
+
+	return cu
+}
+
+// utilization returns share of used capacity of the resource type in range [0, 1].
+// Resource without any capacity is considered fully utilized
+func (cu clusterUtilization) utilization(key string) sdk.Dec {
+	total, exists := cu.total[key]
+	if !exists || !total.IsPositive() {
+		return sdk.OneDec()
+	}
+
+	used, exists := cu.used[key]
+	if !exists {
+		return sdk.ZeroDec()
+	}
+
+	res := sdk.NewDecFromInt(used).QuoInt(total)
+	if res.GT(sdk.OneDec()) {
+		res = sdk.OneDec()
+	}
+
+	return res
+}
+
+// requestedResources lists utilization keys of resource types requested by the order
+func requestedResources(req Request) map[string]string {
+	res := make(map[string]string)
+
+	for _, group := range req.GSpec.Resources {
+		if cpu := group.Resources.CPU; cpu != nil && !cpu.Units.Val.IsZero() {
+			res[UtilizationResourceCPU] = UtilizationResourceCPU
+		}
+
+		if memory := group.Resources.Memory; memory != nil && !memory.Quantity.Val.IsZero() {
+			res[UtilizationResourceMemory] = UtilizationResourceMemory
+		}
+
+		if gpu := group.Resources.GPU; gpu != nil && !gpu.Units.Val.IsZero() {
+			res[UtilizationResourceGPU] = UtilizationResourceGPU
+		}
+
+		for _, storage := range group.Resources.Storage {
+			class := sdl.StorageEphemeral
+			if cl, found := storage.Attributes.Find(sdl.StorageAttributeClass).AsString(); found && cl != "" {
+				class = cl
+			}
+
+			res[storageUtilizationKey(class)] = UtilizationResourceStorage
+		}
+	}
+
+	return res
+}
+
+// inventoryStatus returns cluster inventory status, reusing one fetched within maxAge.
+// Concurrent orders wait for the single fetch instead of querying inventory each
+func (um *utilizationModifier) inventoryStatus(ctx context.Context) (ctypes.InventoryStatus, error) {
+	um.lock.Lock()
+	defer um.lock.Unlock()
+
+	if !um.fetchedAt.IsZero() && time.Since(um.fetchedAt) < um.maxAge {
+		return um.inventory, nil
+	}
+
+	status, err := um.status.Status(ctx)
+	if err != nil {
+		return ctypes.InventoryStatus{}, err
+	}
+
+	if status.Inventory.Error != nil {
+		return ctypes.InventoryStatus{}, status.Inventory.Error
+	}
+
+	um.inventory = status.Inventory
+	um.fetchedAt = time.Now()
+
+	return um.inventory, nil
+}
+
+func (um *utilizationModifier) ModifyPrice(ctx context.Context, req Request, price sdk.DecCoin) (sdk.DecCoin, error) {
+	inventory, err := um.inventoryStatus(ctx)
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+
+	cu := newClusterUtilization(inventory)
+
+	weightTotal := sdk.ZeroDec()
+	multiplierTotal := sdk.ZeroDec()
+
+	for key, resource := range requestedResources(req) {
+		weight, exists := um.weights[resource]
+		if !exists || weight.IsZero() {
+			continue
+		}
+
+		multiplier := um.curve.Multiplier(cu.utilization(key))
+		utilizationMultiplierGauge.WithLabelValues(key).Set(multiplier.MustFloat64())
+
+		weightTotal = weightTotal.Add(weight)
+		multiplierTotal = multiplierTotal.Add(multiplier.Mul(weight))
+	}
+
+	if weightTotal.IsZero() {
+		utilizationMultiplierApplied.Observe(1)
+		return price, nil
+	}
+
+	multiplier := multiplierTotal.Quo(weightTotal)
+	utilizationMultiplierApplied.Observe(multiplier.MustFloat64())
+
+	return sdk.NewDecCoinFromDec(price.Denom, price.Amount.Mul(multiplier)), nil
+}
@@ -0,0 +1,229 @@
+package bidengine
+
+import (
+	"container/heap"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+var (
+	orderQueueGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "provider_order_queue_depth",
+		Help: "The number of orders waiting for reservation slot",
+	})
+
+	orderInFlightGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "provider_order_in_flight",
+		Help: "The number of orders reserving resources and bidding",
+	})
+)
+
+const (
+	gibibyte = 1024 * 1024 * 1024
+)
+
+// orderPriority decides which queued order is reserving resources first.
+// Orders of higher priority tenants go first, then ones paying more per resource unit
+type orderPriority struct {
+	tenant       int32
+	pricePerUnit sdk.Dec
+}
+
+func (op orderPriority) higherThan(other orderPriority) bool {
+	if op.tenant != other.tenant {
+		return op.tenant > other.tenant
+	}
+
+	return op.pricePerUnit.GT(other.pricePerUnit)
+}
+
+// groupResourceUnits sums resources requested by the group.
+// Unit is one cpu, one gpu or one GiB of memory or storage
+func groupResourceUnits(gspec *dtypes.GroupSpec) sdk.Dec {
+	total := ctypes.InventoryMetricTotal{
+		Storage: make(map[string]int64),
+	}
+
+	for _, res := range gspec.GetResources() {
+		total.AddResources(res)
+	}
+
+	storage := total.StorageEphemeral
+	for _, val := range total.Storage {
+		storage += uint64(val)
+	}
+
+	units := sdk.NewDecFromIntWithPrec(sdk.NewIntFromUint64(total.CPU), 3)
+	units = units.Add(sdk.NewDecFromInt(sdk.NewIntFromUint64(total.GPU)))
+	units = units.Add(sdk.NewDecFromInt(sdk.NewIntFromUint64(total.Memory)).QuoInt64(gibibyte))
+	units = units.Add(sdk.NewDecFromInt(sdk.NewIntFromUint64(storage)).QuoInt64(gibibyte))
+
+	return units
+}
+
+func makeOrderPriority(group *dtypes.Group, policy *TenantPolicy) orderPriority {
+	res := orderPriority{
+		pricePerUnit: group.GroupSpec.Price().Amount,
+	}
+
+	if units := groupResourceUnits(&group.GroupSpec); units.IsPositive() {
+		res.pricePerUnit = res.pricePerUnit.Quo(units)
+	}
+
+	if policy != nil {
+		res.tenant = policy.priority(group.GroupID.Owner)
+	}
+
+	return res
+}
+
+// orderTicket is the place of the order in the scheduler queue.
+// ready is closed once order is allowed to reserve resources
+type orderTicket struct {
+	orderID  mtypes.OrderID
+	priority orderPriority
+	seq      uint64
+	index    int
+	granted  bool
+	released bool
+	ready    chan struct{}
+}
+
+type orderQueue []*orderTicket
+
+var _ heap.Interface = (*orderQueue)(nil)
+
+func (q orderQueue) Len() int {
+	return len(q)
+}
+
+func (q orderQueue) Less(i, j int) bool {
+	if q[i].priority.higherThan(q[j].priority) {
+		return true
+	}
+
+	if q[j].priority.higherThan(q[i].priority) {
+		return false
+	}
+
+	// first come, first served within the same priority
+	return q[i].seq < q[j].seq
+}
+
+func (q orderQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *orderQueue) Push(x interface{}) {
+	ticket := x.(*orderTicket)
+	ticket.index = len(*q)
+	*q = append(*q, ticket)
+}
+
+func (q *orderQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	ticket := old[n-1]
+	old[n-1] = nil
+	ticket.index = -1
+	*q = old[:n-1]
+	return ticket
+}
+
+// orderScheduler limits number of orders reserving resources and bidding at the same time.
+// Orders over the limit are queued and let through in priority order
+type orderScheduler struct {
+	lock     sync.Mutex
+	limit    int
+	inflight int
+	seq      uint64
+	queue    orderQueue
+}
+
+// newOrderScheduler creates scheduler. Zero or negative limit means no limit
+func newOrderScheduler(limit int) *orderScheduler {
+	return &orderScheduler{
+		limit: limit,
+	}
+}
+
+func (s *orderScheduler) hasCapacity() bool {
+	return s.limit <= 0 || s.inflight < s.limit
+}
+
+// schedule queues the order. Caller must release ticket once done with it
+func (s *orderScheduler) schedule(orderID mtypes.OrderID, priority orderPriority) *orderTicket {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.seq++
+
+	ticket := &orderTicket{
+		orderID:  orderID,
+		priority: priority,
+		seq:      s.seq,
+		index:    -1,
+		ready:    make(chan struct{}),
+	}
+
+	heap.Push(&s.queue, ticket)
+	s.dispatch()
+
+	return ticket
+}
+
+// release gives reservation slot back or removes order from the queue if slot was not yet granted.
+// It is safe to release ticket multiple times
+func (s *orderScheduler) release(ticket *orderTicket) {
+	if ticket == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if ticket.released {
+		return
+	}
+
+	ticket.released = true
+
+	if ticket.granted {
+		s.inflight--
+	} else if ticket.index >= 0 {
+		heap.Remove(&s.queue, ticket.index)
+	}
+
+	s.dispatch()
+}
+
+func (s *orderScheduler) dispatch() {
+	for s.hasCapacity() && s.queue.Len() > 0 {
+		ticket := heap.Pop(&s.queue).(*orderTicket)
+		ticket.granted = true
+		s.inflight++
+		close(ticket.ready)
+	}
+
+	orderQueueGauge.Set(float64(s.queue.Len()))
+	orderInFlightGauge.Set(float64(s.inflight))
+}
+
+// depth returns number of queued and in-flight orders
+func (s *orderScheduler) depth() (uint32, uint32) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return uint32(s.queue.Len()), uint32(s.inflight)
+}
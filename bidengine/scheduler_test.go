@@ -0,0 +1,140 @@
+package bidengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	atypes "github.com/akash-network/akash-api/go/node/types/v1beta3"
+	"github.com/akash-network/node/testutil"
+)
+
+func isReady(ticket *orderTicket) bool {
+	select {
+	case <-ticket.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+func Test_OrderSchedulerLimitsInFlight(t *testing.T) {
+	s := newOrderScheduler(1)
+
+	low := orderPriority{pricePerUnit: sdk.NewDec(1)}
+	high := orderPriority{pricePerUnit: sdk.NewDec(10)}
+	vip := orderPriority{tenant: 1, pricePerUnit: sdk.NewDec(1)}
+
+	first := s.schedule(testutil.OrderID(t), low)
+	require.True(t, isReady(first))
+
+	queuedLow := s.schedule(testutil.OrderID(t), low)
+	queuedHigh := s.schedule(testutil.OrderID(t), high)
+	queuedVip := s.schedule(testutil.OrderID(t), vip)
+	queuedLow2 := s.schedule(testutil.OrderID(t), low)
+
+	queued, inflight := s.depth()
+	require.Equal(t, uint32(4), queued)
+	require.Equal(t, uint32(1), inflight)
+
+	// tenant priority goes first
+	s.release(first)
+	require.True(t, isReady(queuedVip))
+	require.False(t, isReady(queuedHigh))
+
+	// releasing twice does not free extra slot
+	s.release(first)
+	require.False(t, isReady(queuedHigh))
+
+	// then higher price per unit
+	s.release(queuedVip)
+	require.True(t, isReady(queuedHigh))
+
+	// queued order leaving does not take a slot
+	s.release(queuedLow2)
+	queued, inflight = s.depth()
+	require.Equal(t, uint32(1), queued)
+	require.Equal(t, uint32(1), inflight)
+
+	// first come first served within the same priority
+	s.release(queuedHigh)
+	require.True(t, isReady(queuedLow))
+	require.False(t, isReady(queuedLow2))
+
+	s.release(queuedLow)
+	queued, inflight = s.depth()
+	require.Equal(t, uint32(0), queued)
+	require.Equal(t, uint32(0), inflight)
+}
+
+func Test_OrderSchedulerUnlimited(t *testing.T) {
+	s := newOrderScheduler(0)
+
+	tickets := make([]*orderTicket, 0, 10)
+	for i := 0; i < 10; i++ {
+		ticket := s.schedule(mtypes.OrderID{}, orderPriority{pricePerUnit: sdk.ZeroDec()})
+		require.True(t, isReady(ticket))
+		tickets = append(tickets, ticket)
+	}
+
+	_, inflight := s.depth()
+	require.Equal(t, uint32(10), inflight)
+
+	for _, ticket := range tickets {
+		s.release(ticket)
+	}
+
+	_, inflight = s.depth()
+	require.Equal(t, uint32(0), inflight)
+}
+
+func makeGroupForPriority(t *testing.T, cpu uint64, memory uint64, price int64) *dtypes.Group {
+	return &dtypes.Group{
+		GroupID: dtypes.MakeGroupID(testutil.DeploymentID(t), 1),
+		GroupSpec: dtypes.GroupSpec{
+			Resources: []dtypes.Resource{
+				{
+					Resources: atypes.ResourceUnits{
+						CPU: &atypes.CPU{
+							Units: atypes.NewResourceValue(cpu),
+						},
+						Memory: &atypes.Memory{
+							Quantity: atypes.NewResourceValue(memory),
+						},
+					},
+					Count: 1,
+					Price: sdk.NewInt64DecCoin(testutil.CoinDenom, price),
+				},
+			},
+		},
+	}
+}
+
+func Test_OrderPriority(t *testing.T) {
+	// 2 cpu + 2Gi memory
+	group := makeGroupForPriority(t, 2000, 2*gibibyte, 100)
+	require.Equal(t, sdk.NewDec(4), groupResourceUnits(&group.GroupSpec))
+
+	priority := makeOrderPriority(group, nil)
+	require.Equal(t, int32(0), priority.tenant)
+	require.Equal(t, sdk.NewDec(25), priority.pricePerUnit)
+
+	// same price for more resources is less valuable
+	bigger := makeOrderPriority(makeGroupForPriority(t, 4000, 4*gibibyte, 100), nil)
+	require.True(t, priority.higherThan(bigger))
+	require.False(t, bigger.higherThan(priority))
+
+	policy, err := NewTenantPolicy(TenantPolicyConfig{
+		Priorities: map[string]int32{
+			group.GroupID.Owner: 5,
+		},
+	})
+	require.NoError(t, err)
+
+	priority = makeOrderPriority(group, policy)
+	require.Equal(t, int32(5), priority.tenant)
+}
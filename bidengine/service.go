@@ -11,6 +11,7 @@ import (
 
 	sdkquery "github.com/cosmos/cosmos-sdk/types/query"
 
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
 	"github.com/akash-network/node/pubsub"
 	mquery "github.com/akash-network/node/x/market/query"
@@ -46,6 +47,8 @@ var (
 // Service handles bidding on orders.
 type Service interface {
 	StatusClient
+	DecisionClient
+	GroupCheckClient
 	Close() error
 	Done() <-chan struct{}
 }
@@ -73,17 +76,19 @@ func NewService(ctx context.Context, session session.Session, cluster cluster.Cl
 	}
 
 	s := &service{
-		session:  session,
-		cluster:  cluster,
-		bus:      bus,
-		sub:      sub,
-		statusch: make(chan chan<- *Status),
-		orders:   make(map[string]*order),
-		drainch:  make(chan *order),
-		lc:       lifecycle.New(),
-		cfg:      cfg,
-		pass:     providerAttrService,
-		waiter:   waiter,
+		session:   session,
+		cluster:   cluster,
+		bus:       bus,
+		sub:       sub,
+		statusch:  make(chan chan<- *Status),
+		orders:    make(map[string]*order),
+		drainch:   make(chan *order),
+		lc:        lifecycle.New(),
+		cfg:       cfg,
+		pass:      providerAttrService,
+		waiter:    waiter,
+		decisions: newDecisionLog(cfg.DecisionLogSize),
+		scheduler: newOrderScheduler(cfg.MaxInFlightOrders),
 	}
 
 	go s.lc.WatchContext(ctx)
@@ -107,7 +112,9 @@ type service struct {
 	lc   lifecycle.Lifecycle
 	pass *providerAttrSignatureService
 
-	waiter waiter.OperatorWaiter
+	waiter    waiter.OperatorWaiter
+	decisions *decisionLog
+	scheduler *orderScheduler
 }
 
 func (s *service) Close() error {
@@ -140,6 +147,29 @@ func (s *service) Status(ctx context.Context) (*Status, error) {
 	}
 }
 
+func (s *service) Decisions(_ context.Context, filter DecisionFilter) ([]BidDecision, error) {
+	return s.decisions.list(filter), nil
+}
+
+// CheckGroup runs all checks bid engine applies to orders and reports result of each one
+func (s *service) CheckGroup(ctx context.Context, group *dtypes.Group) ([]GroupCheck, error) {
+	select {
+	case <-s.lc.Done():
+		return nil, ErrNotRunning
+	default:
+	}
+
+	checker := groupChecker{
+		cfg:     s.cfg,
+		session: s.session,
+		pass:    s.pass,
+		usage:   s.cluster,
+		dryRun:  true,
+	}
+
+	return checker.run(ctx, group, false)
+}
+
 func (s *service) updateOrderManagerGauge() {
 	orderManagerGauge.Set(float64(len(s.orders)))
 }
@@ -198,8 +228,11 @@ loop:
 				s.orders[key] = order
 			}
 		case ch := <-s.statusch:
+			queued, inflight := s.scheduler.depth()
 			ch <- &Status{
-				Orders: uint32(len(s.orders)),
+				Orders:     uint32(len(s.orders)),
+				QueueDepth: queued,
+				InFlight:   inflight,
 			}
 		case order := <-s.drainch:
 			// child done
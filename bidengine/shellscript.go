@@ -113,9 +113,7 @@ func parseStorage(resource atypes.Volumes) []storageElement {
 	return res
 }
 
-func (ssp shellScriptPricing) CalculatePrice(ctx context.Context, req Request) (sdk.DecCoin, error) {
-	buf := &bytes.Buffer{}
-
+func makeDataForScript(req Request) []dataForScriptElement {
 	dataForScript := make([]dataForScriptElement, len(req.GSpec.Resources))
 
 	// iterate over everything & sum it up
@@ -139,6 +137,36 @@ func (ssp shellScriptPricing) CalculatePrice(ctx context.Context, req Request) (
 		}
 	}
 
+	return dataForScript
+}
+
+// parseScriptPrice validates price produced by the pricing script
+func parseScriptPrice(denom string, val string) (sdk.DecCoin, error) {
+	price, err := sdk.NewDecFromStr(val)
+	if err != nil {
+		return sdk.DecCoin{}, ErrBidQuantityInvalid
+	}
+
+	if price.IsZero() {
+		return sdk.DecCoin{}, ErrBidZero
+	}
+
+	if price.IsNegative() {
+		return sdk.DecCoin{}, ErrBidQuantityInvalid
+	}
+
+	if !price.LTE(sdk.MaxSortableDec) {
+		return sdk.DecCoin{}, ErrBidQuantityInvalid
+	}
+
+	return sdk.NewDecCoinFromDec(denom, price), nil
+}
+
+func (ssp shellScriptPricing) CalculatePrice(ctx context.Context, req Request) (sdk.DecCoin, error) {
+	buf := &bytes.Buffer{}
+
+	dataForScript := makeDataForScript(req)
+
 	encoder := json.NewEncoder(buf)
 	err := encoder.Encode(dataForScript)
 	if err != nil {
@@ -188,22 +216,5 @@ func (ssp shellScriptPricing) CalculatePrice(ctx context.Context, req Request) (
 		return sdk.DecCoin{}, fmt.Errorf("%w: script failure %s", err, stderrBuf.String())
 	}
 
-	price, err := sdk.NewDecFromStr(priceNumber.String())
-	if err != nil {
-		return sdk.DecCoin{}, ErrBidQuantityInvalid
-	}
-
-	if price.IsZero() {
-		return sdk.DecCoin{}, ErrBidZero
-	}
-
-	if price.IsNegative() {
-		return sdk.DecCoin{}, ErrBidQuantityInvalid
-	}
-
-	if !price.LTE(sdk.MaxSortableDec) {
-		return sdk.DecCoin{}, ErrBidQuantityInvalid
-	}
-
-	return sdk.NewDecCoinFromDec(denom, price), nil
+	return parseScriptPrice(denom, priceNumber.String())
 }
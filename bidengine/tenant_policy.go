@@ -0,0 +1,335 @@
+package bidengine
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+// Reason codes of orders declined by tenant policy
+const (
+	ReasonTenantDenied     = "tenant-denied"
+	ReasonTenantNotAllowed = "tenant-not-allowed"
+	ReasonTenantQuota      = "tenant-quota"
+)
+
+var (
+	errTenantPolicyInvalid = errors.New("invalid tenant policy")
+)
+
+// TenantQuotaConfig describes limits applied to the single owner.
+// cpu and memory accept kubernetes quantities, e.g. "500m" or "64Gi".
+// Empty or zero values are not limited
+type TenantQuotaConfig struct {
+	Leases uint32 `json:"leases,omitempty" yaml:"leases,omitempty"`
+	CPU    string `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	Memory string `json:"memory,omitempty" yaml:"memory,omitempty"`
+	GPU    uint64 `json:"gpu,omitempty" yaml:"gpu,omitempty"`
+}
+
+// TenantPolicyConfig is the tenants section of the provider config file
+type TenantPolicyConfig struct {
+	// Allow when not empty limits bidding to the listed owners only
+	Allow []string `json:"allow,omitempty" yaml:"allow,omitempty"`
+	// Deny lists owners provider never bids on
+	Deny []string `json:"deny,omitempty" yaml:"deny,omitempty"`
+	// Quota is applied to every owner not listed in Owners
+	Quota TenantQuotaConfig `json:"quota,omitempty" yaml:"quota,omitempty"`
+	// Owners overrides quota of the listed owners
+	Owners map[string]TenantQuotaConfig `json:"owners,omitempty" yaml:"owners,omitempty"`
+	// Priorities of the listed owners when orders are queued for reservation.
+	// Higher value goes first, owners not listed have zero priority
+	Priorities map[string]int32 `json:"priorities,omitempty" yaml:"priorities,omitempty"`
+}
+
+// TenantQuota is the limit of resources reserved by the single owner.
+// CPU is in millicpu and Memory in bytes. Zero value fields are not limited
+type TenantQuota struct {
+	Leases uint32
+	CPU    uint64
+	Memory uint64
+	GPU    uint64
+}
+
+func (tq TenantQuota) isZero() bool {
+	return tq == TenantQuota{}
+}
+
+// exceeded returns true when usage along with requested resources goes over the quota
+func (tq TenantQuota) exceeded(usage ctypes.InventoryOwnerUsage, requested ctypes.InventoryMetricTotal) bool {
+	if tq.Leases != 0 && usage.Leases+1 > tq.Leases {
+		return true
+	}
+
+	if tq.CPU != 0 && usage.CPU+requested.CPU > tq.CPU {
+		return true
+	}
+
+	if tq.Memory != 0 && usage.Memory+requested.Memory > tq.Memory {
+		return true
+	}
+
+	if tq.GPU != 0 && usage.GPU+requested.GPU > tq.GPU {
+		return true
+	}
+
+	return false
+}
+
+func parseTenantQuota(cfg TenantQuotaConfig) (TenantQuota, error) {
+	res := TenantQuota{
+		Leases: cfg.Leases,
+		GPU:    cfg.GPU,
+	}
+
+	if cfg.CPU != "" {
+		val, err := resource.ParseQuantity(cfg.CPU)
+		if err != nil {
+			return TenantQuota{}, errors.Wrapf(errTenantPolicyInvalid, "cpu %q: %s", cfg.CPU, err)
+		}
+
+		if val.Sign() < 0 {
+			return TenantQuota{}, errors.Wrapf(errTenantPolicyInvalid, "cpu %q cannot be negative", cfg.CPU)
+		}
+
+		res.CPU = uint64(val.MilliValue())
+	}
+
+	if cfg.Memory != "" {
+		val, err := resource.ParseQuantity(cfg.Memory)
+		if err != nil {
+			return TenantQuota{}, errors.Wrapf(errTenantPolicyInvalid, "memory %q: %s", cfg.Memory, err)
+		}
+
+		if val.Sign() < 0 {
+			return TenantQuota{}, errors.Wrapf(errTenantPolicyInvalid, "memory %q cannot be negative", cfg.Memory)
+		}
+
+		res.Memory = uint64(val.Value())
+	}
+
+	return res, nil
+}
+
+type tenantPolicyState struct {
+	allow      map[string]struct{}
+	deny       map[string]struct{}
+	quota      TenantQuota
+	owners     map[string]TenantQuota
+	priorities map[string]int32
+}
+
+// TenantPolicy decides which owners provider bids on and how much resources each owner may hold.
+// It is safe for concurrent use and can be updated while bid engine is running
+type TenantPolicy struct {
+	lock  sync.RWMutex
+	state tenantPolicyState
+
+	// quotaLock serializes quota checks, so concurrent orders of the same owner
+	// cannot pass the check on the same usage. Groups which passed the check
+	// and are not reserved in inventory yet are counted in pending
+	quotaLock sync.Mutex
+	pending   map[dtypes.GroupID]pendingQuota
+}
+
+type pendingQuota struct {
+	owner     string
+	requested ctypes.InventoryMetricTotal
+}
+
+// NewTenantPolicy creates policy from the config
+func NewTenantPolicy(cfg TenantPolicyConfig) (*TenantPolicy, error) {
+	tp := &TenantPolicy{
+		pending: make(map[dtypes.GroupID]pendingQuota),
+	}
+
+	if err := tp.Update(cfg); err != nil {
+		return nil, err
+	}
+
+	return tp, nil
+}
+
+func parseOwnerList(field string, owners []string) (map[string]struct{}, error) {
+	res := make(map[string]struct{}, len(owners))
+
+	for _, owner := range owners {
+		if _, err := sdk.AccAddressFromBech32(owner); err != nil {
+			return nil, errors.Wrapf(errTenantPolicyInvalid, "%s owner %q: %s", field, owner, err)
+		}
+
+		res[owner] = struct{}{}
+	}
+
+	return res, nil
+}
+
+// Update replaces policy with the config. Policy is left intact if config is invalid
+func (tp *TenantPolicy) Update(cfg TenantPolicyConfig) error {
+	allow, err := parseOwnerList("allow", cfg.Allow)
+	if err != nil {
+		return err
+	}
+
+	deny, err := parseOwnerList("deny", cfg.Deny)
+	if err != nil {
+		return err
+	}
+
+	quota, err := parseTenantQuota(cfg.Quota)
+	if err != nil {
+		return err
+	}
+
+	owners := make(map[string]TenantQuota, len(cfg.Owners))
+	for owner, qcfg := range cfg.Owners {
+		if _, err = sdk.AccAddressFromBech32(owner); err != nil {
+			return errors.Wrapf(errTenantPolicyInvalid, "quota owner %q: %s", owner, err)
+		}
+
+		if owners[owner], err = parseTenantQuota(qcfg); err != nil {
+			return errors.Wrapf(err, "owner %q", owner)
+		}
+	}
+
+	priorities := make(map[string]int32, len(cfg.Priorities))
+	for owner, priority := range cfg.Priorities {
+		if _, err = sdk.AccAddressFromBech32(owner); err != nil {
+			return errors.Wrapf(errTenantPolicyInvalid, "priority owner %q: %s", owner, err)
+		}
+
+		priorities[owner] = priority
+	}
+
+	tp.lock.Lock()
+	defer tp.lock.Unlock()
+
+	tp.state = tenantPolicyState{
+		allow:      allow,
+		deny:       deny,
+		quota:      quota,
+		owners:     owners,
+		priorities: priorities,
+	}
+
+	return nil
+}
+
+// denied returns true when the owner is on the deny list
+func (tp *TenantPolicy) denied(owner string) bool {
+	tp.lock.RLock()
+	defer tp.lock.RUnlock()
+
+	_, denied := tp.state.deny[owner]
+
+	return denied
+}
+
+// allowed returns true when allow list is empty or the owner is on it
+func (tp *TenantPolicy) allowed(owner string) bool {
+	tp.lock.RLock()
+	defer tp.lock.RUnlock()
+
+	if len(tp.state.allow) == 0 {
+		return true
+	}
+
+	_, allowed := tp.state.allow[owner]
+
+	return allowed
+}
+
+func (tp *TenantPolicy) quota(owner string) TenantQuota {
+	tp.lock.RLock()
+	defer tp.lock.RUnlock()
+
+	if quota, exists := tp.state.owners[owner]; exists {
+		return quota
+	}
+
+	return tp.state.quota
+}
+
+func (tp *TenantPolicy) priority(owner string) int32 {
+	tp.lock.RLock()
+	defer tp.lock.RUnlock()
+
+	return tp.state.priorities[owner]
+}
+
+// TenantUsageClient reports amount of resources reserved by the owner
+type TenantUsageClient interface {
+	OwnerUsage(context.Context, string) (ctypes.InventoryOwnerUsage, error)
+}
+
+// checkQuota returns true if group fits into the quota of its owner.
+// When hold is set, group that fits is held as pending until releaseQuota is called, which must happen
+// once reservation of the group is settled in inventory. Dry runs pass hold false and leave policy intact
+func (tp *TenantPolicy) checkQuota(ctx context.Context, usage TenantUsageClient, group *dtypes.Group, hold bool) (bool, error) {
+	owner := group.GroupID.Owner
+
+	quota := tp.quota(owner)
+	if quota.isZero() {
+		return true, nil
+	}
+
+	tp.quotaLock.Lock()
+	defer tp.quotaLock.Unlock()
+
+	current, err := usage.OwnerUsage(ctx, owner)
+	if err != nil {
+		return false, err
+	}
+
+	// reservation of the group may be in inventory already while it is still pending.
+	// it is counted twice then, which only makes the check stricter for a short while
+	for id, pending := range tp.pending {
+		if pending.owner != owner || id.Equals(group.GroupID) {
+			continue
+		}
+
+		current.Leases++
+		current.CPU += pending.requested.CPU
+		current.Memory += pending.requested.Memory
+		current.GPU += pending.requested.GPU
+	}
+
+	requested := ctypes.InventoryMetricTotal{
+		Storage: make(map[string]int64),
+	}
+
+	for _, res := range group.GroupSpec.GetResources() {
+		requested.AddResources(res)
+	}
+
+	if quota.exceeded(current, requested) {
+		return false, nil
+	}
+
+	if !hold {
+		return true, nil
+	}
+
+	tp.pending[group.GroupID] = pendingQuota{
+		owner:     owner,
+		requested: requested,
+	}
+
+	return true, nil
+}
+
+// releaseQuota stops counting group as pending
+func (tp *TenantPolicy) releaseQuota(id dtypes.GroupID) {
+	tp.quotaLock.Lock()
+	defer tp.quotaLock.Unlock()
+
+	delete(tp.pending, id)
+}
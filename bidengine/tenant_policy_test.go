@@ -0,0 +1,198 @@
+package bidengine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	"github.com/akash-network/node/testutil"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+type fixedOwnerUsage ctypes.InventoryOwnerUsage
+
+func (fu fixedOwnerUsage) OwnerUsage(_ context.Context, _ string) (ctypes.InventoryOwnerUsage, error) {
+	return ctypes.InventoryOwnerUsage(fu), nil
+}
+
+func Test_TenantPolicyRejectsInvalidConfig(t *testing.T) {
+	_, err := NewTenantPolicy(TenantPolicyConfig{Allow: []string{"akash1invalid"}})
+	require.ErrorIs(t, err, errTenantPolicyInvalid)
+
+	_, err = NewTenantPolicy(TenantPolicyConfig{Deny: []string{"foo"}})
+	require.ErrorIs(t, err, errTenantPolicyInvalid)
+
+	_, err = NewTenantPolicy(TenantPolicyConfig{Quota: TenantQuotaConfig{CPU: "lots"}})
+	require.ErrorIs(t, err, errTenantPolicyInvalid)
+
+	_, err = NewTenantPolicy(TenantPolicyConfig{Quota: TenantQuotaConfig{Memory: "-1Gi"}})
+	require.ErrorIs(t, err, errTenantPolicyInvalid)
+
+	_, err = NewTenantPolicy(TenantPolicyConfig{Owners: map[string]TenantQuotaConfig{"foo": {Leases: 1}}})
+	require.ErrorIs(t, err, errTenantPolicyInvalid)
+
+	_, err = NewTenantPolicy(TenantPolicyConfig{Priorities: map[string]int32{"foo": 1}})
+	require.ErrorIs(t, err, errTenantPolicyInvalid)
+}
+
+func Test_TenantPolicyAllowDeny(t *testing.T) {
+	allowed := testutil.AccAddress(t).String()
+	denied := testutil.AccAddress(t).String()
+	other := testutil.AccAddress(t).String()
+
+	policy, err := NewTenantPolicy(TenantPolicyConfig{})
+	require.NoError(t, err)
+	require.False(t, policy.denied(other))
+	require.True(t, policy.allowed(other))
+
+	policy, err = NewTenantPolicy(TenantPolicyConfig{
+		Deny: []string{denied},
+	})
+	require.NoError(t, err)
+	require.True(t, policy.denied(denied))
+	require.False(t, policy.denied(other))
+	require.True(t, policy.allowed(other))
+
+	// deny list takes precedence over allow list
+	err = policy.Update(TenantPolicyConfig{
+		Allow: []string{allowed, denied},
+		Deny:  []string{denied},
+	})
+	require.NoError(t, err)
+	require.False(t, policy.denied(allowed))
+	require.True(t, policy.allowed(allowed))
+	require.True(t, policy.denied(denied))
+	require.False(t, policy.allowed(other))
+
+	// invalid update keeps current policy
+	err = policy.Update(TenantPolicyConfig{Deny: []string{"foo"}})
+	require.Error(t, err)
+	require.False(t, policy.allowed(other))
+}
+
+func Test_TenantPolicyQuota(t *testing.T) {
+	owner := testutil.AccAddress(t).String()
+	vip := testutil.AccAddress(t).String()
+
+	policy, err := NewTenantPolicy(TenantPolicyConfig{
+		Quota: TenantQuotaConfig{
+			Leases: 3,
+			CPU:    "2",
+			Memory: "1Gi",
+			GPU:    1,
+		},
+		Owners: map[string]TenantQuotaConfig{
+			vip: {},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, TenantQuota{Leases: 3, CPU: 2000, Memory: 1 << 30, GPU: 1}, policy.quota(owner))
+	require.True(t, policy.quota(vip).isZero())
+
+	gspec := *defaultGroupSpec()
+	groupOf := func(owner string) *dtypes.Group {
+		res := &dtypes.Group{GroupSpec: gspec}
+		res.GroupID.Owner = owner
+		return res
+	}
+
+	requested := ctypes.InventoryMetricTotal{Storage: make(map[string]int64)}
+	for _, res := range gspec.GetResources() {
+		requested.AddResources(res)
+	}
+
+	tests := []struct {
+		name  string
+		usage ctypes.InventoryOwnerUsage
+		ok    bool
+	}{
+		{name: "empty", ok: true},
+		{name: "leases", usage: ctypes.InventoryOwnerUsage{Leases: 3}},
+		{name: "cpu", usage: ctypes.InventoryOwnerUsage{InventoryMetricTotal: ctypes.InventoryMetricTotal{CPU: 2001 - requested.CPU}}},
+		{name: "cpu at limit", usage: ctypes.InventoryOwnerUsage{InventoryMetricTotal: ctypes.InventoryMetricTotal{CPU: 2000 - requested.CPU}}, ok: true},
+		{name: "memory", usage: ctypes.InventoryOwnerUsage{InventoryMetricTotal: ctypes.InventoryMetricTotal{Memory: 1 << 30}}},
+		{name: "gpu", usage: ctypes.InventoryOwnerUsage{InventoryMetricTotal: ctypes.InventoryMetricTotal{GPU: 1}}, ok: requested.GPU == 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ok, err := policy.checkQuota(context.Background(), fixedOwnerUsage(test.usage), groupOf(owner), true)
+			require.NoError(t, err)
+			require.Equal(t, test.ok, ok)
+
+			// owner without quota is never limited
+			ok, err = policy.checkQuota(context.Background(), fixedOwnerUsage(test.usage), groupOf(vip), true)
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+	}
+}
+
+func Test_TenantPolicyQuotaCountsPendingGroups(t *testing.T) {
+	owner := testutil.AccAddress(t).String()
+
+	policy, err := NewTenantPolicy(TenantPolicyConfig{
+		Quota: TenantQuotaConfig{Leases: 2},
+	})
+	require.NoError(t, err)
+
+	groupOf := func(gseq uint32) *dtypes.Group {
+		res := &dtypes.Group{GroupSpec: *defaultGroupSpec()}
+		res.GroupID.Owner = owner
+		res.GroupID.GSeq = gseq
+		return res
+	}
+
+	usage := fixedOwnerUsage(ctypes.InventoryOwnerUsage{Leases: 1})
+
+	// first group takes the last lease left in the quota, while it is not reserved
+	// second group must not pass the check on the same usage
+	ok, err := policy.checkQuota(context.Background(), usage, groupOf(1), true)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = policy.checkQuota(context.Background(), usage, groupOf(2), true)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// checking same group again does not count it twice
+	ok, err = policy.checkQuota(context.Background(), usage, groupOf(1), true)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	policy.releaseQuota(groupOf(1).GroupID)
+
+	ok, err = policy.checkQuota(context.Background(), usage, groupOf(2), true)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func Test_TenantPolicyQuotaDryRunDoesNotHold(t *testing.T) {
+	owner := testutil.AccAddress(t).String()
+
+	policy, err := NewTenantPolicy(TenantPolicyConfig{
+		Quota: TenantQuotaConfig{Leases: 1},
+	})
+	require.NoError(t, err)
+
+	groupOf := func(gseq uint32) *dtypes.Group {
+		res := &dtypes.Group{GroupSpec: *defaultGroupSpec()}
+		res.GroupID.Owner = owner
+		res.GroupID.GSeq = gseq
+		return res
+	}
+
+	usage := fixedOwnerUsage{}
+
+	ok, err := policy.checkQuota(context.Background(), usage, groupOf(0), false)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = policy.checkQuota(context.Background(), usage, groupOf(1), true)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -2,5 +2,7 @@ package bidengine
 
 // Status stores orders
 type Status struct {
-	Orders uint32 `json:"orders"`
+	Orders     uint32 `json:"orders"`
+	QueueDepth uint32 `json:"queue_depth"`
+	InFlight   uint32 `json:"in_flight"`
 }
@@ -37,7 +37,17 @@ var (
 	ErrExecCommandDoesNotExist     = fmt.Errorf("%w: command could not be executed because it does not exist", ErrExec)
 	ErrExecDeploymentNotYetRunning = fmt.Errorf("%w: deployment is not yet active", ErrExec)
 	ErrExecPodIndexOutOfRange      = fmt.Errorf("%w: pod index out of range", ErrExec)
+	ErrExecPortNotExposed          = fmt.Errorf("%w: service does not expose tcp port", ErrExec)
+	ErrExecPortForwardFailed       = fmt.Errorf("%w: port forward failed", ErrExec)
 	ErrUnknownStorageClass         = errors.New("inventory: unknown storage class")
+	ErrSnapshot                    = errors.New("volume snapshot error")
+	ErrSnapshotDisabled            = fmt.Errorf("%w: snapshots are disabled", ErrSnapshot)
+	ErrSnapshotNoVolumes           = fmt.Errorf("%w: service has no persistent volumes", ErrSnapshot)
+	ErrSnapshotNotFound            = fmt.Errorf("%w: no such snapshot", ErrSnapshot)
+	ErrSnapshotNotReady            = fmt.Errorf("%w: snapshot is not ready to use", ErrSnapshot)
+	ErrSnapshotQuotaExceeded       = fmt.Errorf("%w: snapshot quota exceeded", ErrSnapshot)
+	ErrRestart                     = errors.New("service restart error")
+	ErrRestartPodIndexOutOfRange   = fmt.Errorf("%w: pod index out of range", ErrRestart)
 	errNotImplemented              = errors.New("not implemented")
 )
 
@@ -48,11 +58,14 @@ type ReadClient interface {
 	LeaseStatus(context.Context, mtypes.LeaseID) (map[string]*ctypes.ServiceStatus, error)
 	ForwardedPortStatus(context.Context, mtypes.LeaseID) (map[string][]ctypes.ForwardedPortStatus, error)
 	LeaseEvents(context.Context, mtypes.LeaseID, string, bool) (ctypes.EventsWatcher, error)
-	LeaseLogs(context.Context, mtypes.LeaseID, string, bool, *int64) ([]*ctypes.ServiceLog, error)
+	LeaseLogs(context.Context, mtypes.LeaseID, ctypes.LeaseLogsOptions) ([]*ctypes.ServiceLog, error)
 	ServiceStatus(context.Context, mtypes.LeaseID, string) (*ctypes.ServiceStatus, error)
+	// LeaseMetrics returns current resource usage of running pods of the lease
+	LeaseMetrics(context.Context, mtypes.LeaseID) (*ctypes.LeaseMetrics, error)
 
 	AllHostnames(context.Context) ([]ctypes.ActiveHostname, error)
 	GetManifestGroup(context.Context, mtypes.LeaseID) (bool, crd.ManifestGroup, error)
+	GetManifestStatus(context.Context, mtypes.LeaseID) (crd.ManifestStatus, error)
 
 	ObserveHostnameState(ctx context.Context) (<-chan ctypes.HostnameResourceEvent, error)
 	GetHostnameDeploymentConnections(ctx context.Context) ([]ctypes.LeaseIDHostnameConnection, error)
@@ -69,7 +82,14 @@ type Client interface {
 	Deploy(ctx context.Context, deployment ctypes.IDeployment) error
 	TeardownLease(context.Context, mtypes.LeaseID) error
 	Deployments(context.Context) ([]ctypes.IDeployment, error)
+	// SetLastGoodDeployment records currently deployed group of the lease as the last good one
+	SetLastGoodDeployment(ctx context.Context, lID mtypes.LeaseID) error
+	// RollbackDeployment marks lease as rolled back and returns its last good deployment
+	RollbackDeployment(ctx context.Context, lID mtypes.LeaseID, reason string) (ctypes.IDeployment, error)
 	Inventory(context.Context) (ctypes.Inventory, error)
+	// StorageExpansion returns additional space of persistent storage classes needed
+	// to grow volumes of the deployed lease to sizes requested by the deployment
+	StorageExpansion(ctx context.Context, deployment ctypes.IDeployment) (map[string]types.ResourceValue, error)
 	Exec(ctx context.Context,
 		lID mtypes.LeaseID,
 		service string,
@@ -81,6 +101,15 @@ type Client interface {
 		tty bool,
 		tsq remotecommand.TerminalSizeQueue) (ctypes.ExecResult, error)
 
+	// PortForward tunnels input and output to the tcp port of the service replica
+	PortForward(ctx context.Context,
+		lID mtypes.LeaseID,
+		service string,
+		podIndex uint,
+		port uint32,
+		input io.Reader,
+		output io.Writer) error
+
 	// ConnectHostnameToDeployment Connect a given hostname to a deployment
 	ConnectHostnameToDeployment(ctx context.Context, directive ctypes.ConnectHostnameToDeploymentDirective) error
 	// RemoveHostnameFromDeployment Remove a given hostname from a deployment
@@ -99,6 +128,21 @@ type Client interface {
 	DeclareIP(ctx context.Context, lID mtypes.LeaseID, serviceName string, port uint32, externalPort uint32, proto mani.ServiceProtocol, sharingKey string, overwrite bool) error
 	PurgeDeclaredIP(ctx context.Context, lID mtypes.LeaseID, serviceName string, externalPort uint32, proto mani.ServiceProtocol) error
 	PurgeDeclaredIPs(ctx context.Context, lID mtypes.LeaseID) error
+
+	// SweepOrphans tears down leases not in active list which left objects in the cluster
+	// and returns leases swept. Objects younger than minAge are ignored
+	SweepOrphans(ctx context.Context, active []mtypes.LeaseID, minAge time.Duration) ([]mtypes.LeaseID, error)
+
+	// CreateSnapshot snapshots all persistent volumes of the service
+	CreateSnapshot(ctx context.Context, lID mtypes.LeaseID, service string) ([]ctypes.VolumeSnapshot, error)
+	// ListSnapshots returns volume snapshots of the lease. Snapshots of all services are returned when service is empty
+	ListSnapshots(ctx context.Context, lID mtypes.LeaseID, service string) ([]ctypes.VolumeSnapshot, error)
+	// RestoreSnapshot replaces persistent volumes of the service with volumes restored from the snapshot
+	RestoreSnapshot(ctx context.Context, lID mtypes.LeaseID, service string, name string) error
+
+	// RestartService performs rolling restart of all replicas of the service,
+	// or restarts only the replica at podIndex when it is not nil
+	RestartService(ctx context.Context, lID mtypes.LeaseID, service string, podIndex *uint) error
 }
 
 func ErrorIsOkToSendToClient(err error) bool {
@@ -172,6 +216,11 @@ type inventory struct {
 var _ ctypes.Inventory = (*inventory)(nil)
 
 func (inv *inventory) Adjust(reservation ctypes.ReservationGroup, opts ...ctypes.InventoryOption) error {
+	cfg := &ctypes.InventoryOptions{}
+	for _, opt := range opts {
+		cfg = opt(cfg)
+	}
+
 	resources := make([]types.Resources, len(reservation.Resources().GetResources()))
 	copy(resources, reservation.Resources().GetResources())
 
@@ -208,6 +257,13 @@ nodes:
 				storageClasses := currInventory.storage.dup()
 
 				for idx, storage := range res.Resources.Storage {
+					if ctypes.IsRAMStorage(storage.Attributes) {
+						if adjusted = memory.subNLZ(storage.Quantity); !adjusted {
+							continue nodes
+						}
+						continue
+					}
+
 					attr := storage.Attributes.Find(sdl.StorageAttributePersistent)
 
 					if persistent, _ := attr.AsBool(); !persistent {
@@ -267,7 +323,9 @@ nodes:
 	}
 
 	if len(resources) == 0 {
-		*inv = *currInventory
+		if !cfg.DryRun {
+			*inv = *currInventory
+		}
 
 		return nil
 	}
@@ -275,6 +333,25 @@ nodes:
 	return ctypes.ErrInsufficientCapacity
 }
 
+func (inv *inventory) AdjustStorage(requests map[string]types.ResourceValue) error {
+	storage := inv.storage.dup()
+
+	for class, val := range requests {
+		cstorage, exists := storage[class]
+		if !exists {
+			return fmt.Errorf("%w: %q", ErrUnknownStorageClass, class)
+		}
+
+		if !cstorage.subNLZ(val) {
+			return fmt.Errorf("%w: storage class %q", ctypes.ErrInsufficientCapacity, class)
+		}
+	}
+
+	inv.storage = storage
+
+	return nil
+}
+
 func (inv *inventory) Metrics() ctypes.InventoryMetrics {
 	cpuTotal := uint64(0)
 	gpuTotal := uint64(0)
@@ -348,7 +425,8 @@ func (inv *inventory) Metrics() ctypes.InventoryMetrics {
 
 func (inv *inventory) dup() *inventory {
 	res := &inventory{
-		nodes: make([]*node, 0, len(inv.nodes)),
+		storage: inv.storage.dup(),
+		nodes:   make([]*node, 0, len(inv.nodes)),
 	}
 
 	for _, nd := range inv.nodes {
@@ -448,6 +526,11 @@ func (c *nullClient) Deploy(ctx context.Context, deployment ctypes.IDeployment)
 	return nil
 }
 
+// StorageExpansion returns nothing as null client has no persistent volumes to grow
+func (*nullClient) StorageExpansion(_ context.Context, _ ctypes.IDeployment) (map[string]types.ResourceValue, error) {
+	return nil, nil
+}
+
 func (*nullClient) ForwardedPortStatus(context.Context, mtypes.LeaseID) (map[string][]ctypes.ForwardedPortStatus, error) {
 	return nil, errNotImplemented
 }
@@ -464,9 +547,14 @@ func (c *nullClient) LeaseStatus(_ context.Context, lid mtypes.LeaseID) (map[str
 	resp := make(map[string]*ctypes.ServiceStatus)
 	for _, svc := range lease.group.Services {
 		resp[svc.Name] = &ctypes.ServiceStatus{
-			Name:      svc.Name,
-			Available: int32(svc.Count),
-			Total:     int32(svc.Count),
+			Name:              svc.Name,
+			Available:         int32(svc.Count),
+			Total:             int32(svc.Count),
+			Replicas:          int32(svc.Count),
+			UpdatedReplicas:   int32(svc.Count),
+			ReadyReplicas:     int32(svc.Count),
+			AvailableReplicas: int32(svc.Count),
+			Progressing:       true,
 		}
 	}
 
@@ -538,10 +626,18 @@ func (c *nullClient) ServiceStatus(_ context.Context, _ mtypes.LeaseID, _ string
 	return nil, nil
 }
 
-func (c *nullClient) LeaseLogs(_ context.Context, _ mtypes.LeaseID, _ string, _ bool, _ *int64) ([]*ctypes.ServiceLog, error) {
+func (c *nullClient) LeaseLogs(_ context.Context, _ mtypes.LeaseID, _ ctypes.LeaseLogsOptions) ([]*ctypes.ServiceLog, error) {
 	return nil, nil
 }
 
+func (c *nullClient) LeaseMetrics(_ context.Context, _ mtypes.LeaseID) (*ctypes.LeaseMetrics, error) {
+	return nil, errNotImplemented
+}
+
+func (c *nullClient) RestartService(_ context.Context, _ mtypes.LeaseID, _ string, _ *uint) error {
+	return errNotImplemented
+}
+
 func (c *nullClient) TeardownLease(_ context.Context, lid mtypes.LeaseID) error {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
@@ -599,10 +695,26 @@ func (c *nullClient) Exec(context.Context, mtypes.LeaseID, string, uint, []strin
 	return nil, errNotImplemented
 }
 
+func (c *nullClient) PortForward(context.Context, mtypes.LeaseID, string, uint, uint32, io.Reader, io.Writer) error {
+	return errNotImplemented
+}
+
 func (c *nullClient) GetManifestGroup(context.Context, mtypes.LeaseID) (bool, crd.ManifestGroup, error) {
 	return false, crd.ManifestGroup{}, nil
 }
 
+func (c *nullClient) GetManifestStatus(context.Context, mtypes.LeaseID) (crd.ManifestStatus, error) {
+	return crd.ManifestStatus{}, nil
+}
+
+func (c *nullClient) SetLastGoodDeployment(context.Context, mtypes.LeaseID) error {
+	return nil
+}
+
+func (c *nullClient) RollbackDeployment(context.Context, mtypes.LeaseID, string) (ctypes.IDeployment, error) {
+	return nil, errNotImplemented
+}
+
 func (c *nullClient) AllHostnames(context.Context) ([]ctypes.ActiveHostname, error) {
 	return nil, nil
 }
@@ -619,6 +731,22 @@ func (c *nullClient) PurgeDeclaredIPs(_ context.Context, _ mtypes.LeaseID) error
 	return errNotImplemented
 }
 
+func (c *nullClient) SweepOrphans(_ context.Context, _ []mtypes.LeaseID, _ time.Duration) ([]mtypes.LeaseID, error) {
+	return nil, nil
+}
+
+func (c *nullClient) CreateSnapshot(_ context.Context, _ mtypes.LeaseID, _ string) ([]ctypes.VolumeSnapshot, error) {
+	return nil, errNotImplemented
+}
+
+func (c *nullClient) ListSnapshots(_ context.Context, _ mtypes.LeaseID, _ string) ([]ctypes.VolumeSnapshot, error) {
+	return nil, errNotImplemented
+}
+
+func (c *nullClient) RestoreSnapshot(_ context.Context, _ mtypes.LeaseID, _ string, _ string) error {
+	return errNotImplemented
+}
+
 func (c *nullClient) ObserveIPState(_ context.Context) (<-chan ctypes.IPResourceEvent, error) {
 	return nil, errNotImplemented
 }
@@ -14,6 +14,11 @@ type Config struct {
 	DeploymentIngressStaticHosts    bool
 	DeploymentIngressDomain         string
 	ClusterSettings                 map[interface{}]interface{}
+	// OrphanSweepPeriod is how often objects of leases without deployment manager are removed.
+	// Zero disables the sweeper
+	OrphanSweepPeriod time.Duration
+	// OrphanSweepMinAge protects objects of leases which are being deployed from the sweeper
+	OrphanSweepMinAge time.Duration
 }
 
 func NewDefaultConfig() Config {
@@ -68,9 +68,11 @@ type inventoryService struct {
 	sub    pubsub.Subscriber
 
 	statusch         chan chan<- ctypes.InventoryStatus
+	usagech          chan inventoryUsageRequest
 	lookupch         chan inventoryRequest
 	reservech        chan inventoryRequest
 	unreservech      chan inventoryRequest
+	adjustch         chan inventoryAdjustRequest
 	reservationCount int64
 
 	readych chan struct{}
@@ -106,9 +108,11 @@ func newInventoryService(
 		client:                 client,
 		sub:                    sub,
 		statusch:               make(chan chan<- ctypes.InventoryStatus),
+		usagech:                make(chan inventoryUsageRequest),
 		lookupch:               make(chan inventoryRequest),
 		reservech:              make(chan inventoryRequest),
 		unreservech:            make(chan inventoryRequest),
+		adjustch:               make(chan inventoryAdjustRequest),
 		readych:                make(chan struct{}),
 		log:                    log.With("cmp", "inventory-service"),
 		lc:                     lifecycle.New(),
@@ -156,6 +160,16 @@ func (is *inventoryService) lookup(order mtypes.OrderID, resources atypes.Resour
 }
 
 func (is *inventoryService) reserve(order mtypes.OrderID, resources atypes.ResourceGroup) (ctypes.Reservation, error) {
+	return is.requestReservation(context.Background(), order, resources, false)
+}
+
+// dryRunReserve checks if resources fit into the inventory without reserving them.
+// Reservations are not served until inventory is ready, so caller bounds the wait with ctx
+func (is *inventoryService) dryRunReserve(ctx context.Context, order mtypes.OrderID, resources atypes.ResourceGroup) (ctypes.Reservation, error) {
+	return is.requestReservation(ctx, order, resources, true)
+}
+
+func (is *inventoryService) requestReservation(ctx context.Context, order mtypes.OrderID, resources atypes.ResourceGroup, dryRun bool) (ctypes.Reservation, error) {
 	for idx, res := range resources.GetResources() {
 		if res.Resources.CPU == nil {
 			return nil, fmt.Errorf("%w: CPU resource at idx %d is nil", ErrInvalidResource, idx)
@@ -172,19 +186,22 @@ func (is *inventoryService) reserve(order mtypes.OrderID, resources atypes.Resou
 	req := inventoryRequest{
 		order:     order,
 		resources: resources,
+		dryRun:    dryRun,
 		ch:        ch,
 	}
 
 	select {
 	case is.reservech <- req:
 		response := <-ch
-		if response.err == nil {
+		if response.err == nil && !dryRun {
 			cnt := atomic.AddInt64(&is.reservationCount, 1)
 			is.log.Debug("reservation count", "cnt", cnt)
 		}
 		return response.value, response.err
 	case <-is.lc.ShuttingDown():
 		return nil, ErrNotRunning
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
 }
 
@@ -208,6 +225,26 @@ func (is *inventoryService) unreserve(order mtypes.OrderID) error { // nolint:go
 	}
 }
 
+// adjustStorage takes additional space of persistent storage classes for the reservation of the order,
+// so space of the grown volumes is not offered to other orders until next inventory check accounts for it
+func (is *inventoryService) adjustStorage(ctx context.Context, order mtypes.OrderID, storage map[string]atypes.ResourceValue) error {
+	ch := make(chan error, 1)
+	req := inventoryAdjustRequest{
+		order:   order,
+		storage: storage,
+		ch:      ch,
+	}
+
+	select {
+	case is.adjustch <- req:
+		return <-ch
+	case <-is.lc.ShuttingDown():
+		return ErrNotRunning
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (is *inventoryService) status(ctx context.Context) (ctypes.InventoryStatus, error) {
 	ch := make(chan ctypes.InventoryStatus, 1)
 
@@ -229,12 +266,49 @@ func (is *inventoryService) status(ctx context.Context) (ctypes.InventoryStatus,
 	}
 }
 
+func (is *inventoryService) usage(ctx context.Context, owner string) (ctypes.InventoryOwnerUsage, error) {
+	ch := make(chan ctypes.InventoryOwnerUsage, 1)
+	req := inventoryUsageRequest{
+		owner: owner,
+		ch:    ch,
+	}
+
+	select {
+	case <-is.lc.Done():
+		return ctypes.InventoryOwnerUsage{}, ErrNotRunning
+	case <-ctx.Done():
+		return ctypes.InventoryOwnerUsage{}, ctx.Err()
+	case is.usagech <- req:
+	}
+
+	select {
+	case <-is.lc.Done():
+		return ctypes.InventoryOwnerUsage{}, ErrNotRunning
+	case <-ctx.Done():
+		return ctypes.InventoryOwnerUsage{}, ctx.Err()
+	case result := <-ch:
+		return result, nil
+	}
+}
+
+type inventoryUsageRequest struct {
+	owner string
+	ch    chan<- ctypes.InventoryOwnerUsage
+}
+
 type inventoryRequest struct {
 	order     mtypes.OrderID
 	resources atypes.ResourceGroup
+	dryRun    bool
 	ch        chan<- inventoryResponse
 }
 
+type inventoryAdjustRequest struct {
+	order   mtypes.OrderID
+	storage map[string]atypes.ResourceValue
+	ch      chan<- error
+}
+
 type inventoryResponse struct {
 	value ctypes.Reservation
 	err   error
@@ -411,14 +485,29 @@ func (is *inventoryService) handleRequest(req inventoryRequest, state *inventory
 		reservation.ipsConfirmed = true // No IPs, just mark it as confirmed implicitly
 	}
 
-	err := state.inventory.Adjust(reservation)
+	action := "reserve"
+	var opts []ctypes.InventoryOption
+
+	if req.dryRun {
+		action = "dry-run"
+		opts = append(opts, ctypes.WithDryRun())
+	}
+
+	err := state.inventory.Adjust(reservation, opts...)
 	if err != nil {
-		is.log.Info("insufficient capacity for reservation", "order", req.order)
-		inventoryRequestsCounter.WithLabelValues("reserve", "insufficient-capacity").Inc()
+		is.log.Info("insufficient capacity for reservation", "order", req.order, "dry-run", req.dryRun)
+		inventoryRequestsCounter.WithLabelValues(action, "insufficient-capacity").Inc()
 		req.ch <- inventoryResponse{err: err}
 		return
 	}
 
+	// dry run leaves inventory and reservations intact
+	if req.dryRun {
+		req.ch <- inventoryResponse{value: reservation}
+		inventoryRequestsCounter.WithLabelValues(action, "success").Inc()
+		return
+	}
+
 	// Add the reservation to the list
 	state.reservations = append(state.reservations, reservation)
 	req.ch <- inventoryResponse{value: reservation}
@@ -426,6 +515,33 @@ func (is *inventoryService) handleRequest(req inventoryRequest, state *inventory
 
 }
 
+func (is *inventoryService) handleAdjustRequest(req inventoryAdjustRequest, state *inventoryServiceState) {
+	found := false
+	for _, res := range state.reservations {
+		if res.OrderID().Equals(req.order) {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		inventoryRequestsCounter.WithLabelValues("adjust", "not-found").Inc()
+		req.ch <- errReservationNotFound
+		return
+	}
+
+	if err := state.inventory.AdjustStorage(req.storage); err != nil {
+		is.log.Info("insufficient capacity for storage adjustment", "order", req.order, "err", err)
+		inventoryRequestsCounter.WithLabelValues("adjust", "insufficient-capacity").Inc()
+		req.ch <- err
+		return
+	}
+
+	is.log.Info("adjusted storage of reservation", "order", req.order)
+	inventoryRequestsCounter.WithLabelValues("adjust", "success").Inc()
+	req.ch <- nil
+}
+
 func (is *inventoryService) run(ctx context.Context, reservationsArg []*reservation) {
 	defer is.lc.ShutdownCompleted()
 	defer is.sub.Close()
@@ -455,13 +571,16 @@ func (is *inventoryService) run(ctx context.Context, reservationsArg []*reservat
 	var fetchCount uint
 
 	var reserveChLocal <-chan inventoryRequest
+	var adjustChLocal <-chan inventoryAdjustRequest
 
 	resumeProcessingReservations := func() {
 		reserveChLocal = is.reservech
+		adjustChLocal = is.adjustch
 	}
 
 	updateInventory := func() {
 		reserveChLocal = nil
+		adjustChLocal = nil
 		if runch == nil {
 			runch = is.runCheck(ctx, state)
 		}
@@ -511,6 +630,9 @@ loop:
 		case req := <-reserveChLocal:
 			is.handleRequest(req, state)
 
+		case req := <-adjustChLocal:
+			is.handleAdjustRequest(req, state)
+
 		case req := <-is.lookupch:
 			// lookup registration
 			for _, res := range state.reservations {
@@ -560,6 +682,10 @@ loop:
 			responseCh <- is.getStatus(state)
 			inventoryRequestsCounter.WithLabelValues("status", "success").Inc()
 
+		case req := <-is.usagech:
+			req.ch <- getOwnerUsage(state, req.owner)
+			inventoryRequestsCounter.WithLabelValues("usage", "success").Inc()
+
 		case <-t.C:
 			// run cluster inventory check
 
@@ -748,6 +874,28 @@ func (is *inventoryService) getStatus(state *inventoryServiceState) ctypes.Inven
 	return status
 }
 
+func getOwnerUsage(state *inventoryServiceState, owner string) ctypes.InventoryOwnerUsage {
+	usage := ctypes.InventoryOwnerUsage{
+		InventoryMetricTotal: ctypes.InventoryMetricTotal{
+			Storage: make(map[string]int64),
+		},
+	}
+
+	for _, reservation := range state.reservations {
+		if reservation.OrderID().Owner != owner {
+			continue
+		}
+
+		usage.Leases++
+
+		for _, resources := range reservation.Resources().GetResources() {
+			usage.AddResources(resources)
+		}
+	}
+
+	return usage
+}
+
 func reservationCountEndpoints(reservation *reservation) uint {
 	var externalPortCount uint
 
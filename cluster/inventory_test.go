@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/boz/go-lifecycle"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -281,6 +282,18 @@ func TestInventory_ClusterDeploymentDeployed(t *testing.T) {
 	// availableExternalEndpoints should be consumed because of the deployed reservation
 	require.Equal(t, uint(1000-countOfRandomPortService), inv.availableExternalPorts)
 
+	// deployed reservation is accounted to its owner only
+	usage, err := inv.usage(context.Background(), lid.Owner)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), usage.Leases)
+	require.Equal(t, uint64(1), usage.CPU)
+	require.Equal(t, uint64(1*unit.Gi), usage.Memory)
+
+	usage, err = inv.usage(context.Background(), testutil.AccAddress(t).String())
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), usage.Leases)
+	require.Equal(t, uint64(0), usage.CPU)
+
 	// Unreserving the allocated reservation should reclaim the availableExternalEndpoints
 	err = inv.unreserve(lid.OrderID())
 	require.NoError(t, err)
@@ -668,12 +681,22 @@ func TestInventory_OverReservations(t *testing.T) {
 	// Wait for first call to inventory
 	testutil.ChannelWaitForValueUpTo(t, scaffold.inventoryCalled, 30*time.Second)
 
+	// Dry run does not consume inventory
+	for i := 0; i < 2; i++ {
+		reservation, err := inv.dryRunReserve(context.Background(), lid0.OrderID(), group)
+		require.NoError(t, err)
+		require.NotNil(t, reservation)
+	}
+
 	// Get the reservation
 	reservation, err := inv.reserve(lid0.OrderID(), group)
 	require.NoError(t, err)
 	require.NotNil(t, reservation)
 
 	// Confirm the second reservation would be too much
+	_, err = inv.dryRunReserve(context.Background(), lid1.OrderID(), group)
+	require.ErrorIs(t, err, ctypes.ErrInsufficientCapacity)
+
 	_, err = inv.reserve(lid1.OrderID(), group)
 	require.Error(t, err)
 	require.ErrorIs(t, err, ctypes.ErrInsufficientCapacity)
@@ -706,3 +729,78 @@ func TestInventory_OverReservations(t *testing.T) {
 	// No ports used yet
 	require.Equal(t, uint(1000-countOfRandomPortService), inv.availableExternalPorts)
 }
+
+func TestInventory_AdjustStorage(t *testing.T) {
+	scaffold := makeInventoryScaffold(t, 2, true, "nodeA")
+	defer scaffold.bus.Close()
+	lid0 := scaffold.leaseIDs[0]
+	lid1 := scaffold.leaseIDs[1]
+
+	subscriber, err := scaffold.bus.Subscribe()
+	require.NoError(t, err)
+	defer subscriber.Close()
+
+	config := Config{
+		InventoryResourcePollPeriod:     time.Hour,
+		InventoryResourceDebugFrequency: 1,
+		InventoryExternalPortQuantity:   1000,
+	}
+
+	inv, err := newInventoryService(
+		config,
+		testutil.Logger(t),
+		scaffold.donech,
+		subscriber,
+		scaffold.clusterClient,
+		nil,                    // No IP operator client
+		waiter.NewNullWaiter(), // Do not need to wait in test
+		make([]ctypes.IDeployment, 0))
+	require.NoError(t, err)
+	require.NotNil(t, inv)
+
+	testutil.ChannelWaitForValueUpTo(t, scaffold.inventoryCalled, 30*time.Second)
+
+	_, err = inv.reserve(lid0.OrderID(), makeGroupForInventoryTest(false, false, false))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// storage is grown for existing reservations only
+	err = inv.adjustStorage(ctx, lid1.OrderID(), map[string]types.ResourceValue{"beta2": types.NewResourceValue(unit.Gi)})
+	require.ErrorIs(t, err, errReservationNotFound)
+
+	// 502Gi of beta2 class is available
+	err = inv.adjustStorage(ctx, lid0.OrderID(), map[string]types.ResourceValue{"beta2": types.NewResourceValue(500 * unit.Gi)})
+	require.NoError(t, err)
+
+	// space taken by the adjustment is not available to anyone else
+	err = inv.adjustStorage(ctx, lid0.OrderID(), map[string]types.ResourceValue{"beta2": types.NewResourceValue(10 * unit.Gi)})
+	require.ErrorIs(t, err, ctypes.ErrInsufficientCapacity)
+
+	err = inv.adjustStorage(ctx, lid0.OrderID(), map[string]types.ResourceValue{"beta3": types.NewResourceValue(1)})
+	require.ErrorIs(t, err, ErrUnknownStorageClass)
+
+	close(scaffold.donech)
+	<-inv.lc.Done()
+}
+
+func TestInventory_DryRunReserveNotReady(t *testing.T) {
+	// reservations are not served until inventory is ready, nobody reads reservech here
+	inv := &inventoryService{
+		reservech: make(chan inventoryRequest),
+		lc:        lifecycle.New(),
+	}
+
+	group := testutil.GroupSpec(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := inv.dryRunReserve(ctx, testutil.OrderID(t), &group)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	inv.lc.ShutdownInitiated(nil)
+
+	_, err = inv.dryRunReserve(context.Background(), testutil.OrderID(t), &group)
+	require.ErrorIs(t, err, ErrNotRunning)
+}
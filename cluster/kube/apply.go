@@ -5,8 +5,13 @@ package kube
 import (
 	"context"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/client-go/kubernetes"
 
 	metricsutils "github.com/akash-network/node/util/metrics"
@@ -46,7 +51,8 @@ func applyNetPolicies(ctx context.Context, kc kubernetes.Interface, b builder.Ne
 	}
 
 	for _, pol := range policies {
-		obj, err := kc.NetworkingV1().NetworkPolicies(b.NS()).Get(ctx, pol.Name, metav1.GetOptions{})
+		var obj *netv1.NetworkPolicy
+		obj, err = kc.NetworkingV1().NetworkPolicies(b.NS()).Get(ctx, pol.Name, metav1.GetOptions{})
 		metricsutils.IncCounterVecWithLabelValuesFiltered(kubeCallsCounter, "networking-policies-get", err, errors.IsNotFound)
 
 		switch {
@@ -68,23 +74,59 @@ func applyNetPolicies(ctx context.Context, kc kubernetes.Interface, b builder.Ne
 	return err
 }
 
-// TODO: re-enable.  see #946
-// func applyRestrictivePodSecPoliciesToNS(ctx context.Context, kc kubernetes.Interface, p builder.PspRestricted) error {
-// 	obj, err := kc.PolicyV1beta1().PodSecurityPolicies().Get(ctx, p.Name(), metav1.GetOptions{})
-// 	switch {
-// 	case err == nil:
-// 		obj, err = p.Update(obj)
-// 		if err == nil {
-// 			_, err = kc.PolicyV1beta1().PodSecurityPolicies().Update(ctx, obj, metav1.UpdateOptions{})
-// 		}
-// 	case errors.IsNotFound(err):
-// 		obj, err = p.Create()
-// 		if err == nil {
-// 			_, err = kc.PolicyV1beta1().PodSecurityPolicies().Create(ctx, obj, metav1.CreateOptions{})
-// 		}
-// 	}
-// 	return err
-// }
+// applyResourceQuota creates or updates lease quota, or removes it when quotas are disabled
+func applyResourceQuota(ctx context.Context, kc kubernetes.Interface, b builder.ResourceQuota, enabled bool) error {
+	obj, err := kc.CoreV1().ResourceQuotas(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
+	metricsutils.IncCounterVecWithLabelValuesFiltered(kubeCallsCounter, "resource-quotas-get", err, errors.IsNotFound)
+
+	switch {
+	case err == nil && !enabled:
+		err = kc.CoreV1().ResourceQuotas(b.NS()).Delete(ctx, b.Name(), metav1.DeleteOptions{})
+		metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "resource-quotas-delete", err)
+	case err == nil:
+		obj, err = b.Update(obj)
+		if err == nil {
+			_, err = kc.CoreV1().ResourceQuotas(b.NS()).Update(ctx, obj, metav1.UpdateOptions{})
+			metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "resource-quotas-update", err)
+		}
+	case errors.IsNotFound(err) && !enabled:
+		err = nil
+	case errors.IsNotFound(err):
+		obj, err = b.Create()
+		if err == nil {
+			_, err = kc.CoreV1().ResourceQuotas(b.NS()).Create(ctx, obj, metav1.CreateOptions{})
+			metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "resource-quotas-create", err)
+		}
+	}
+	return err
+}
+
+// applyLimitRange creates or updates lease limit range, or removes it when quotas are disabled
+func applyLimitRange(ctx context.Context, kc kubernetes.Interface, b builder.LimitRange, enabled bool) error {
+	obj, err := kc.CoreV1().LimitRanges(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
+	metricsutils.IncCounterVecWithLabelValuesFiltered(kubeCallsCounter, "limit-ranges-get", err, errors.IsNotFound)
+
+	switch {
+	case err == nil && !enabled:
+		err = kc.CoreV1().LimitRanges(b.NS()).Delete(ctx, b.Name(), metav1.DeleteOptions{})
+		metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "limit-ranges-delete", err)
+	case err == nil:
+		obj, err = b.Update(obj)
+		if err == nil {
+			_, err = kc.CoreV1().LimitRanges(b.NS()).Update(ctx, obj, metav1.UpdateOptions{})
+			metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "limit-ranges-update", err)
+		}
+	case errors.IsNotFound(err) && !enabled:
+		err = nil
+	case errors.IsNotFound(err):
+		obj, err = b.Create()
+		if err == nil {
+			_, err = kc.CoreV1().LimitRanges(b.NS()).Create(ctx, obj, metav1.CreateOptions{})
+			metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "limit-ranges-create", err)
+		}
+	}
+	return err
+}
 
 func applyDeployment(ctx context.Context, kc kubernetes.Interface, b builder.Deployment) error {
 	obj, err := kc.AppsV1().Deployments(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
@@ -109,21 +151,35 @@ func applyDeployment(ctx context.Context, kc kubernetes.Interface, b builder.Dep
 	return err
 }
 
-func applyStatefulSet(ctx context.Context, kc kubernetes.Interface, b builder.StatefulSet) error {
+// applyStatefulSet creates or updates statefulset of the service. restartedAt, when set, is restart annotation
+// of the statefulset removed by volume expansion, created statefulset keeps it so adopted pods are not replaced
+func applyStatefulSet(ctx context.Context, kc kubernetes.Interface, b builder.StatefulSet, restartedAt string) error {
 	obj, err := kc.AppsV1().StatefulSets(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
 	metricsutils.IncCounterVecWithLabelValuesFiltered(kubeCallsCounter, "deployments-get", err, errors.IsNotFound)
 
 	switch {
 	case err == nil:
+		// revision rolled out when the update was applied, it does not complete until its pods become ready
+		var pendingRevision string
+		if obj.Status.UpdateRevision != obj.Status.CurrentRevision {
+			pendingRevision = obj.Status.UpdateRevision
+		}
+
 		obj, err = b.Update(obj)
 
 		if err == nil {
-			_, err = kc.AppsV1().StatefulSets(b.NS()).Update(ctx, obj, metav1.UpdateOptions{})
+			obj, err = kc.AppsV1().StatefulSets(b.NS()).Update(ctx, obj, metav1.UpdateOptions{})
 			metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "deployments-update", err)
+		}
 
+		if err == nil && pendingRevision != "" {
+			err = deleteStuckStatefulSetPods(ctx, kc, obj, pendingRevision)
 		}
 	case errors.IsNotFound(err):
 		obj, err = b.Create()
+		if err == nil && restartedAt != "" {
+			obj.Spec.Template.Annotations = withRestartedAt(obj.Spec.Template.Annotations, restartedAt)
+		}
 		if err == nil {
 			_, err = kc.AppsV1().StatefulSets(b.NS()).Create(ctx, obj, metav1.CreateOptions{})
 			metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "deployments-create", err)
@@ -132,6 +188,57 @@ func applyStatefulSet(ctx context.Context, kc kubernetes.Interface, b builder.St
 	return err
 }
 
+// deleteStuckStatefulSetPods deletes pods of the revision which are not ready.
+// StatefulSet controller waits for such pod to become ready before it replaces it,
+// so rolling back (or fixing) broken revision never takes effect on its own
+func deleteStuckStatefulSetPods(ctx context.Context, kc kubernetes.Interface, obj *appsv1.StatefulSet, revision string) error {
+	if obj.Spec.Selector == nil {
+		return nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(obj.Spec.Selector)
+	if err != nil {
+		return err
+	}
+
+	req, err := labels.NewRequirement(appsv1.ControllerRevisionHashLabelKey, selection.Equals, []string{revision})
+	if err != nil {
+		return err
+	}
+
+	pods, err := kc.CoreV1().Pods(obj.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.Add(*req).String(),
+	})
+	metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "pods-list", err)
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods.Items {
+		if podReady(pod) {
+			continue
+		}
+
+		err = kc.CoreV1().Pods(obj.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+		metricsutils.IncCounterVecWithLabelValuesFiltered(kubeCallsCounter, "pods-delete", err, errors.IsNotFound)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func podReady(pod corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
 func applyService(ctx context.Context, kc kubernetes.Interface, b builder.Service) error {
 	obj, err := kc.CoreV1().Services(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
 	metricsutils.IncCounterVecWithLabelValuesFiltered(kubeCallsCounter, "services-get", err, errors.IsNotFound)
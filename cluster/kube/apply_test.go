@@ -0,0 +1,64 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeleteStuckStatefulSetPods(t *testing.T) {
+	const ns = "lease"
+
+	selector := map[string]string{"app": "db"}
+
+	pod := func(name, revision string, ready corev1.ConditionStatus) *corev1.Pod {
+		labels := map[string]string{appsv1.ControllerRevisionHashLabelKey: revision}
+		for k, v := range selector {
+			labels[k] = v
+		}
+
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: labels},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+
+	sset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: ns},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+		},
+	}
+
+	other := pod("other-0", "broken", corev1.ConditionFalse)
+	other.Labels["app"] = "web"
+
+	kc := kubefake.NewSimpleClientset(
+		pod("db-0", "good", corev1.ConditionTrue),
+		pod("db-1", "good", corev1.ConditionFalse),
+		pod("db-2", "broken", corev1.ConditionFalse),
+		pod("db-3", "broken", corev1.ConditionTrue),
+		other,
+	)
+
+	ctx := context.Background()
+	require.NoError(t, deleteStuckStatefulSetPods(ctx, kc, sset, "broken"))
+
+	pods, err := kc.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		names = append(names, pod.Name)
+	}
+
+	// only not ready pod of the broken revision is deleted
+	require.ElementsMatch(t, []string{"db-0", "db-1", "db-3", "other-0"}, names)
+}
@@ -33,6 +33,7 @@ const (
 	AkashLeaseOSeqLabelName       = "akash.network/lease.id.oseq"
 	AkashLeaseProviderLabelName   = "akash.network/lease.id.provider"
 	AkashLeaseManifestVersion     = "akash.network/manifest.version"
+	AkashVolumeSnapshotLabelName  = "akash.network/snapshot"
 )
 
 const (
@@ -31,6 +31,11 @@ func NewDeployment(workload Workload) Deployment {
 func (b *deployment) Create() (*appsv1.Deployment, error) { // nolint:golint,unparam
 	falseValue := false
 
+	container, err := b.container()
+	if err != nil {
+		return nil, err
+	}
+
 	kdeployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   b.Name(),
@@ -40,20 +45,22 @@ func (b *deployment) Create() (*appsv1.Deployment, error) { // nolint:golint,unp
 			Selector: &metav1.LabelSelector{
 				MatchLabels: b.labels(),
 			},
-			Replicas: b.replicas(),
+			Replicas:                b.replicas(),
+			Strategy:                b.strategy(),
+			ProgressDeadlineSeconds: b.progressDeadline(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: b.labels(),
+					Labels:      b.labels(),
+					Annotations: b.podAnnotations(nil),
 				},
 				Spec: corev1.PodSpec{
-					Affinity:         b.affinity(),
-					RuntimeClassName: b.runtimeClass(),
-					SecurityContext: &corev1.PodSecurityContext{
-						RunAsNonRoot: &falseValue,
-					},
+					Affinity:                     b.affinity(),
+					RuntimeClassName:             b.runtimeClass(),
+					SecurityContext:              b.podSecurityContext(),
 					AutomountServiceAccountToken: &falseValue,
-					Containers:                   []corev1.Container{b.container()},
+					Containers:                   []corev1.Container{container},
 					ImagePullSecrets:             b.imagePullSecrets(),
+					Volumes:                      b.volumes(),
 				},
 			},
 		},
@@ -63,14 +70,54 @@ func (b *deployment) Create() (*appsv1.Deployment, error) { // nolint:golint,unp
 }
 
 func (b *deployment) Update(obj *appsv1.Deployment) (*appsv1.Deployment, error) { // nolint:golint,unparam
+	container, err := b.container()
+	if err != nil {
+		return nil, err
+	}
+
 	obj.Labels = b.labels()
 	obj.Spec.Selector.MatchLabels = b.labels()
 	obj.Spec.Replicas = b.replicas()
+	obj.Spec.Strategy = b.strategy()
+	obj.Spec.ProgressDeadlineSeconds = b.progressDeadline()
 	obj.Spec.Template.Labels = b.labels()
+	obj.Spec.Template.Annotations = b.podAnnotations(obj.Spec.Template.Annotations)
 	obj.Spec.Template.Spec.Affinity = b.affinity()
 	obj.Spec.Template.Spec.RuntimeClassName = b.runtimeClass()
-	obj.Spec.Template.Spec.Containers = []corev1.Container{b.container()}
+	obj.Spec.Template.Spec.SecurityContext = b.podSecurityContext()
+	obj.Spec.Template.Spec.Containers = []corev1.Container{container}
 	obj.Spec.Template.Spec.ImagePullSecrets = b.imagePullSecrets()
+	obj.Spec.Template.Spec.Volumes = b.volumes()
 
 	return obj, nil
 }
+
+// strategy returns rolling update strategy, so failed update leaves part of old pods running
+func (b *deployment) strategy() appsv1.DeploymentStrategy {
+	rolling := &appsv1.RollingUpdateDeployment{}
+
+	if val := b.settings.Rollout.MaxSurge; val != nil {
+		surge := *val
+		rolling.MaxSurge = &surge
+	}
+
+	if val := b.settings.Rollout.MaxUnavailable; val != nil {
+		unavailable := *val
+		rolling.MaxUnavailable = &unavailable
+	}
+
+	return appsv1.DeploymentStrategy{
+		Type:          appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: rolling,
+	}
+}
+
+func (b *deployment) progressDeadline() *int32 {
+	if b.settings.Rollout.ProgressDeadline == 0 {
+		return nil
+	}
+
+	val := durationSeconds(b.settings.Rollout.ProgressDeadline)
+
+	return &val
+}
@@ -1,14 +1,22 @@
 package builder
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 
+	manitypes "github.com/akash-network/akash-api/go/manifest/v2beta2"
+	"github.com/akash-network/akash-api/go/node/types/unit"
+	types "github.com/akash-network/akash-api/go/node/types/v1beta3"
 	"github.com/akash-network/node/sdl"
 	"github.com/akash-network/node/testutil"
 
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 )
 
@@ -40,7 +48,8 @@ func TestDeploySetsEnvironmentVariables(t *testing.T) {
 
 	dbuilder := deploymentBuilder.(*deployment)
 
-	container := dbuilder.container()
+	container, err := dbuilder.container()
+	require.NoError(t, err)
 	require.NotNil(t, container)
 
 	env := make(map[string]string)
@@ -72,3 +81,152 @@ func TestDeploySetsEnvironmentVariables(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, lid.Provider, value)
 }
+
+func TestDeployRAMVolume(t *testing.T) {
+	log := testutil.Logger(t)
+
+	sdl, err := sdl.ReadFile("../../../testdata/deployment/deployment.yaml")
+	require.NoError(t, err)
+
+	mani, err := sdl.Manifest()
+	require.NoError(t, err)
+
+	group := mani.GetGroups()[0]
+
+	service := &group.Services[0]
+	service.Resources.Storage = append(service.Resources.Storage, types.Storage{
+		Name:     "shm",
+		Quantity: types.NewResourceValue(64 * unit.Mi),
+		Attributes: types.Attributes{
+			{Key: "class", Value: ctypes.StorageClassRAM},
+			{Key: "persistent", Value: "false"},
+		},
+	})
+	service.Params = &manitypes.ServiceParams{
+		Storage: []manitypes.StorageParams{
+			{
+				Name:  "shm",
+				Mount: "/dev/shm",
+			},
+		},
+	}
+
+	cdep := &ClusterDeployment{
+		Lid:     testutil.LeaseID(t),
+		Group:   &group,
+		Sparams: crd.ClusterSettings{SchedulerParams: make([]*crd.SchedulerParams, len(group.Services))},
+	}
+
+	obj, err := NewDeployment(NewWorkloadBuilder(log, NewDefaultSettings(), cdep, 0)).Create()
+	require.NoError(t, err)
+
+	volumes := obj.Spec.Template.Spec.Volumes
+	require.Len(t, volumes, 1)
+	require.Equal(t, "web-shm", volumes[0].Name)
+	require.NotNil(t, volumes[0].EmptyDir)
+	require.Equal(t, corev1.StorageMediumMemory, volumes[0].EmptyDir.Medium)
+	require.Equal(t, int64(64*unit.Mi), volumes[0].EmptyDir.SizeLimit.Value())
+
+	container := obj.Spec.Template.Spec.Containers[0]
+	require.Len(t, container.VolumeMounts, 1)
+	require.Equal(t, "web-shm", container.VolumeMounts[0].Name)
+
+	// volume size is charged to memory of the container, ephemeral storage is left as is
+	mem := container.Resources.Limits[corev1.ResourceMemory]
+	require.Equal(t, int64(128*unit.Mi+64*unit.Mi), mem.Value())
+
+	storage := container.Resources.Limits[corev1.ResourceEphemeralStorage]
+	require.Equal(t, int64(512*unit.Mi), storage.Value())
+}
+
+func TestDeployRAMVolumeFromSDL(t *testing.T) {
+	buf, err := os.ReadFile("../../../testdata/deployment/deployment-v2-ram.yaml")
+	require.NoError(t, err)
+
+	// SDL of akash-network/node v0.23 does not allow class on ephemeral storage yet
+	_, err = sdl.Read(buf)
+	require.ErrorContains(t, err, "ephemeral storage should not set attribute class")
+
+	// until it does, read SDL without the class and set it on the manifest the way SDL would
+	sdef, err := sdl.Read(bytes.Replace(buf, []byte("class: ram"), []byte("persistent: false"), 1))
+	require.NoError(t, err)
+
+	mani, err := sdef.Manifest()
+	require.NoError(t, err)
+
+	group := mani.GetGroups()[0]
+
+	service := &group.Services[0]
+	for i := range service.Resources.Storage {
+		if service.Resources.Storage[i].Name == "shm" {
+			service.Resources.Storage[i].Attributes = append(types.Attributes{
+				{Key: sdl.StorageAttributeClass, Value: ctypes.StorageClassRAM},
+			}, service.Resources.Storage[i].Attributes...)
+		}
+	}
+
+	cdep := &ClusterDeployment{
+		Lid:     testutil.LeaseID(t),
+		Group:   &group,
+		Sparams: crd.ClusterSettings{SchedulerParams: make([]*crd.SchedulerParams, len(group.Services))},
+	}
+
+	obj, err := NewDeployment(NewWorkloadBuilder(testutil.Logger(t), NewDefaultSettings(), cdep, 0)).Create()
+	require.NoError(t, err)
+
+	volumes := obj.Spec.Template.Spec.Volumes
+	require.Len(t, volumes, 1)
+	require.Equal(t, "web-shm", volumes[0].Name)
+	require.NotNil(t, volumes[0].EmptyDir)
+	require.Equal(t, corev1.StorageMediumMemory, volumes[0].EmptyDir.Medium)
+
+	container := obj.Spec.Template.Spec.Containers[0]
+	require.Len(t, container.VolumeMounts, 1)
+	require.Equal(t, "/dev/shm", container.VolumeMounts[0].MountPath)
+
+	mem := container.Resources.Limits[corev1.ResourceMemory]
+	require.Equal(t, int64(128*unit.Mi+64*unit.Mi), mem.Value())
+}
+
+func TestDeployRolloutStrategy(t *testing.T) {
+	log := testutil.Logger(t)
+
+	sdl, err := sdl.ReadFile("../../../testdata/deployment/deployment.yaml")
+	require.NoError(t, err)
+
+	mani, err := sdl.Manifest()
+	require.NoError(t, err)
+
+	group := mani.GetGroups()[0]
+	group.Services[0].Count = 4
+
+	cdep := &ClusterDeployment{
+		Lid:     testutil.LeaseID(t),
+		Group:   &group,
+		Sparams: crd.ClusterSettings{SchedulerParams: make([]*crd.SchedulerParams, len(group.Services))},
+	}
+
+	settings := NewDefaultSettings()
+	settings.Rollout.MaxSurge = rolloutValue("2")
+	settings.Rollout.MaxUnavailable = rolloutValue("0")
+
+	obj, err := NewDeployment(NewWorkloadBuilder(log, settings, cdep, 0)).Create()
+	require.NoError(t, err)
+
+	require.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, obj.Spec.Strategy.Type)
+	require.Equal(t, "2", obj.Spec.Strategy.RollingUpdate.MaxSurge.String())
+	require.Equal(t, "0", obj.Spec.Strategy.RollingUpdate.MaxUnavailable.String())
+	require.Equal(t, int32(600), *obj.Spec.ProgressDeadlineSeconds)
+
+	// quota leaves room for surge pods
+	quota, err := BuildResourceQuota(settings, cdep).Create()
+	require.NoError(t, err)
+	require.Equal(t, int64(6), quota.Spec.Hard.Pods().Value())
+
+	sset, err := BuildStatefulSet(NewWorkloadBuilder(log, settings, cdep, 0)).Create()
+	require.NoError(t, err)
+	require.Equal(t, appsv1.RollingUpdateStatefulSetStrategyType, sset.Spec.UpdateStrategy.Type)
+
+	settings.Rollout.MaxSurge = rolloutValue("0")
+	require.ErrorIs(t, ValidateSettings(settings), ErrSettingsValidation)
+}
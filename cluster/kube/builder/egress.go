@@ -0,0 +1,335 @@
+package builder
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var (
+	ErrInvalidEgressPolicy = errors.New("invalid egress policy")
+)
+
+var (
+	// private and link-local (cloud metadata) ranges are never reachable by tenants
+	defaultDeniedIPv4CIDRs = []string{
+		"10.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+	}
+
+	// unique local and link-local ranges
+	defaultDeniedIPv6CIDRs = []string{
+		"fc00::/7",
+		"fe80::/10",
+	}
+)
+
+const (
+	maxPort = 65535
+)
+
+// EgressPortConfig is the destination port. Empty protocol matches both tcp and udp
+type EgressPortConfig struct {
+	Port     uint16 `json:"port" yaml:"port"`
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+}
+
+// EgressServiceConfig is the in-cluster service tenants are allowed to reach,
+// e.g. a registry mirror or a cache. Empty selector matches all pods of the namespace
+// and empty ports allow every port
+type EgressServiceConfig struct {
+	Namespace string             `json:"namespace" yaml:"namespace"`
+	Selector  map[string]string  `json:"selector,omitempty" yaml:"selector,omitempty"`
+	Ports     []EgressPortConfig `json:"ports,omitempty" yaml:"ports,omitempty"`
+}
+
+// EgressPolicyConfig is the egress section of the provider config file.
+// Private and link-local ranges are always denied in addition to DeniedCIDRs
+type EgressPolicyConfig struct {
+	// DeniedCIDRs are public ranges tenants cannot reach, both IPv4 and IPv6 ranges are accepted
+	DeniedCIDRs []string `json:"denied_cidrs,omitempty" yaml:"denied_cidrs,omitempty"`
+	// IPv6 allows access to public IPv6 addresses
+	IPv6 bool `json:"ipv6,omitempty" yaml:"ipv6,omitempty"`
+	// DeniedPorts are destination ports tenants cannot connect to on public addresses, e.g. smtp 25
+	DeniedPorts []EgressPortConfig `json:"denied_ports,omitempty" yaml:"denied_ports,omitempty"`
+	// AllowedServices are in-cluster services tenants can connect to
+	AllowedServices []EgressServiceConfig `json:"allowed_services,omitempty" yaml:"allowed_services,omitempty"`
+}
+
+// EgressPolicy holds egress rules applied to every lease namespace.
+// It is safe to update while leases are being deployed
+type EgressPolicy struct {
+	lock  sync.RWMutex
+	rules []netv1.NetworkPolicyEgressRule
+}
+
+func NewEgressPolicy(cfg EgressPolicyConfig) (*EgressPolicy, error) {
+	ep := &EgressPolicy{}
+
+	if _, err := ep.Update(cfg); err != nil {
+		return nil, err
+	}
+
+	return ep, nil
+}
+
+// Update replaces rules of the policy and reports whether they have changed.
+// Policy is left unchanged if config is invalid
+func (ep *EgressPolicy) Update(cfg EgressPolicyConfig) (bool, error) {
+	rules, err := cfg.rules()
+	if err != nil {
+		return false, err
+	}
+
+	ep.lock.Lock()
+	defer ep.lock.Unlock()
+
+	changed := !reflect.DeepEqual(ep.rules, rules)
+	ep.rules = rules
+
+	return changed, nil
+}
+
+// egressRules returns copy of the policy rules. Nil policy has default rules
+func (ep *EgressPolicy) egressRules() []netv1.NetworkPolicyEgressRule {
+	if ep == nil {
+		rules, _ := EgressPolicyConfig{}.rules()
+		return rules
+	}
+
+	ep.lock.RLock()
+	defer ep.lock.RUnlock()
+
+	res := make([]netv1.NetworkPolicyEgressRule, 0, len(ep.rules))
+	for i := range ep.rules {
+		res = append(res, *ep.rules[i].DeepCopy())
+	}
+
+	return res
+}
+
+func parseEgressProtocols(proto string) ([]corev1.Protocol, error) {
+	switch strings.ToLower(proto) {
+	case "":
+		return []corev1.Protocol{corev1.ProtocolTCP, corev1.ProtocolUDP}, nil
+	case "tcp":
+		return []corev1.Protocol{corev1.ProtocolTCP}, nil
+	case "udp":
+		return []corev1.Protocol{corev1.ProtocolUDP}, nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported protocol %q", ErrInvalidEgressPolicy, proto)
+}
+
+// parseDeniedCIDRs splits configured ranges by address family and merges them with the defaults
+func parseDeniedCIDRs(cidrs []string) ([]string, []string, error) {
+	ipv4 := append([]string{}, defaultDeniedIPv4CIDRs...)
+	ipv6 := append([]string{}, defaultDeniedIPv6CIDRs...)
+
+	for _, cidr := range cidrs {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: denied cidr %q: %s", ErrInvalidEgressPolicy, cidr, err)
+		}
+
+		ones, _ := ipnet.Mask.Size()
+		if ones == 0 {
+			return nil, nil, fmt.Errorf("%w: denied cidr %q covers all addresses", ErrInvalidEgressPolicy, cidr)
+		}
+
+		if ipnet.IP.To4() != nil {
+			ipv4 = append(ipv4, ipnet.String())
+		} else {
+			ipv6 = append(ipv6, ipnet.String())
+		}
+	}
+
+	return dedupStrings(ipv4), dedupStrings(ipv6), nil
+}
+
+func dedupStrings(vals []string) []string {
+	sort.Strings(vals)
+
+	res := vals[:0]
+	for i, val := range vals {
+		if i == 0 || val != vals[i-1] {
+			res = append(res, val)
+		}
+	}
+
+	return res
+}
+
+// publicPorts returns ports of public destinations. Network policies can only allow traffic,
+// so denied ports are excluded by allowing ranges between them. Nil result allows every port
+func publicPorts(denied []EgressPortConfig) ([]netv1.NetworkPolicyPort, error) {
+	if len(denied) == 0 {
+		return nil, nil
+	}
+
+	deniedByProto := map[corev1.Protocol]map[int32]bool{
+		corev1.ProtocolTCP: {},
+		corev1.ProtocolUDP: {},
+	}
+
+	for _, port := range denied {
+		if port.Port == 0 {
+			return nil, fmt.Errorf("%w: denied port cannot be 0", ErrInvalidEgressPolicy)
+		}
+
+		protos, err := parseEgressProtocols(port.Protocol)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, proto := range protos {
+			deniedByProto[proto][int32(port.Port)] = true
+		}
+	}
+
+	var ports []netv1.NetworkPolicyPort // nolint:prealloc
+
+	for _, proto := range []corev1.Protocol{corev1.ProtocolTCP, corev1.ProtocolUDP} {
+		proto := proto
+
+		if len(deniedByProto[proto]) == 0 {
+			ports = append(ports, netv1.NetworkPolicyPort{Protocol: &proto})
+			continue
+		}
+
+		start := int32(1)
+		for port := int32(1); port <= maxPort+1; port++ {
+			if port <= maxPort && !deniedByProto[proto][port] {
+				continue
+			}
+
+			if start < port {
+				from := intstr.FromInt(int(start))
+				entry := netv1.NetworkPolicyPort{
+					Protocol: &proto,
+					Port:     &from,
+				}
+
+				if end := port - 1; end > start {
+					entry.EndPort = &end
+				}
+
+				ports = append(ports, entry)
+			}
+
+			start = port + 1
+		}
+	}
+
+	return ports, nil
+}
+
+func (esc EgressServiceConfig) rule() (netv1.NetworkPolicyEgressRule, error) {
+	if esc.Namespace == "" {
+		return netv1.NetworkPolicyEgressRule{}, fmt.Errorf("%w: allowed service must specify namespace", ErrInvalidEgressPolicy)
+	}
+
+	peer := netv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"kubernetes.io/metadata.name": esc.Namespace,
+			},
+		},
+	}
+
+	if len(esc.Selector) != 0 {
+		peer.PodSelector = &metav1.LabelSelector{
+			MatchLabels: esc.Selector,
+		}
+	}
+
+	rule := netv1.NetworkPolicyEgressRule{
+		To: []netv1.NetworkPolicyPeer{peer},
+	}
+
+	for _, port := range esc.Ports {
+		if port.Port == 0 {
+			return netv1.NetworkPolicyEgressRule{}, fmt.Errorf("%w: allowed service %q port cannot be 0", ErrInvalidEgressPolicy, esc.Namespace)
+		}
+
+		protos, err := parseEgressProtocols(port.Protocol)
+		if err != nil {
+			return netv1.NetworkPolicyEgressRule{}, err
+		}
+
+		for _, proto := range protos {
+			proto := proto
+			val := intstr.FromInt(int(port.Port))
+
+			rule.Ports = append(rule.Ports, netv1.NetworkPolicyPort{
+				Protocol: &proto,
+				Port:     &val,
+			})
+		}
+	}
+
+	return rule, nil
+}
+
+// rules returns egress rules to public addresses and allowed in-cluster services
+func (cfg EgressPolicyConfig) rules() ([]netv1.NetworkPolicyEgressRule, error) {
+	ipv4, ipv6, err := parseDeniedCIDRs(cfg.DeniedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	ports, err := publicPorts(cfg.DeniedPorts)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []netv1.NetworkPolicyEgressRule{
+		{ // Allow access to IPV4 Public addresses only
+			Ports: ports,
+			To: []netv1.NetworkPolicyPeer{
+				{
+					IPBlock: &netv1.IPBlock{
+						CIDR:   "0.0.0.0/0",
+						Except: ipv4,
+					},
+				},
+			},
+		},
+	}
+
+	if cfg.IPv6 {
+		rules = append(rules, netv1.NetworkPolicyEgressRule{ // Allow access to IPV6 Public addresses only
+			Ports: ports,
+			To: []netv1.NetworkPolicyPeer{
+				{
+					IPBlock: &netv1.IPBlock{
+						CIDR:   "::/0",
+						Except: ipv6,
+					},
+				},
+			},
+		})
+	}
+
+	for _, svc := range cfg.AllowedServices {
+		rule, err := svc.rule()
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,130 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/akash-network/node/sdl"
+	"github.com/akash-network/node/testutil"
+
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+func makeEgressDeployment(t *testing.T) *ClusterDeployment {
+	sdl, err := sdl.ReadFile("../../../testdata/deployment/deployment.yaml")
+	require.NoError(t, err)
+
+	mani, err := sdl.Manifest()
+	require.NoError(t, err)
+
+	group := mani.GetGroups()[0]
+
+	return &ClusterDeployment{
+		Lid:     testutil.LeaseID(t),
+		Group:   &group,
+		Sparams: crd.ClusterSettings{SchedulerParams: make([]*crd.SchedulerParams, len(group.Services))},
+	}
+}
+
+func TestNetPolDefaultEgress(t *testing.T) {
+	settings := NewDefaultSettings()
+	settings.NetworkPoliciesEnabled = true
+
+	policies, err := BuildNetPol(settings, makeEgressDeployment(t)).Create()
+	require.NoError(t, err)
+
+	egress := policies[0].Spec.Egress
+	require.Len(t, egress, 3)
+
+	public := egress[2]
+	require.Nil(t, public.Ports)
+	require.Equal(t, "0.0.0.0/0", public.To[0].IPBlock.CIDR)
+	require.Contains(t, public.To[0].IPBlock.Except, "169.254.0.0/16")
+	require.Contains(t, public.To[0].IPBlock.Except, "10.0.0.0/8")
+}
+
+func TestNetPolEgressPolicy(t *testing.T) {
+	policy, err := NewEgressPolicy(EgressPolicyConfig{
+		DeniedCIDRs: []string{"203.0.113.0/24", "2001:db8::/32"},
+		IPv6:        true,
+		DeniedPorts: []EgressPortConfig{
+			{Port: 25, Protocol: "tcp"},
+			{Port: 65535, Protocol: "tcp"},
+		},
+		AllowedServices: []EgressServiceConfig{
+			{
+				Namespace: "registry",
+				Selector:  map[string]string{"app": "mirror"},
+				Ports:     []EgressPortConfig{{Port: 5000, Protocol: "tcp"}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	settings := NewDefaultSettings()
+	settings.NetworkPoliciesEnabled = true
+	settings.EgressPolicy = policy
+
+	cdep := makeEgressDeployment(t)
+
+	policies, err := BuildNetPol(settings, cdep).Create()
+	require.NoError(t, err)
+
+	egress := policies[0].Spec.Egress
+	require.Len(t, egress, 5)
+
+	ipv4 := egress[2]
+	require.Equal(t, "0.0.0.0/0", ipv4.To[0].IPBlock.CIDR)
+	require.Contains(t, ipv4.To[0].IPBlock.Except, "203.0.113.0/24")
+	require.NotContains(t, ipv4.To[0].IPBlock.Except, "2001:db8::/32")
+
+	// tcp 1-24 and 26-65534, all of udp
+	require.Len(t, ipv4.Ports, 3)
+	require.Equal(t, corev1.ProtocolTCP, *ipv4.Ports[0].Protocol)
+	require.Equal(t, int32(1), ipv4.Ports[0].Port.IntVal)
+	require.Equal(t, int32(24), *ipv4.Ports[0].EndPort)
+	require.Equal(t, int32(26), ipv4.Ports[1].Port.IntVal)
+	require.Equal(t, int32(65534), *ipv4.Ports[1].EndPort)
+	require.Equal(t, corev1.ProtocolUDP, *ipv4.Ports[2].Protocol)
+	require.Nil(t, ipv4.Ports[2].Port)
+
+	ipv6 := egress[3]
+	require.Equal(t, "::/0", ipv6.To[0].IPBlock.CIDR)
+	require.Contains(t, ipv6.To[0].IPBlock.Except, "2001:db8::/32")
+	require.Contains(t, ipv6.To[0].IPBlock.Except, "fe80::/10")
+	require.Equal(t, ipv4.Ports, ipv6.Ports)
+
+	svc := egress[4]
+	require.Equal(t, "registry", svc.To[0].NamespaceSelector.MatchLabels["kubernetes.io/metadata.name"])
+	require.Equal(t, "mirror", svc.To[0].PodSelector.MatchLabels["app"])
+	require.Len(t, svc.Ports, 1)
+	require.Equal(t, int32(5000), svc.Ports[0].Port.IntVal)
+
+	// invalid config leaves policy intact
+	_, err = policy.Update(EgressPolicyConfig{DeniedCIDRs: []string{"foo"}})
+	require.ErrorIs(t, err, ErrInvalidEgressPolicy)
+
+	_, err = policy.Update(EgressPolicyConfig{DeniedPorts: []EgressPortConfig{{Port: 25, Protocol: "sctp"}}})
+	require.ErrorIs(t, err, ErrInvalidEgressPolicy)
+
+	policies, err = BuildNetPol(settings, cdep).Create()
+	require.NoError(t, err)
+	require.Len(t, policies[0].Spec.Egress, 5)
+
+	// updates are picked up by the next build
+	changed, err := policy.Update(EgressPolicyConfig{})
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	changed, err = policy.Update(EgressPolicyConfig{})
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	policies, err = BuildNetPol(settings, cdep).Create()
+	require.NoError(t, err)
+
+	var defaults *EgressPolicy
+	require.Equal(t, defaults.egressRules(), policies[0].Spec.Egress[2:])
+}
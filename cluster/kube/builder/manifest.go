@@ -52,7 +52,11 @@ func (b *manifest) Update(obj *crd.Manifest) (*crd.Manifest, error) {
 	if err != nil {
 		return nil, err
 	}
+	// last good group is kept until the new one becomes healthy
+	lastGood := obj.Spec.LastGood
+
 	obj.Spec = m.Spec
+	obj.Spec.LastGood = lastGood
 	obj.Labels = b.labels()
 	return obj, nil
 }
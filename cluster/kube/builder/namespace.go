@@ -22,7 +22,13 @@ func BuildNS(settings Settings, deployment IClusterDeployment) NS {
 }
 
 func (b *ns) labels() map[string]string {
-	return AppendLeaseLabels(b.deployment.LeaseID(), b.builder.labels())
+	labels := AppendLeaseLabels(b.deployment.LeaseID(), b.builder.labels())
+
+	for key, val := range b.settings.PodSecurity.podSecurityLabels() {
+		labels[key] = val
+	}
+
+	return labels
 }
 
 func (b *ns) Create() (*corev1.Namespace, error) { // nolint:golint,unparam
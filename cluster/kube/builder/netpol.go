@@ -119,27 +119,13 @@ func (b *netPol) Create() ([]*netv1.NetworkPolicy, error) { // nolint:golint,unp
 							},
 						},
 					},
-					{ // Allow access to IPV4 Public addresses only
-						To: []netv1.NetworkPolicyPeer{
-							{
-								PodSelector:       nil,
-								NamespaceSelector: nil,
-								IPBlock: &netv1.IPBlock{
-									CIDR: "0.0.0.0/0",
-									Except: []string{
-										"10.0.0.0/8",
-										"192.168.0.0/16",
-										"172.16.0.0/12",
-									},
-								},
-							},
-						},
-					},
 				},
 			},
 		},
 	}
 
+	result[0].Spec.Egress = append(result[0].Spec.Egress, b.settings.EgressPolicy.egressRules()...)
+
 	for _, service := range b.deployment.ManifestGroup().Services {
 		// find all the ports that are exposed directly
 		ports := make([]netv1.NetworkPolicyPort, 0)
@@ -0,0 +1,200 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodSecurityLevel is the Pod Security Standard enforced on lease namespaces by Pod Security Admission
+type PodSecurityLevel string
+
+const (
+	PodSecurityLevelNone       = PodSecurityLevel("")
+	PodSecurityLevelPrivileged = PodSecurityLevel("privileged")
+	PodSecurityLevelBaseline   = PodSecurityLevel("baseline")
+	PodSecurityLevelRestricted = PodSecurityLevel("restricted")
+)
+
+const (
+	podSecurityLabelPrefix = "pod-security.kubernetes.io/"
+	podSecurityVersion     = "latest"
+
+	appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+	appArmorRuntimeDefault   = "runtime/default"
+)
+
+// safeSysctls are namespaced sysctls allowed by the baseline Pod Security Standard
+var safeSysctls = map[string]bool{
+	"kernel.shm_rmid_forced":              true,
+	"net.ipv4.ip_local_port_range":        true,
+	"net.ipv4.ip_unprivileged_port_start": true,
+	"net.ipv4.tcp_syncookies":             true,
+	"net.ipv4.ping_group_range":           true,
+}
+
+// PodSecuritySettings configures hardening of tenant workloads
+type PodSecuritySettings struct {
+	// Level labels lease namespaces with Pod Security Standard. Namespaces are not labeled when empty
+	Level PodSecurityLevel
+	// Audit only reports violations of the level with audit annotations and warnings, pods are not rejected
+	Audit bool
+
+	// RunAsNonRoot rejects containers running as root
+	RunAsNonRoot bool
+	// SeccompRuntimeDefault runs containers with seccomp profile of the container runtime
+	SeccompRuntimeDefault bool
+	// AppArmorRuntimeDefault runs containers with AppArmor profile of the container runtime.
+	// Every node must have AppArmor enabled
+	AppArmorRuntimeDefault bool
+	// DropCapabilities are removed from containers, e.g. ALL
+	DropCapabilities []corev1.Capability
+	// ReadOnlyRootFilesystem mounts root filesystem of containers as read only
+	ReadOnlyRootFilesystem bool
+	// Sysctls are set on every tenant pod
+	Sysctls []corev1.Sysctl
+}
+
+// ParseSysctls parses sysctls in name=value form
+func ParseSysctls(vals []string) ([]corev1.Sysctl, error) {
+	res := make([]corev1.Sysctl, 0, len(vals))
+
+	for _, val := range vals {
+		parts := strings.SplitN(val, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("%w: invalid sysctl %q, expected name=value", ErrSettingsValidation, val)
+		}
+
+		res = append(res, corev1.Sysctl{
+			Name:  strings.TrimSpace(parts[0]),
+			Value: strings.TrimSpace(parts[1]),
+		})
+	}
+
+	return res, nil
+}
+
+func (s PodSecuritySettings) dropsAllCapabilities() bool {
+	for _, capability := range s.DropCapabilities {
+		if capability == "ALL" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s PodSecuritySettings) validate() error {
+	switch s.Level {
+	case PodSecurityLevelNone, PodSecurityLevelPrivileged, PodSecurityLevelBaseline, PodSecurityLevelRestricted:
+	default:
+		return fmt.Errorf("%w: invalid pod security level %q", ErrSettingsValidation, s.Level)
+	}
+
+	for _, sysctl := range s.Sysctls {
+		if sysctl.Name == "" {
+			return fmt.Errorf("%w: empty sysctl name", ErrSettingsValidation)
+		}
+	}
+
+	// enforced levels would reject every tenant pod
+	if s.Audit || s.Level == PodSecurityLevelNone || s.Level == PodSecurityLevelPrivileged {
+		return nil
+	}
+
+	for _, sysctl := range s.Sysctls {
+		if !safeSysctls[sysctl.Name] {
+			return fmt.Errorf("%w: sysctl %q is not allowed by %s pod security level", ErrSettingsValidation, sysctl.Name, s.Level)
+		}
+	}
+
+	if s.Level == PodSecurityLevelRestricted && (!s.RunAsNonRoot || !s.SeccompRuntimeDefault || !s.dropsAllCapabilities()) {
+		return fmt.Errorf("%w: restricted pod security level requires non root containers, runtime default seccomp profile and dropped ALL capabilities", ErrSettingsValidation)
+	}
+
+	return nil
+}
+
+// podSecurityLabels returns Pod Security Admission labels of lease namespace.
+// Violations are rejected unless in audit mode and always reported
+func (s PodSecuritySettings) podSecurityLabels() map[string]string {
+	if s.Level == PodSecurityLevelNone {
+		return nil
+	}
+
+	modes := []string{"audit", "warn"}
+	if !s.Audit {
+		modes = append(modes, "enforce")
+	}
+
+	labels := make(map[string]string, len(modes)*2)
+	for _, mode := range modes {
+		labels[podSecurityLabelPrefix+mode] = string(s.Level)
+		labels[podSecurityLabelPrefix+mode+"-version"] = podSecurityVersion
+	}
+
+	return labels
+}
+
+func (b *Workload) podSecurityContext() *corev1.PodSecurityContext {
+	ps := b.settings.PodSecurity
+
+	sctx := &corev1.PodSecurityContext{
+		RunAsNonRoot: &ps.RunAsNonRoot,
+	}
+
+	if ps.SeccompRuntimeDefault {
+		sctx.SeccompProfile = &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		}
+	}
+
+	if len(ps.Sysctls) != 0 {
+		sctx.Sysctls = append([]corev1.Sysctl{}, ps.Sysctls...)
+	}
+
+	return sctx
+}
+
+func (b *Workload) securityContext() *corev1.SecurityContext {
+	falseValue := false
+	ps := b.settings.PodSecurity
+
+	sctx := &corev1.SecurityContext{
+		RunAsNonRoot:             &ps.RunAsNonRoot,
+		Privileged:               &falseValue,
+		AllowPrivilegeEscalation: &falseValue,
+	}
+
+	if ps.ReadOnlyRootFilesystem {
+		sctx.ReadOnlyRootFilesystem = &ps.ReadOnlyRootFilesystem
+	}
+
+	if len(ps.DropCapabilities) != 0 {
+		sctx.Capabilities = &corev1.Capabilities{
+			Drop: append([]corev1.Capability{}, ps.DropCapabilities...),
+		}
+	}
+
+	return sctx
+}
+
+// podAnnotations updates annotations of tenant pods with AppArmor profile, which is set with annotation
+// on clusters older than v1.30. Other annotations are left intact
+func (b *Workload) podAnnotations(annotations map[string]string) map[string]string {
+	key := appArmorAnnotationPrefix + b.Name()
+
+	if !b.settings.PodSecurity.AppArmorRuntimeDefault {
+		delete(annotations, key)
+		return annotations
+	}
+
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[key] = appArmorRuntimeDefault
+
+	return annotations
+}
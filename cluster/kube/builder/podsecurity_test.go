@@ -0,0 +1,114 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/akash-network/node/testutil"
+)
+
+func TestPodSecurityNamespaceLabels(t *testing.T) {
+	cdep := makeEgressDeployment(t)
+
+	settings := NewDefaultSettings()
+
+	ns, err := BuildNS(settings, cdep).Create()
+	require.NoError(t, err)
+	require.NotContains(t, ns.Labels, "pod-security.kubernetes.io/enforce")
+
+	settings.PodSecurity.Level = PodSecurityLevelBaseline
+
+	ns, err = BuildNS(settings, cdep).Update(ns)
+	require.NoError(t, err)
+	require.Equal(t, "baseline", ns.Labels["pod-security.kubernetes.io/enforce"])
+	require.Equal(t, "baseline", ns.Labels["pod-security.kubernetes.io/audit"])
+	require.Equal(t, "baseline", ns.Labels["pod-security.kubernetes.io/warn"])
+	require.Equal(t, "latest", ns.Labels["pod-security.kubernetes.io/enforce-version"])
+
+	// audit mode only reports violations
+	settings.PodSecurity.Audit = true
+
+	ns, err = BuildNS(settings, cdep).Update(ns)
+	require.NoError(t, err)
+	require.NotContains(t, ns.Labels, "pod-security.kubernetes.io/enforce")
+	require.Equal(t, "baseline", ns.Labels["pod-security.kubernetes.io/audit"])
+	require.Equal(t, "baseline", ns.Labels["pod-security.kubernetes.io/warn"])
+}
+
+func TestPodSecurityWorkload(t *testing.T) {
+	log := testutil.Logger(t)
+	cdep := makeEgressDeployment(t)
+
+	settings := NewDefaultSettings()
+	settings.PodSecurity = PodSecuritySettings{
+		Level:                  PodSecurityLevelRestricted,
+		RunAsNonRoot:           true,
+		SeccompRuntimeDefault:  true,
+		AppArmorRuntimeDefault: true,
+		DropCapabilities:       []corev1.Capability{"ALL"},
+		ReadOnlyRootFilesystem: true,
+		Sysctls:                []corev1.Sysctl{{Name: "net.ipv4.tcp_syncookies", Value: "1"}},
+	}
+	require.NoError(t, ValidateSettings(settings))
+
+	workload := NewWorkloadBuilder(log, settings, cdep, 0)
+
+	obj, err := NewDeployment(workload).Create()
+	require.NoError(t, err)
+
+	pod := obj.Spec.Template.Spec
+	require.True(t, *pod.SecurityContext.RunAsNonRoot)
+	require.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, pod.SecurityContext.SeccompProfile.Type)
+	require.Equal(t, settings.PodSecurity.Sysctls, pod.SecurityContext.Sysctls)
+	require.Equal(t, "runtime/default", obj.Spec.Template.Annotations["container.apparmor.security.beta.kubernetes.io/"+workload.Name()])
+
+	sctx := pod.Containers[0].SecurityContext
+	require.True(t, *sctx.RunAsNonRoot)
+	require.False(t, *sctx.AllowPrivilegeEscalation)
+	require.True(t, *sctx.ReadOnlyRootFilesystem)
+	require.Equal(t, []corev1.Capability{"ALL"}, sctx.Capabilities.Drop)
+
+	// disabled profile is removed on update, other annotations are kept
+	obj.Spec.Template.Annotations["foo"] = "bar"
+	settings.PodSecurity = PodSecuritySettings{}
+
+	obj, err = NewDeployment(NewWorkloadBuilder(log, settings, cdep, 0)).Update(obj)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"foo": "bar"}, obj.Spec.Template.Annotations)
+	require.False(t, *obj.Spec.Template.Spec.SecurityContext.RunAsNonRoot)
+	require.Nil(t, obj.Spec.Template.Spec.SecurityContext.SeccompProfile)
+	require.Nil(t, obj.Spec.Template.Spec.Containers[0].SecurityContext.Capabilities)
+}
+
+func TestPodSecurityValidation(t *testing.T) {
+	settings := NewDefaultSettings()
+
+	settings.PodSecurity.Level = "strict"
+	require.ErrorIs(t, ValidateSettings(settings), ErrSettingsValidation)
+
+	// enforced restricted level requires hardened workloads
+	settings.PodSecurity.Level = PodSecurityLevelRestricted
+	require.ErrorIs(t, ValidateSettings(settings), ErrSettingsValidation)
+
+	settings.PodSecurity.Audit = true
+	require.NoError(t, ValidateSettings(settings))
+
+	// unsafe sysctls are rejected by enforced baseline level
+	settings.PodSecurity = PodSecuritySettings{
+		Level:   PodSecurityLevelBaseline,
+		Sysctls: []corev1.Sysctl{{Name: "net.core.somaxconn", Value: "1024"}},
+	}
+	require.ErrorIs(t, ValidateSettings(settings), ErrSettingsValidation)
+
+	settings.PodSecurity.Level = PodSecurityLevelPrivileged
+	require.NoError(t, ValidateSettings(settings))
+
+	sysctls, err := ParseSysctls([]string{"net.ipv4.tcp_syncookies=1"})
+	require.NoError(t, err)
+	require.Equal(t, []corev1.Sysctl{{Name: "net.ipv4.tcp_syncookies", Value: "1"}}, sysctls)
+
+	_, err = ParseSysctls([]string{"net.ipv4.tcp_syncookies"})
+	require.ErrorIs(t, err, ErrSettingsValidation)
+}
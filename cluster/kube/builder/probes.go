@@ -0,0 +1,191 @@
+package builder
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	mani "github.com/akash-network/akash-api/go/manifest/v2beta2"
+
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+var (
+	ErrInvalidProbe = errors.New("invalid probe")
+)
+
+// ProbeSettings configures health probes of tenant containers.
+// Zero limits are not enforced
+type ProbeSettings struct {
+	// DefaultsEnabled generates tcp liveness and readiness probes on the first exposed port
+	// for services which have no probes set
+	DefaultsEnabled bool
+	// ExecEnabled allows exec probes
+	ExecEnabled bool
+
+	MaxInitialDelay     time.Duration
+	MinPeriod           time.Duration
+	MaxTimeout          time.Duration
+	MaxFailureThreshold int32
+}
+
+type workloadProbes struct {
+	liveness  *corev1.Probe
+	readiness *corev1.Probe
+	startup   *corev1.Probe
+}
+
+func (b *Workload) probes() (workloadProbes, error) {
+	service := &b.deployment.ManifestGroup().Services[b.serviceIdx]
+
+	var sprobes *crd.ManifestServiceProbes
+	if cprobes := b.deployment.ClusterParams().Probes; b.serviceIdx < len(cprobes) {
+		sprobes = cprobes[b.serviceIdx]
+	}
+
+	if sprobes == nil && b.settings.Probes.DefaultsEnabled {
+		sprobes = defaultProbes(service)
+	}
+
+	res := workloadProbes{}
+	if sprobes == nil {
+		return res, nil
+	}
+
+	var err error
+
+	if res.liveness, err = b.probe(service, "liveness", sprobes.Liveness); err != nil {
+		return workloadProbes{}, err
+	}
+
+	if res.readiness, err = b.probe(service, "readiness", sprobes.Readiness); err != nil {
+		return workloadProbes{}, err
+	}
+
+	if res.startup, err = b.probe(service, "startup", sprobes.Startup); err != nil {
+		return workloadProbes{}, err
+	}
+
+	return res, nil
+}
+
+// defaultProbes returns tcp probes on the first exposed tcp port or nil if service exposes none
+func defaultProbes(service *mani.Service) *crd.ManifestServiceProbes {
+	for _, expose := range service.Expose {
+		if expose.Proto != mani.TCP {
+			continue
+		}
+
+		return &crd.ManifestServiceProbes{
+			Liveness: &crd.ManifestServiceProbe{
+				Type: crd.ProbeTypeTCP,
+				Port: uint16(expose.Port),
+			},
+			Readiness: &crd.ManifestServiceProbe{
+				Type: crd.ProbeTypeTCP,
+				Port: uint16(expose.Port),
+			},
+		}
+	}
+
+	return nil
+}
+
+func (b *Workload) probe(service *mani.Service, kind string, params *crd.ManifestServiceProbe) (*corev1.Probe, error) {
+	if params == nil {
+		return nil, nil
+	}
+
+	port := params.Port
+	if port == 0 {
+		for _, expose := range service.Expose {
+			if expose.Proto == mani.TCP {
+				port = uint16(expose.Port)
+				break
+			}
+		}
+	}
+
+	probe := &corev1.Probe{
+		InitialDelaySeconds: params.InitialDelaySeconds,
+		PeriodSeconds:       params.PeriodSeconds,
+		TimeoutSeconds:      params.TimeoutSeconds,
+		SuccessThreshold:    params.SuccessThreshold,
+		FailureThreshold:    params.FailureThreshold,
+	}
+
+	switch params.Type {
+	case crd.ProbeTypeHTTP, crd.ProbeTypeTCP:
+		if port == 0 {
+			return nil, fmt.Errorf("%w: service %q %s probe: no port to probe", ErrInvalidProbe, service.Name, kind)
+		}
+
+		if params.Type == crd.ProbeTypeTCP {
+			probe.TCPSocket = &corev1.TCPSocketAction{
+				Port: intstr.FromInt(int(port)),
+			}
+			break
+		}
+
+		path := params.Path
+		if path == "" {
+			path = "/"
+		}
+
+		probe.HTTPGet = &corev1.HTTPGetAction{
+			Path:   path,
+			Port:   intstr.FromInt(int(port)),
+			Scheme: corev1.URISchemeHTTP,
+		}
+	case crd.ProbeTypeExec:
+		if !b.settings.Probes.ExecEnabled {
+			return nil, fmt.Errorf("%w: service %q %s probe: exec probes are not allowed", ErrInvalidProbe, service.Name, kind)
+		}
+
+		if len(params.Command) == 0 {
+			return nil, fmt.Errorf("%w: service %q %s probe: empty command", ErrInvalidProbe, service.Name, kind)
+		}
+
+		probe.Exec = &corev1.ExecAction{
+			Command: params.Command,
+		}
+	default:
+		return nil, fmt.Errorf("%w: service %q %s probe: unsupported type %q", ErrInvalidProbe, service.Name, kind, params.Type)
+	}
+
+	// kubernetes accepts success threshold other than 1 for readiness probes only
+	if kind != "readiness" && probe.SuccessThreshold > 1 {
+		probe.SuccessThreshold = 1
+	}
+
+	b.settings.Probes.limit(probe)
+
+	return probe, nil
+}
+
+// limit clamps probe timings into provider limits
+func (ps ProbeSettings) limit(probe *corev1.Probe) {
+	if max := durationSeconds(ps.MaxInitialDelay); max > 0 && probe.InitialDelaySeconds > max {
+		probe.InitialDelaySeconds = max
+	}
+
+	// unset period defaults to 10 seconds
+	if min := durationSeconds(ps.MinPeriod); min > 0 && (probe.PeriodSeconds < min) && (probe.PeriodSeconds != 0 || min > 10) {
+		probe.PeriodSeconds = min
+	}
+
+	if max := durationSeconds(ps.MaxTimeout); max > 0 && probe.TimeoutSeconds > max {
+		probe.TimeoutSeconds = max
+	}
+
+	if ps.MaxFailureThreshold > 0 && probe.FailureThreshold > ps.MaxFailureThreshold {
+		probe.FailureThreshold = ps.MaxFailureThreshold
+	}
+}
+
+func durationSeconds(val time.Duration) int32 {
+	return int32(val / time.Second)
+}
@@ -0,0 +1,151 @@
+package builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/akash-network/node/sdl"
+	"github.com/akash-network/node/testutil"
+
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+func makeProbesDeployment(t *testing.T, probes []*crd.ManifestServiceProbes) *ClusterDeployment {
+	sdl, err := sdl.ReadFile("../../../testdata/deployment/deployment.yaml")
+	require.NoError(t, err)
+
+	mani, err := sdl.Manifest()
+	require.NoError(t, err)
+
+	group := mani.GetGroups()[0]
+
+	return &ClusterDeployment{
+		Lid:   testutil.LeaseID(t),
+		Group: &group,
+		Sparams: crd.ClusterSettings{
+			SchedulerParams: make([]*crd.SchedulerParams, len(group.Services)),
+			Probes:          probes,
+		},
+	}
+}
+
+func TestWorkloadDefaultProbes(t *testing.T) {
+	log := testutil.Logger(t)
+	cdep := makeProbesDeployment(t, nil)
+
+	settings := NewDefaultSettings()
+
+	// no probes unless defaults are enabled
+	workload := NewWorkloadBuilder(log, settings, cdep, 0)
+	container, err := workload.container()
+	require.NoError(t, err)
+	require.Nil(t, container.LivenessProbe)
+	require.Nil(t, container.ReadinessProbe)
+	require.Nil(t, container.StartupProbe)
+
+	settings.Probes.DefaultsEnabled = true
+
+	workload = NewWorkloadBuilder(log, settings, cdep, 0)
+	container, err = workload.container()
+	require.NoError(t, err)
+	require.NotNil(t, container.LivenessProbe)
+	require.NotNil(t, container.LivenessProbe.TCPSocket)
+	require.Equal(t, intstr.FromInt(80), container.LivenessProbe.TCPSocket.Port)
+	require.NotNil(t, container.ReadinessProbe)
+	require.NotNil(t, container.ReadinessProbe.TCPSocket)
+	require.Nil(t, container.StartupProbe)
+}
+
+func TestWorkloadProbesFromParams(t *testing.T) {
+	log := testutil.Logger(t)
+	cdep := makeProbesDeployment(t, []*crd.ManifestServiceProbes{
+		{
+			Liveness: &crd.ManifestServiceProbe{
+				Type:             crd.ProbeTypeHTTP,
+				Path:             "/healthz",
+				PeriodSeconds:    1,
+				TimeoutSeconds:   120,
+				SuccessThreshold: 3,
+			},
+			Readiness: &crd.ManifestServiceProbe{
+				Type:                crd.ProbeTypeTCP,
+				Port:                8080,
+				InitialDelaySeconds: 3600,
+				SuccessThreshold:    3,
+			},
+			Startup: &crd.ManifestServiceProbe{
+				Type:             crd.ProbeTypeExec,
+				Command:          []string{"/bin/true"},
+				FailureThreshold: 100,
+			},
+		},
+	})
+
+	settings := NewDefaultSettings()
+	settings.Probes = ProbeSettings{
+		DefaultsEnabled:     true,
+		ExecEnabled:         true,
+		MaxInitialDelay:     time.Minute,
+		MinPeriod:           5 * time.Second,
+		MaxTimeout:          10 * time.Second,
+		MaxFailureThreshold: 10,
+	}
+
+	deployment := NewDeployment(NewWorkloadBuilder(log, settings, cdep, 0))
+	obj, err := deployment.Create()
+	require.NoError(t, err)
+
+	container := obj.Spec.Template.Spec.Containers[0]
+
+	liveness := container.LivenessProbe
+	require.NotNil(t, liveness)
+	require.NotNil(t, liveness.HTTPGet)
+	require.Equal(t, "/healthz", liveness.HTTPGet.Path)
+	require.Equal(t, intstr.FromInt(80), liveness.HTTPGet.Port)
+	require.Equal(t, int32(5), liveness.PeriodSeconds)
+	require.Equal(t, int32(10), liveness.TimeoutSeconds)
+	require.Equal(t, int32(1), liveness.SuccessThreshold)
+
+	readiness := container.ReadinessProbe
+	require.NotNil(t, readiness)
+	require.NotNil(t, readiness.TCPSocket)
+	require.Equal(t, intstr.FromInt(8080), readiness.TCPSocket.Port)
+	require.Equal(t, int32(60), readiness.InitialDelaySeconds)
+	require.Equal(t, int32(3), readiness.SuccessThreshold)
+
+	startup := container.StartupProbe
+	require.NotNil(t, startup)
+	require.NotNil(t, startup.Exec)
+	require.Equal(t, []string{"/bin/true"}, startup.Exec.Command)
+	require.Equal(t, int32(10), startup.FailureThreshold)
+
+	// same probes are applied to statefulset
+	sset := BuildStatefulSet(NewWorkloadBuilder(log, settings, cdep, 0))
+	sobj, err := sset.Create()
+	require.NoError(t, err)
+	require.Equal(t, container.LivenessProbe, sobj.Spec.Template.Spec.Containers[0].LivenessProbe)
+
+	settings.Probes.ExecEnabled = false
+
+	deployment = NewDeployment(NewWorkloadBuilder(log, settings, cdep, 0))
+	_, err = deployment.Create()
+	require.ErrorIs(t, err, ErrInvalidProbe)
+}
+
+func TestWorkloadInvalidProbe(t *testing.T) {
+	log := testutil.Logger(t)
+	cdep := makeProbesDeployment(t, []*crd.ManifestServiceProbes{
+		{
+			Liveness: &crd.ManifestServiceProbe{
+				Type: "grpc",
+			},
+		},
+	})
+
+	deployment := NewDeployment(NewWorkloadBuilder(log, NewDefaultSettings(), cdep, 0))
+	_, err := deployment.Create()
+	require.ErrorIs(t, err, ErrInvalidProbe)
+}
@@ -0,0 +1,276 @@
+package builder
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	mani "github.com/akash-network/akash-api/go/manifest/v2beta2"
+	"github.com/akash-network/node/sdl"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+const (
+	akashLeaseQuotaName      = "akash-lease-quota"
+	akashLeaseLimitRangeName = "akash-lease-limits"
+)
+
+type ResourceQuota interface {
+	builderBase
+	Create() (*corev1.ResourceQuota, error)
+	Update(obj *corev1.ResourceQuota) (*corev1.ResourceQuota, error)
+}
+
+type LimitRange interface {
+	builderBase
+	Create() (*corev1.LimitRange, error)
+	Update(obj *corev1.LimitRange) (*corev1.LimitRange, error)
+}
+
+type resourceQuota struct {
+	builder
+}
+
+type limitRange struct {
+	builder
+}
+
+var (
+	_ ResourceQuota = (*resourceQuota)(nil)
+	_ LimitRange    = (*limitRange)(nil)
+)
+
+// BuildResourceQuota creates quota capping lease namespace at resources of the lease
+func BuildResourceQuota(settings Settings, deployment IClusterDeployment) ResourceQuota {
+	return &resourceQuota{builder: builder{settings: settings, deployment: deployment}}
+}
+
+// BuildLimitRange creates limits of single container and volume claim within lease namespace
+func BuildLimitRange(settings Settings, deployment IClusterDeployment) LimitRange {
+	return &limitRange{builder: builder{settings: settings, deployment: deployment}}
+}
+
+// serviceReplicasLimit is the number of pods service may have at once.
+// Deployments are rolled out with surge pods on top of the count, 25% rounded up by default
+func serviceReplicasLimit(service *mani.Service, rollout RolloutSettings) int64 {
+	count := int64(service.Count)
+
+	if servicePersistent(service) {
+		return count
+	}
+
+	surge := intstr.FromString("25%")
+	if rollout.MaxSurge != nil {
+		surge = *rollout.MaxSurge
+	}
+
+	val, err := intstr.GetScaledValueFromIntOrPercent(&surge, int(count), true)
+	if err != nil || val < 0 {
+		val = 0
+	}
+
+	return count + int64(val)
+}
+
+func servicePersistent(service *mani.Service) bool {
+	for _, storage := range service.Resources.Storage {
+		attr := storage.Attributes.Find(sdl.StorageAttributePersistent)
+		if persistent, _ := attr.AsBool(); persistent {
+			return true
+		}
+	}
+
+	return false
+}
+
+func addQuantity(list corev1.ResourceList, name corev1.ResourceName, val int64) {
+	qty := list[name]
+	qty.Add(*resource.NewQuantity(val, resource.DecimalSI))
+	list[name] = qty
+}
+
+func addMilliQuantity(list corev1.ResourceList, name corev1.ResourceName, val int64) {
+	qty := list[name]
+	qty.Add(*resource.NewMilliQuantity(val, resource.DecimalSI))
+	list[name] = qty
+}
+
+func (b *resourceQuota) Name() string {
+	return akashLeaseQuotaName
+}
+
+func (b *resourceQuota) hard() corev1.ResourceList {
+	hard := corev1.ResourceList{
+		corev1.ResourcePods:                     resource.MustParse("0"),
+		corev1.ResourceRequestsCPU:              resource.MustParse("0"),
+		corev1.ResourceLimitsCPU:                resource.MustParse("0"),
+		corev1.ResourceRequestsMemory:           resource.MustParse("0"),
+		corev1.ResourceLimitsMemory:             resource.MustParse("0"),
+		corev1.ResourceRequestsEphemeralStorage: resource.MustParse("0"),
+		corev1.ResourceLimitsEphemeralStorage:   resource.MustParse("0"),
+		corev1.ResourcePersistentVolumeClaims:   resource.MustParse("0"),
+		corev1.ResourceRequestsStorage:          resource.MustParse("0"),
+	}
+
+	group := b.deployment.ManifestGroup()
+	sparams := b.deployment.ClusterParams().SchedulerParams
+
+	for idx := range group.Services {
+		service := &group.Services[idx]
+		replicas := serviceReplicasLimit(service, b.settings.Rollout)
+
+		addQuantity(hard, corev1.ResourcePods, replicas)
+
+		if cpu := service.Resources.CPU; cpu != nil {
+			addMilliQuantity(hard, corev1.ResourceRequestsCPU, int64(cpu.Units.Value())*replicas)
+			addMilliQuantity(hard, corev1.ResourceLimitsCPU, int64(cpu.Units.Value())*replicas)
+		}
+
+		if mem := service.Resources.Memory; mem != nil {
+			memory := int64(mem.Quantity.Value()) + serviceRAMStorage(service)
+
+			addQuantity(hard, corev1.ResourceRequestsMemory, memory*replicas)
+			addQuantity(hard, corev1.ResourceLimitsMemory, memory*replicas)
+		}
+
+		if gpu := service.Resources.GPU; gpu != nil && gpu.Units.Value() > 0 && idx < len(sparams) {
+			if params := sparams[idx]; params != nil && params.Resources != nil && params.Resources.GPU != nil {
+				if name := gpuResourceName(params.Resources.GPU.Vendor); name != "" {
+					addQuantity(hard, corev1.ResourceName(fmt.Sprintf("requests.%s", name)), int64(gpu.Units.Value())*replicas)
+				}
+			}
+		}
+
+		for _, storage := range service.Resources.Storage {
+			if ctypes.IsRAMStorage(storage.Attributes) {
+				continue
+			}
+
+			attr := storage.Attributes.Find(sdl.StorageAttributePersistent)
+			if persistent, _ := attr.AsBool(); !persistent {
+				addQuantity(hard, corev1.ResourceRequestsEphemeralStorage, int64(storage.Quantity.Value())*replicas)
+				addQuantity(hard, corev1.ResourceLimitsEphemeralStorage, int64(storage.Quantity.Value())*replicas)
+				continue
+			}
+
+			addQuantity(hard, corev1.ResourcePersistentVolumeClaims, replicas)
+			addQuantity(hard, corev1.ResourceRequestsStorage, int64(storage.Quantity.Value())*replicas)
+
+			attr = storage.Attributes.Find(sdl.StorageAttributeClass)
+			if class, valid := attr.AsString(); valid && class != sdl.StorageClassDefault {
+				name := corev1.ResourceName(fmt.Sprintf("%s.storageclass.storage.k8s.io/requests.storage", class))
+				addQuantity(hard, name, int64(storage.Quantity.Value())*replicas)
+			}
+		}
+	}
+
+	return hard
+}
+
+func (b *resourceQuota) Create() (*corev1.ResourceQuota, error) { // nolint:golint,unparam
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.Name(),
+			Namespace: b.NS(),
+			Labels:    b.labels(),
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: b.hard(),
+		},
+	}, nil
+}
+
+func (b *resourceQuota) Update(obj *corev1.ResourceQuota) (*corev1.ResourceQuota, error) { // nolint:golint,unparam
+	obj.Labels = b.labels()
+	obj.Spec.Hard = b.hard()
+
+	return obj, nil
+}
+
+func (b *limitRange) Name() string {
+	return akashLeaseLimitRangeName
+}
+
+func maxQuantity(list corev1.ResourceList, name corev1.ResourceName, qty resource.Quantity) {
+	if curr, exists := list[name]; !exists || curr.Cmp(qty) < 0 {
+		list[name] = qty
+	}
+}
+
+func (b *limitRange) limits() []corev1.LimitRangeItem {
+	container := make(corev1.ResourceList)
+	claim := make(corev1.ResourceList)
+
+	group := b.deployment.ManifestGroup()
+
+	for idx := range group.Services {
+		service := &group.Services[idx]
+
+		if cpu := service.Resources.CPU; cpu != nil {
+			maxQuantity(container, corev1.ResourceCPU, *resource.NewMilliQuantity(int64(cpu.Units.Value()), resource.DecimalSI))
+		}
+
+		if mem := service.Resources.Memory; mem != nil {
+			memory := int64(mem.Quantity.Value()) + serviceRAMStorage(service)
+			maxQuantity(container, corev1.ResourceMemory, *resource.NewQuantity(memory, resource.DecimalSI))
+		}
+
+		for _, storage := range service.Resources.Storage {
+			if ctypes.IsRAMStorage(storage.Attributes) {
+				continue
+			}
+
+			qty := *resource.NewQuantity(int64(storage.Quantity.Value()), resource.DecimalSI)
+
+			attr := storage.Attributes.Find(sdl.StorageAttributePersistent)
+			if persistent, _ := attr.AsBool(); persistent {
+				maxQuantity(claim, corev1.ResourceStorage, qty)
+			} else {
+				maxQuantity(container, corev1.ResourceEphemeralStorage, qty)
+			}
+		}
+	}
+
+	var items []corev1.LimitRangeItem // nolint:prealloc
+
+	if len(container) != 0 {
+		items = append(items, corev1.LimitRangeItem{
+			Type:    corev1.LimitTypeContainer,
+			Max:     container,
+			Default: container.DeepCopy(),
+		})
+	}
+
+	if len(claim) != 0 {
+		items = append(items, corev1.LimitRangeItem{
+			Type: corev1.LimitTypePersistentVolumeClaim,
+			Max:  claim,
+		})
+	}
+
+	return items
+}
+
+func (b *limitRange) Create() (*corev1.LimitRange, error) { // nolint:golint,unparam
+	return &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.Name(),
+			Namespace: b.NS(),
+			Labels:    b.labels(),
+		},
+		Spec: corev1.LimitRangeSpec{
+			Limits: b.limits(),
+		},
+	}, nil
+}
+
+func (b *limitRange) Update(obj *corev1.LimitRange) (*corev1.LimitRange, error) { // nolint:golint,unparam
+	obj.Labels = b.labels()
+	obj.Spec.Limits = b.limits()
+
+	return obj, nil
+}
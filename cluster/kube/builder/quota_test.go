@@ -0,0 +1,70 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/akash-network/node/sdl"
+	"github.com/akash-network/node/testutil"
+
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+func TestResourceQuotaFromLease(t *testing.T) {
+	sdl, err := sdl.ReadFile("../../../testdata/deployment/deployment.yaml")
+	require.NoError(t, err)
+
+	mani, err := sdl.Manifest()
+	require.NoError(t, err)
+
+	group := mani.GetGroups()[0]
+	group.Services[0].Count = 4
+
+	cdep := &ClusterDeployment{
+		Lid:   testutil.LeaseID(t),
+		Group: &group,
+		Sparams: crd.ClusterSettings{
+			SchedulerParams: make([]*crd.SchedulerParams, len(group.Services)),
+		},
+	}
+
+	quota, err := BuildResourceQuota(NewDefaultSettings(), cdep).Create()
+	require.NoError(t, err)
+	require.Equal(t, akashLeaseQuotaName, quota.Name)
+	require.Equal(t, LidNS(cdep.Lid), quota.Namespace)
+
+	hard := quota.Spec.Hard
+
+	// 4 replicas plus one surge pod of deployment rollout
+	require.Equal(t, int64(5), hard.Pods().Value())
+
+	cpu := hard[corev1.ResourceLimitsCPU]
+	require.Equal(t, int64(50), cpu.MilliValue())
+
+	mem := hard[corev1.ResourceLimitsMemory]
+	require.Equal(t, 0, mem.Cmp(*resource.NewQuantity(5*128*1024*1024, resource.DecimalSI)))
+
+	storage := hard[corev1.ResourceLimitsEphemeralStorage]
+	require.Equal(t, 0, storage.Cmp(*resource.NewQuantity(5*512*1024*1024, resource.DecimalSI)))
+
+	claims := hard[corev1.ResourcePersistentVolumeClaims]
+	require.True(t, claims.IsZero())
+
+	// quota follows manifest updates
+	group.Services[0].Count = 1
+
+	quota, err = BuildResourceQuota(NewDefaultSettings(), cdep).Update(quota)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), quota.Spec.Hard.Pods().Value())
+
+	limits, err := BuildLimitRange(NewDefaultSettings(), cdep).Create()
+	require.NoError(t, err)
+	require.Len(t, limits.Spec.Limits, 1)
+	require.Equal(t, corev1.LimitTypeContainer, limits.Spec.Limits[0].Type)
+
+	maxCPU := limits.Spec.Limits[0].Max[corev1.ResourceCPU]
+	require.Equal(t, int64(10), maxCPU.MilliValue())
+}
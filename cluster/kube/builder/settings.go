@@ -2,9 +2,11 @@ package builder
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	vutil "github.com/akash-network/node/util/validation"
 )
@@ -34,6 +36,10 @@ type Settings struct {
 	// NetworkPoliciesEnabled determines if NetworkPolicies should be installed.
 	NetworkPoliciesEnabled bool
 
+	// EgressPolicy restricts outgoing traffic of tenants when network policies are enabled.
+	// Nil policy allows public IPv4 addresses only
+	EgressPolicy *EgressPolicy
+
 	CPUCommitLevel     float64
 	GPUCommitLevel     float64
 	MemoryCommitLevel  float64
@@ -43,6 +49,50 @@ type Settings struct {
 
 	// Name of the image pull secret to use in pod spec
 	DockerImagePullSecretsName string
+
+	// Probes configures health probes of tenant containers
+	Probes ProbeSettings
+
+	// ResourceQuotasEnabled determines if lease namespaces are capped at lease resources
+	// with ResourceQuota and LimitRange
+	ResourceQuotasEnabled bool
+
+	// Rollout configures rolling updates of tenant workloads
+	Rollout RolloutSettings
+
+	// StaleVolumeRetention is how long volume claims of removed services are kept before deletion.
+	// Claims are deleted right away when zero
+	StaleVolumeRetention time.Duration
+
+	// PodSecurity configures Pod Security Admission of lease namespaces and security context of tenant workloads
+	PodSecurity PodSecuritySettings
+
+	// Snapshots configures volume snapshots of persistent storage
+	Snapshots SnapshotSettings
+}
+
+// SnapshotSettings configures VolumeSnapshots of persistent volumes taken on tenant request
+type SnapshotSettings struct {
+	// Class is the name of VolumeSnapshotClass. Cluster default is used when empty
+	Class string
+	// Quota is the maximum number of snapshots kept per lease. Snapshots are disabled when zero
+	Quota uint
+	// Retention is how long snapshots are kept. Snapshots are kept until lease is closed when zero
+	Retention time.Duration
+}
+
+// RolloutSettings configures rolling updates of deployments.
+// Statefulsets are always updated one pod at a time, starting from the highest ordinal
+type RolloutSettings struct {
+	// MaxSurge is the number or percentage of pods created above desired count during update.
+	// Kubernetes default is used when nil
+	MaxSurge *intstr.IntOrString
+	// MaxUnavailable is the number or percentage of pods which may be unavailable during update.
+	// Kubernetes default is used when nil
+	MaxUnavailable *intstr.IntOrString
+	// ProgressDeadline is the time after which stalled rollout is reported as failed.
+	// Kubernetes default is used when zero
+	ProgressDeadline time.Duration
 }
 
 var ErrSettingsValidation = errors.New("settings validation")
@@ -58,15 +108,83 @@ func ValidateSettings(settings Settings) error {
 		}
 	}
 
+	if settings.Probes.MaxInitialDelay < 0 || settings.Probes.MinPeriod < 0 || settings.Probes.MaxTimeout < 0 || settings.Probes.MaxFailureThreshold < 0 {
+		return errors.Wrap(ErrSettingsValidation, "negative probe limits")
+	}
+
+	if err := validateRolloutValue(settings.Rollout.MaxSurge); err != nil {
+		return fmt.Errorf("%w: rollout max surge: %s", ErrSettingsValidation, err)
+	}
+
+	if err := validateRolloutValue(settings.Rollout.MaxUnavailable); err != nil {
+		return fmt.Errorf("%w: rollout max unavailable: %s", ErrSettingsValidation, err)
+	}
+
+	if surge, unavailable := settings.Rollout.MaxSurge, settings.Rollout.MaxUnavailable; surge != nil && unavailable != nil &&
+		surge.String() == "0" && unavailable.String() == "0" {
+		return errors.Wrap(ErrSettingsValidation, "rollout max surge and max unavailable cannot be both 0")
+	}
+
+	if settings.Rollout.ProgressDeadline < 0 {
+		return errors.Wrap(ErrSettingsValidation, "negative rollout progress deadline")
+	}
+
+	if settings.StaleVolumeRetention < 0 {
+		return errors.Wrap(ErrSettingsValidation, "negative stale volume retention")
+	}
+
+	if settings.Snapshots.Retention < 0 {
+		return errors.Wrap(ErrSettingsValidation, "negative snapshot retention")
+	}
+
+	if err := settings.PodSecurity.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+func validateRolloutValue(val *intstr.IntOrString) error {
+	if val == nil {
+		return nil
+	}
+
+	res, err := intstr.GetScaledValueFromIntOrPercent(val, 100, true)
+	if err != nil {
+		return err
+	}
+
+	if res < 0 {
+		return fmt.Errorf("negative value %q", val.String()) // nolint: goerr113
+	}
+
+	return nil
+}
+
+// rolloutValue parses number or percentage of replicas
+func rolloutValue(val string) *intstr.IntOrString {
+	res := intstr.Parse(val)
+	return &res
+}
+
 func NewDefaultSettings() Settings {
 	return Settings{
 		DeploymentServiceType:          corev1.ServiceTypeClusterIP,
 		DeploymentIngressStaticHosts:   false,
 		DeploymentIngressExposeLBHosts: false,
 		NetworkPoliciesEnabled:         false,
+		ResourceQuotasEnabled:          true,
+		Probes: ProbeSettings{
+			ExecEnabled: true,
+		},
+		Rollout: RolloutSettings{
+			MaxSurge:         rolloutValue("25%"),
+			MaxUnavailable:   rolloutValue("25%"),
+			ProgressDeadline: 10 * time.Minute,
+		},
+		PodSecurity: PodSecuritySettings{
+			SeccompRuntimeDefault: true,
+		},
 	}
 }
 
@@ -31,6 +31,11 @@ func BuildStatefulSet(workload Workload) StatefulSet {
 func (b *statefulSet) Create() (*appsv1.StatefulSet, error) { // nolint:golint,unparam
 	falseValue := false
 
+	container, err := b.container()
+	if err != nil {
+		return nil, err
+	}
+
 	kdeployment := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   b.Name(),
@@ -40,20 +45,21 @@ func (b *statefulSet) Create() (*appsv1.StatefulSet, error) { // nolint:golint,u
 			Selector: &metav1.LabelSelector{
 				MatchLabels: b.labels(),
 			},
-			Replicas: b.replicas(),
+			Replicas:       b.replicas(),
+			UpdateStrategy: b.updateStrategy(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: b.labels(),
+					Labels:      b.labels(),
+					Annotations: b.podAnnotations(nil),
 				},
 				Spec: corev1.PodSpec{
-					Affinity:         b.affinity(),
-					RuntimeClassName: b.runtimeClass(),
-					SecurityContext: &corev1.PodSecurityContext{
-						RunAsNonRoot: &falseValue,
-					},
+					Affinity:                     b.affinity(),
+					RuntimeClassName:             b.runtimeClass(),
+					SecurityContext:              b.podSecurityContext(),
 					AutomountServiceAccountToken: &falseValue,
-					Containers:                   []corev1.Container{b.container()},
+					Containers:                   []corev1.Container{container},
 					ImagePullSecrets:             b.imagePullSecrets(),
+					Volumes:                      b.volumes(),
 				},
 			},
 			VolumeClaimTemplates: b.persistentVolumeClaims(),
@@ -64,15 +70,33 @@ func (b *statefulSet) Create() (*appsv1.StatefulSet, error) { // nolint:golint,u
 }
 
 func (b *statefulSet) Update(obj *appsv1.StatefulSet) (*appsv1.StatefulSet, error) { // nolint:golint,unparam
+	container, err := b.container()
+	if err != nil {
+		return nil, err
+	}
+
 	obj.Labels = b.labels()
 	obj.Spec.Selector.MatchLabels = b.labels()
 	obj.Spec.Replicas = b.replicas()
+	obj.Spec.UpdateStrategy = b.updateStrategy()
 	obj.Spec.Template.Labels = b.labels()
+	obj.Spec.Template.Annotations = b.podAnnotations(obj.Spec.Template.Annotations)
 	obj.Spec.Template.Spec.Affinity = b.affinity()
 	obj.Spec.Template.Spec.RuntimeClassName = b.runtimeClass()
-	obj.Spec.Template.Spec.Containers = []corev1.Container{b.container()}
+	obj.Spec.Template.Spec.SecurityContext = b.podSecurityContext()
+	obj.Spec.Template.Spec.Containers = []corev1.Container{container}
 	obj.Spec.Template.Spec.ImagePullSecrets = b.imagePullSecrets()
+	obj.Spec.Template.Spec.Volumes = b.volumes()
 	obj.Spec.VolumeClaimTemplates = b.persistentVolumeClaims()
 
 	return obj, nil
 }
+
+// updateStrategy returns rolling update. Pods are replaced one at a time from the highest ordinal,
+// and update halts at the first pod which fails to become ready, leaving the rest of pods at the previous revision.
+// Staged partitioned updates are out of scope, whole statefulset is rolled out at once
+func (b *statefulSet) updateStrategy() appsv1.StatefulSetUpdateStrategy {
+	return appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+	}
+}
@@ -10,9 +10,11 @@ import (
 
 	"github.com/tendermint/tendermint/libs/log"
 
+	mani "github.com/akash-network/akash-api/go/manifest/v2beta2"
 	"github.com/akash-network/node/sdl"
 	sdlutil "github.com/akash-network/node/sdl/util"
 
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 )
 
@@ -54,8 +56,11 @@ func (b *Workload) Name() string {
 	return b.deployment.ManifestGroup().Services[b.serviceIdx].Name
 }
 
-func (b *Workload) container() corev1.Container {
-	falseValue := false
+func (b *Workload) container() (corev1.Container, error) {
+	probes, err := b.probes()
+	if err != nil {
+		return corev1.Container{}, err
+	}
 
 	service := &b.deployment.ManifestGroup().Services[b.serviceIdx]
 	sparams := b.deployment.ClusterParams().SchedulerParams[b.serviceIdx]
@@ -70,11 +75,10 @@ func (b *Workload) container() corev1.Container {
 			Requests: make(corev1.ResourceList),
 		},
 		ImagePullPolicy: corev1.PullIfNotPresent,
-		SecurityContext: &corev1.SecurityContext{
-			RunAsNonRoot:             &falseValue,
-			Privileged:               &falseValue,
-			AllowPrivilegeEscalation: &falseValue,
-		},
+		SecurityContext: b.securityContext(),
+		LivenessProbe:   probes.liveness,
+		ReadinessProbe:  probes.readiness,
+		StartupProbe:    probes.startup,
 	}
 
 	if cpu := service.Resources.CPU; cpu != nil {
@@ -84,14 +88,8 @@ func (b *Workload) container() corev1.Container {
 	}
 
 	if gpu := service.Resources.GPU; gpu != nil && gpu.Units.Value() > 0 {
-		var resourceName corev1.ResourceName
-
-		switch sparams.Resources.GPU.Vendor {
-		case GPUVendorNvidia:
-			resourceName = ResourceGPUNvidia
-		case GPUVendorAMD:
-			resourceName = ResourceGPUAMD
-		default:
+		resourceName := gpuResourceName(sparams.Resources.GPU.Vendor)
+		if resourceName == "" {
 			panic(fmt.Sprintf("requested for unsupported GPU vendor"))
 		}
 
@@ -105,12 +103,19 @@ func (b *Workload) container() corev1.Container {
 	}
 
 	if mem := service.Resources.Memory; mem != nil {
+		// memory backed volumes are charged to the container memory, they are not subject to overcommit
+		ram := serviceRAMStorage(service)
+
 		requestedMem := sdlutil.ComputeCommittedResources(b.settings.MemoryCommitLevel, mem.Quantity)
-		kcontainer.Resources.Requests[corev1.ResourceMemory] = resource.NewQuantity(int64(requestedMem.Value()), resource.DecimalSI).DeepCopy()
-		kcontainer.Resources.Limits[corev1.ResourceMemory] = resource.NewQuantity(int64(mem.Quantity.Value()), resource.DecimalSI).DeepCopy()
+		kcontainer.Resources.Requests[corev1.ResourceMemory] = resource.NewQuantity(int64(requestedMem.Value())+ram, resource.DecimalSI).DeepCopy()
+		kcontainer.Resources.Limits[corev1.ResourceMemory] = resource.NewQuantity(int64(mem.Quantity.Value())+ram, resource.DecimalSI).DeepCopy()
 	}
 
 	for _, ephemeral := range service.Resources.Storage {
+		if ctypes.IsRAMStorage(ephemeral.Attributes) {
+			continue
+		}
+
 		attr := ephemeral.Attributes.Find(sdl.StorageAttributePersistent)
 		if persistent, _ := attr.AsBool(); !persistent {
 			requestedStorage := sdlutil.ComputeCommittedResources(b.settings.StorageCommitLevel, ephemeral.Quantity)
@@ -151,7 +156,58 @@ func (b *Workload) container() corev1.Container {
 		})
 	}
 
-	return kcontainer
+	return kcontainer, nil
+}
+
+// serviceRAMStorage returns total size of memory backed volumes of the service
+func serviceRAMStorage(service *mani.Service) int64 {
+	var total int64
+
+	for _, storage := range service.Resources.Storage {
+		if ctypes.IsRAMStorage(storage.Attributes) {
+			total += int64(storage.Quantity.Value())
+		}
+	}
+
+	return total
+}
+
+// volumes returns memory backed volumes of the service as emptyDir volumes of the pod
+func (b *Workload) volumes() []corev1.Volume {
+	var volumes []corev1.Volume // nolint:prealloc
+
+	service := &b.deployment.ManifestGroup().Services[b.serviceIdx]
+
+	for _, storage := range service.Resources.Storage {
+		if !ctypes.IsRAMStorage(storage.Attributes) {
+			continue
+		}
+
+		volumes = append(volumes, corev1.Volume{
+			// matches volume mounts of the container
+			Name: fmt.Sprintf("%s-%s", service.Name, storage.Name),
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium:    corev1.StorageMediumMemory,
+					SizeLimit: resource.NewQuantity(int64(storage.Quantity.Value()), resource.DecimalSI),
+				},
+			},
+		})
+	}
+
+	return volumes
+}
+
+// gpuResourceName returns kubernetes resource name of the GPU vendor or empty string if vendor is not supported
+func gpuResourceName(vendor string) corev1.ResourceName {
+	switch vendor {
+	case GPUVendorNvidia:
+		return ResourceGPUNvidia
+	case GPUVendorAMD:
+		return ResourceGPUAMD
+	default:
+		return ""
+	}
 }
 
 func (b *Workload) persistentVolumeClaims() []corev1.PersistentVolumeClaim {
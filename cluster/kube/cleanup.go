@@ -2,20 +2,56 @@ package kube
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
-	mani "github.com/akash-network/akash-api/go/manifest/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	kubeErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/client-go/kubernetes"
 
+	mani "github.com/akash-network/akash-api/go/manifest/v2beta2"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	"github.com/akash-network/node/sdl"
 
 	"github.com/akash-network/provider/cluster/kube/builder"
+	"github.com/akash-network/provider/cluster/kube/clientcommon"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
 )
 
-func cleanupStaleResources(ctx context.Context, kc kubernetes.Interface, lid mtypes.LeaseID, group *mani.Group) error {
+// staleSinceAnnotation is set on volume claims of removed services.
+// Claim is deleted once retention period passes, so accidentally removed service can be restored with its data
+const staleSinceAnnotation = "akash.network/stale-since"
+
+func servicePersistent(service *mani.Service) bool {
+	for i := range service.Resources.Storage {
+		attrVal := service.Resources.Storage[i].Attributes.Find(sdl.StorageAttributePersistent)
+		if persistent, _ := attrVal.AsBool(); persistent {
+			return true
+		}
+	}
+
+	return false
+}
+
+func groupServices(group *mani.Group) map[string]*mani.Service {
+	res := make(map[string]*mani.Service, len(group.Services))
+	for i := range group.Services {
+		res[group.Services[i].Name] = &group.Services[i]
+	}
+
+	return res
+}
+
+// cleanupStaleResources deletes objects of services which are not in the manifest group anymore
+// along with workloads of services which switched between deployment and statefulset
+func (c *client) cleanupStaleResources(ctx context.Context, settings builder.Settings, lid mtypes.LeaseID, group *mani.Group) error {
 	ns := builder.LidNS(lid)
+	services := groupServices(group)
 
 	// build label selector for objects not in current manifest group
 	svcnames := make([]string, 0, len(group.Services))
@@ -27,37 +63,337 @@ func cleanupStaleResources(ctx context.Context, kc kubernetes.Interface, lid mty
 	if err != nil {
 		return err
 	}
-	req2, err := labels.NewRequirement(builder.AkashServiceTarget, selection.Equals, []string{"true"})
+	req2, err := labels.NewRequirement(builder.AkashManifestServiceLabelName, selection.Exists, nil)
 	if err != nil {
 		return err
 	}
 
-	req3, err := labels.NewRequirement(builder.AkashManagedLabelName, selection.NotIn, []string{builder.AkashMetalLB})
+	req3, err := labels.NewRequirement(builder.AkashServiceTarget, selection.NotIn, []string{builder.AkashMetalLB})
 	if err != nil {
 		return err
 	}
 
 	selector := labels.NewSelector().Add(*req1).Add(*req2).Add(*req3).String()
+	workloadSelector := labels.NewSelector().Add(*req2).String()
 
 	// delete stale deployments
-	if err := kc.AppsV1().Deployments(ns).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{
-		LabelSelector: selector,
-	}); err != nil {
+	deployments, err := c.kc.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: workloadSelector,
+	})
+	if err != nil {
 		return err
 	}
+	for _, obj := range deployments.Items {
+		if svc, exists := services[obj.Labels[builder.AkashManifestServiceLabelName]]; exists && !servicePersistent(svc) {
+			continue
+		}
+
+		if err := c.kc.AppsV1().Deployments(ns).Delete(ctx, obj.Name, metav1.DeleteOptions{}); err != nil && !kubeErrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	// delete stale statefulsets
+	ssets, err := c.kc.AppsV1().StatefulSets(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: workloadSelector,
+	})
+	if err != nil {
+		return err
+	}
+	for _, obj := range ssets.Items {
+		if svc, exists := services[obj.Labels[builder.AkashManifestServiceLabelName]]; exists && servicePersistent(svc) {
+			continue
+		}
+
+		if err := c.kc.AppsV1().StatefulSets(ns).Delete(ctx, obj.Name, metav1.DeleteOptions{}); err != nil && !kubeErrors.IsNotFound(err) {
+			return err
+		}
+	}
 
 	// delete stale services (no DeleteCollection)
-	services, err := kc.CoreV1().Services(ns).List(ctx, metav1.ListOptions{
+	kservices, err := c.kc.CoreV1().Services(ns).List(ctx, metav1.ListOptions{
 		LabelSelector: selector,
 	})
 	if err != nil {
 		return err
 	}
-	for _, svc := range services.Items {
-		if err := kc.CoreV1().Services(ns).Delete(ctx, svc.Name, metav1.DeleteOptions{}); err != nil {
+	for _, svc := range kservices.Items {
+		if err := c.kc.CoreV1().Services(ns).Delete(ctx, svc.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+	}
+
+	if err := cleanupStaleVolumes(ctx, c.kc, ns, settings.StaleVolumeRetention, time.Now(), func(pvc corev1.PersistentVolumeClaim) bool {
+		return !volumeClaimInUse(pvc, services)
+	}); err != nil {
+		return err
+	}
+
+	// delete ingresses routed to removed services
+	ingresses, err := c.kc.NetworkingV1().Ingresses(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", builder.AkashManagedLabelName),
+	})
+	if err != nil {
+		return err
+	}
+	for _, ingress := range ingresses.Items {
+		stale := false
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil {
+					if _, exists := services[path.Backend.Service.Name]; !exists {
+						stale = true
+					}
+				}
+			}
+		}
+
+		if !stale {
+			continue
+		}
+
+		if err := c.kc.NetworkingV1().Ingresses(ns).Delete(ctx, ingress.Name, metav1.DeleteOptions{}); err != nil && !kubeErrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return c.cleanupStaleDeclarations(ctx, lid, services)
+}
+
+// cleanupStaleDeclarations deletes hostnames and leased IPs declared for removed services
+func (c *client) cleanupStaleDeclarations(ctx context.Context, lid mtypes.LeaseID, services map[string]*mani.Service) error {
+	labelSelector := &strings.Builder{}
+	kubeSelectorForLease(labelSelector, lid)
+
+	hosts, err := c.ac.AkashV2beta2().ProviderHosts(c.ns).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector.String(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, host := range hosts.Items {
+		if _, exists := services[host.Spec.ServiceName]; exists {
+			continue
+		}
+
+		c.log.Info("removing hostname of stale service", "lease", lid, "service-name", host.Spec.ServiceName, "host", host.Name)
+		if err := c.ac.AkashV2beta2().ProviderHosts(c.ns).Delete(ctx, host.Name, metav1.DeleteOptions{}); err != nil && !kubeErrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	ips, err := c.ac.AkashV2beta2().ProviderLeasedIPs(c.ns).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector.String(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, ip := range ips.Items {
+		if _, exists := services[ip.Spec.ServiceName]; exists {
+			continue
+		}
+
+		c.log.Info("removing leased ip of stale service", "lease", lid, "service-name", ip.Spec.ServiceName, "name", ip.Name)
+		if err := c.ac.AkashV2beta2().ProviderLeasedIPs(c.ns).Delete(ctx, ip.Name, metav1.DeleteOptions{}); err != nil && !kubeErrors.IsNotFound(err) {
 			return err
 		}
 	}
 
 	return nil
 }
+
+// volumeClaimInUse checks claim belongs to the volume of persistent service and to the replica within service count.
+// Statefulset claims are named <service>-<volume>-<statefulset>-<ordinal>
+func volumeClaimInUse(pvc corev1.PersistentVolumeClaim, services map[string]*mani.Service) bool {
+	svc, exists := services[pvc.Labels[builder.AkashManifestServiceLabelName]]
+	if !exists {
+		return false
+	}
+
+	for _, storage := range svc.Resources.Storage {
+		attr := storage.Attributes.Find(sdl.StorageAttributePersistent)
+		if persistent, _ := attr.AsBool(); !persistent {
+			continue
+		}
+
+		prefix := fmt.Sprintf("%s-%s-%s-", svc.Name, storage.Name, svc.Name)
+		if !strings.HasPrefix(pvc.Name, prefix) {
+			continue
+		}
+
+		ordinal, err := strconv.ParseUint(strings.TrimPrefix(pvc.Name, prefix), 10, 32)
+		if err == nil && ordinal < uint64(svc.Count) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cleanupStaleVolumes marks volume claims reported stale and deletes them once retention passes.
+// Claims in use again are unmarked
+func cleanupStaleVolumes(ctx context.Context, kc kubernetes.Interface, ns string, retention time.Duration, now time.Time, stale func(corev1.PersistentVolumeClaim) bool) error {
+	pvcs, err := kc.CoreV1().PersistentVolumeClaims(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", builder.AkashManagedLabelName),
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		val, marked := pvc.Annotations[staleSinceAnnotation]
+
+		if !stale(*pvc) {
+			if marked {
+				delete(pvc.Annotations, staleSinceAnnotation)
+				if _, err := kc.CoreV1().PersistentVolumeClaims(ns).Update(ctx, pvc, metav1.UpdateOptions{}); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		since, err := time.Parse(time.RFC3339, val)
+		if retention > 0 && (!marked || err != nil) {
+			if pvc.Annotations == nil {
+				pvc.Annotations = make(map[string]string)
+			}
+			pvc.Annotations[staleSinceAnnotation] = now.UTC().Format(time.RFC3339)
+			if _, err := kc.CoreV1().PersistentVolumeClaims(ns).Update(ctx, pvc, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if retention > 0 && now.Sub(since) < retention {
+			continue
+		}
+
+		if err := kc.CoreV1().PersistentVolumeClaims(ns).Delete(ctx, pvc.Name, metav1.DeleteOptions{}); err != nil && !kubeErrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SweepOrphans tears down leases which have akash managed objects in the cluster and are not active.
+// Objects younger than minAge are left intact, so leases which are being deployed are not swept.
+// Volume claims of removed services of active leases are deleted once their retention passes
+func (c *client) SweepOrphans(ctx context.Context, active []mtypes.LeaseID, minAge time.Duration) ([]mtypes.LeaseID, error) {
+	settingsI := ctx.Value(builder.SettingsKey)
+	if nil == settingsI {
+		return nil, kubeclienterrors.ErrNotConfiguredWithSettings
+	}
+	settings := settingsI.(builder.Settings)
+
+	activeLeases := make(map[mtypes.LeaseID]bool, len(active))
+	for _, lid := range active {
+		activeLeases[lid] = true
+	}
+
+	now := time.Now()
+	orphans := make(map[mtypes.LeaseID]bool)
+
+	check := func(meta metav1.ObjectMeta, lid mtypes.LeaseID) {
+		if !activeLeases[lid] && now.Sub(meta.CreationTimestamp.Time) >= minAge {
+			orphans[lid] = true
+		}
+	}
+
+	managedSelector := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=true", builder.AkashManagedLabelName)}
+
+	namespaces, err := wrapKubeCall("namespaces-list", func() (*corev1.NamespaceList, error) {
+		return c.kc.CoreV1().Namespaces().List(ctx, managedSelector)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ns := range namespaces.Items {
+		lid, err := clientcommon.RecoverLeaseIDFromLabels(ns.Labels)
+		if err != nil {
+			continue
+		}
+
+		if !activeLeases[lid] {
+			check(ns.ObjectMeta, lid)
+			continue
+		}
+
+		// only claims marked on deploy are deleted
+		if err := cleanupStaleVolumes(ctx, c.kc, ns.Name, settings.StaleVolumeRetention, now, func(pvc corev1.PersistentVolumeClaim) bool {
+			_, marked := pvc.Annotations[staleSinceAnnotation]
+			return marked
+		}); err != nil {
+			c.log.Error("cleaning stale volumes", "err", err, "lease", lid)
+		}
+
+		if err := c.pruneSnapshots(ctx, ns.Name, settings.Snapshots.Retention, now); err != nil {
+			c.log.Error("pruning volume snapshots", "err", err, "lease", lid)
+		}
+	}
+
+	manifests, err := c.ac.AkashV2beta2().Manifests(c.ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range manifests.Items {
+		if lid, err := m.Spec.LeaseID.FromCRD(); err == nil {
+			check(m.ObjectMeta, lid)
+		}
+	}
+
+	hosts, err := c.ac.AkashV2beta2().ProviderHosts(c.ns).List(ctx, managedSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, host := range hosts.Items {
+		if lid, err := clientcommon.RecoverLeaseIDFromLabels(host.Labels); err == nil {
+			check(host.ObjectMeta, lid)
+		}
+	}
+
+	ips, err := c.ac.AkashV2beta2().ProviderLeasedIPs(c.ns).List(ctx, managedSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ip := range ips.Items {
+		if lid, err := clientcommon.RecoverLeaseIDFromLabels(ip.Labels); err == nil {
+			check(ip.ObjectMeta, lid)
+		}
+	}
+
+	swept := make([]mtypes.LeaseID, 0, len(orphans))
+
+	for lid := range orphans {
+		c.log.Info("sweeping orphaned lease", "lease", lid)
+
+		if err := c.TeardownLease(ctx, lid); err != nil && !kubeErrors.IsNotFound(err) {
+			c.log.Error("sweeping orphaned lease namespace", "err", err, "lease", lid)
+			continue
+		}
+
+		if err := c.PurgeDeclaredHostnames(ctx, lid); err != nil {
+			c.log.Error("sweeping orphaned lease hostnames", "err", err, "lease", lid)
+			continue
+		}
+
+		if err := c.PurgeDeclaredIPs(ctx, lid); err != nil {
+			c.log.Error("sweeping orphaned lease ips", "err", err, "lease", lid)
+			continue
+		}
+
+		swept = append(swept, lid)
+	}
+
+	return swept, nil
+}
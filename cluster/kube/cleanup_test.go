@@ -0,0 +1,158 @@
+package kube
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	kubeErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	"github.com/akash-network/node/sdl"
+	"github.com/akash-network/node/testutil"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+	akashclient_fake "github.com/akash-network/provider/pkg/client/clientset/versioned/fake"
+)
+
+func serviceLabels(lid mtypes.LeaseID, service string) map[string]string {
+	return map[string]string{
+		builder.AkashManagedLabelName:         "true",
+		builder.AkashManifestServiceLabelName: service,
+		"akash.network/namespace":             builder.LidNS(lid),
+	}
+}
+
+func leaseLabels(lid mtypes.LeaseID) map[string]string {
+	return builder.AppendLeaseLabels(lid, map[string]string{builder.AkashManagedLabelName: "true"})
+}
+
+func TestCleanupStaleResources(t *testing.T) {
+	ctx := context.Background()
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+
+	sdl, err := sdl.ReadFile("../../testdata/deployment/deployment.yaml")
+	require.NoError(t, err)
+
+	mani, err := sdl.Manifest()
+	require.NoError(t, err)
+
+	group := &mani.GetGroups()[0]
+
+	kc := kubefake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: ns, Labels: serviceLabels(lid, "web")}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: ns, Labels: serviceLabels(lid, "api")}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: ns, Labels: serviceLabels(lid, "db")}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: ns, Labels: serviceLabels(lid, "db")}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "db-data-db-0", Namespace: ns, Labels: serviceLabels(lid, "db")}},
+		&netv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "db.localhost", Namespace: ns, Labels: leaseLabels(lid)},
+			Spec:       netv1.IngressSpec{Rules: ingressRules("db.localhost", "db", 80)},
+		},
+		&netv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test.localhost", Namespace: ns, Labels: leaseLabels(lid)},
+			Spec:       netv1.IngressSpec{Rules: ingressRules("test.localhost", "web", 80)},
+		},
+	)
+
+	ac := akashclient_fake.NewSimpleClientset(
+		fakeProviderHost("db.localhost", lid, "db", 80),
+		fakeProviderHost("test.localhost", lid, "web", 80),
+		&crd.ProviderLeasedIP{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-ip", Namespace: testKubeClientNs, Labels: leaseLabels(lid)},
+			Spec:       crd.ProviderLeasedIPSpec{ServiceName: "db"},
+		},
+	)
+
+	c := clientForTest(t, kc, ac).(*client)
+
+	settings := builder.NewDefaultSettings()
+	settings.StaleVolumeRetention = time.Hour
+
+	require.NoError(t, c.cleanupStaleResources(ctx, settings, lid, group))
+
+	_, err = kc.AppsV1().Deployments(ns).Get(ctx, "web", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	_, err = kc.AppsV1().Deployments(ns).Get(ctx, "api", metav1.GetOptions{})
+	require.True(t, kubeErrors.IsNotFound(err))
+
+	_, err = kc.AppsV1().StatefulSets(ns).Get(ctx, "db", metav1.GetOptions{})
+	require.True(t, kubeErrors.IsNotFound(err))
+
+	_, err = kc.CoreV1().Services(ns).Get(ctx, "db", metav1.GetOptions{})
+	require.True(t, kubeErrors.IsNotFound(err))
+
+	_, err = kc.NetworkingV1().Ingresses(ns).Get(ctx, "db.localhost", metav1.GetOptions{})
+	require.True(t, kubeErrors.IsNotFound(err))
+
+	_, err = kc.NetworkingV1().Ingresses(ns).Get(ctx, "test.localhost", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	_, err = ac.AkashV2beta2().ProviderHosts(testKubeClientNs).Get(ctx, "db.localhost", metav1.GetOptions{})
+	require.True(t, kubeErrors.IsNotFound(err))
+
+	_, err = ac.AkashV2beta2().ProviderHosts(testKubeClientNs).Get(ctx, "test.localhost", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	_, err = ac.AkashV2beta2().ProviderLeasedIPs(testKubeClientNs).Get(ctx, "db-ip", metav1.GetOptions{})
+	require.True(t, kubeErrors.IsNotFound(err))
+
+	// volume is kept for retention period
+	pvc, err := kc.CoreV1().PersistentVolumeClaims(ns).Get(ctx, "db-data-db-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Contains(t, pvc.Annotations, staleSinceAnnotation)
+
+	pvc.Annotations[staleSinceAnnotation] = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	_, err = kc.CoreV1().PersistentVolumeClaims(ns).Update(ctx, pvc, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, c.cleanupStaleResources(ctx, settings, lid, group))
+
+	_, err = kc.CoreV1().PersistentVolumeClaims(ns).Get(ctx, "db-data-db-0", metav1.GetOptions{})
+	require.True(t, kubeErrors.IsNotFound(err))
+}
+
+func TestSweepOrphans(t *testing.T) {
+	active := testutil.LeaseID(t)
+	orphan := testutil.LeaseID(t)
+	young := testutil.LeaseID(t)
+
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	kc := kubefake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: builder.LidNS(active), Labels: leaseLabels(active), CreationTimestamp: created}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: builder.LidNS(orphan), Labels: leaseLabels(orphan), CreationTimestamp: created}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: builder.LidNS(young), Labels: leaseLabels(young), CreationTimestamp: metav1.Now()}},
+	)
+	ac := akashclient_fake.NewSimpleClientset()
+
+	c := clientForTest(t, kc, ac)
+
+	ctx := context.Background()
+
+	_, err := c.SweepOrphans(ctx, []mtypes.LeaseID{active}, 10*time.Minute)
+	require.Error(t, err)
+
+	ctx = context.WithValue(ctx, builder.SettingsKey, builder.NewDefaultSettings())
+
+	swept, err := c.SweepOrphans(ctx, []mtypes.LeaseID{active}, 10*time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, []mtypes.LeaseID{orphan}, swept)
+
+	_, err = kc.CoreV1().Namespaces().Get(ctx, builder.LidNS(orphan), metav1.GetOptions{})
+	require.True(t, kubeErrors.IsNotFound(err))
+
+	for _, lid := range []mtypes.LeaseID{active, young} {
+		_, err = kc.CoreV1().Namespaces().Get(ctx, builder.LidNS(lid), metav1.GetOptions{})
+		require.NoError(t, err)
+	}
+}
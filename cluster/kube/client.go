@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -17,6 +19,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/util/flowcontrol"
@@ -25,7 +28,6 @@ import (
 	mapi "github.com/akash-network/akash-api/go/manifest/v2beta2"
 	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
-	"github.com/akash-network/node/sdl"
 	sdlutil "github.com/akash-network/node/sdl/util"
 	metricsutils "github.com/akash-network/node/util/metrics"
 
@@ -47,6 +49,8 @@ var (
 // Client interface includes cluster client
 type Client interface {
 	cluster.Client
+	// ReapplyNetworkPolicies updates network policies of all lease namespaces with current settings
+	ReapplyNetworkPolicies(ctx context.Context) error
 }
 
 var _ Client = (*client)(nil)
@@ -55,6 +59,7 @@ type client struct {
 	kc                kubernetes.Interface
 	ac                akashclient.Interface
 	metc              metricsclient.Interface
+	dc                dynamic.Interface
 	ns                string
 	log               log.Logger
 	kubeContentConfig *restclient.Config
@@ -105,10 +110,16 @@ func NewClient(ctx context.Context, log log.Logger, ns string, configPath string
 		return nil, errors.Wrap(err, "kube: error creating metrics client")
 	}
 
+	dc, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "kube: error creating dynamic client")
+	}
+
 	return &client{
 		kc:                kc,
 		ac:                mc,
 		metc:              metc,
+		dc:                dc,
 		ns:                ns,
 		log:               log.With("client", "kube"),
 		kubeContentConfig: config,
@@ -169,6 +180,102 @@ func (c *client) GetManifestGroup(ctx context.Context, lID mtypes.LeaseID) (bool
 	return true, obj.Spec.Group, nil
 }
 
+func (c *client) SetLastGoodDeployment(ctx context.Context, lID mtypes.LeaseID) error {
+	obj, err := wrapKubeCall("manifests-get", func() (*crd.Manifest, error) {
+		return c.ac.AkashV2beta2().Manifests(c.ns).Get(ctx, builder.LidNS(lID), metav1.GetOptions{})
+	})
+	if err != nil {
+		return err
+	}
+
+	if obj.Spec.LastGood != nil && reflect.DeepEqual(*obj.Spec.LastGood, obj.Spec.Group) {
+		return nil
+	}
+
+	obj.Spec.LastGood = obj.Spec.Group.DeepCopy()
+	// newer group is healthy, previous rollback is no longer relevant
+	obj.Status = crd.ManifestStatus{}
+
+	_, err = wrapKubeCall("manifests-update", func() (*crd.Manifest, error) {
+		return c.ac.AkashV2beta2().Manifests(c.ns).Update(ctx, obj, metav1.UpdateOptions{})
+	})
+
+	return err
+}
+
+func (c *client) RollbackDeployment(ctx context.Context, lID mtypes.LeaseID, reason string) (ctypes.IDeployment, error) {
+	obj, err := wrapKubeCall("manifests-get", func() (*crd.Manifest, error) {
+		return c.ac.AkashV2beta2().Manifests(c.ns).Get(ctx, builder.LidNS(lID), metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// nothing to roll back to when the failing group is the last good one
+	if obj.Spec.LastGood == nil || reflect.DeepEqual(*obj.Spec.LastGood, obj.Spec.Group) {
+		return nil, kubeclienterrors.ErrNoLastGoodManifest
+	}
+
+	deployment, err := obj.LastGoodDeployment()
+	if err != nil {
+		return nil, err
+	}
+
+	obj.Status = crd.ManifestStatus{
+		State:   crd.ManifestStateRolledBack,
+		Message: reason,
+	}
+
+	_, err = wrapKubeCall("manifests-update", func() (*crd.Manifest, error) {
+		return c.ac.AkashV2beta2().Manifests(c.ns).Update(ctx, obj, metav1.UpdateOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// tenant sees the rollback in lease events
+	_, err = wrapKubeCall("events-create", func() (*eventsv1.Event, error) {
+		return c.kc.EventsV1().Events(builder.LidNS(lID)).Create(ctx, &eventsv1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "akash-rollback-",
+				Namespace:    builder.LidNS(lID),
+			},
+			EventTime:           metav1.NowMicro(),
+			ReportingController: "akash.network/provider",
+			ReportingInstance:   "akash-provider",
+			Action:              "Rollback",
+			Reason:              "RolledBack",
+			Regarding: corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Namespace",
+				Name:       builder.LidNS(lID),
+			},
+			Note: reason,
+			Type: corev1.EventTypeWarning,
+		}, metav1.CreateOptions{})
+	})
+	if err != nil {
+		c.log.Error("recording rollback event", "err", err, "lease", lID)
+	}
+
+	return deployment, nil
+}
+
+func (c *client) GetManifestStatus(ctx context.Context, lID mtypes.LeaseID) (crd.ManifestStatus, error) {
+	obj, err := wrapKubeCall("manifests-get", func() (*crd.Manifest, error) {
+		return c.ac.AkashV2beta2().Manifests(c.ns).Get(ctx, builder.LidNS(lID), metav1.GetOptions{})
+	})
+	if err != nil {
+		if kubeErrors.IsNotFound(err) {
+			return crd.ManifestStatus{}, kubeclienterrors.ErrNoManifestForLease
+		}
+
+		return crd.ManifestStatus{}, err
+	}
+
+	return obj.Status, nil
+}
+
 func (c *client) Deployments(ctx context.Context) ([]ctypes.IDeployment, error) {
 	manifests, err := wrapKubeCall("manifests-list", func() (*crd.ManifestList, error) {
 		return c.ac.AkashV2beta2().Manifests(c.ns).List(ctx, metav1.ListOptions{})
@@ -190,6 +297,41 @@ func (c *client) Deployments(ctx context.Context) ([]ctypes.IDeployment, error)
 	return deployments, nil
 }
 
+func (c *client) ReapplyNetworkPolicies(ctx context.Context) error {
+	settingsI := ctx.Value(builder.SettingsKey)
+	if nil == settingsI {
+		return kubeclienterrors.ErrNotConfiguredWithSettings
+	}
+	settings := settingsI.(builder.Settings)
+	if err := builder.ValidateSettings(settings); err != nil {
+		return err
+	}
+
+	deployments, err := c.Deployments(ctx)
+	if err != nil {
+		return err
+	}
+
+	var result error
+
+	for _, deployment := range deployments {
+		// keep going with the rest of leases, failed ones are updated with the next deploy
+		cdeployment, err := builder.ClusterDeploymentFromDeployment(deployment)
+		if err != nil {
+			c.log.Error("reading deployment for network policies", "err", err, "lease", deployment.LeaseID())
+			result = err
+			continue
+		}
+
+		if err := applyNetPolicies(ctx, c.kc, builder.BuildNetPol(settings, cdeployment)); err != nil {
+			c.log.Error("applying namespace network policies", "err", err, "lease", cdeployment.LeaseID())
+			result = err
+		}
+	}
+
+	return result
+}
+
 func (c *client) Deploy(ctx context.Context, deployment ctypes.IDeployment) error {
 	cdeployment, err := builder.ClusterDeploymentFromDeployment(deployment)
 	if err != nil {
@@ -218,13 +360,23 @@ func (c *client) Deploy(ctx context.Context, deployment ctypes.IDeployment) erro
 		return err
 	}
 
+	if err := applyResourceQuota(ctx, c.kc, builder.BuildResourceQuota(settings, cdeployment), settings.ResourceQuotasEnabled); err != nil {
+		c.log.Error("applying namespace resource quota", "err", err, "lease", lid)
+		return err
+	}
+
+	if err := applyLimitRange(ctx, c.kc, builder.BuildLimitRange(settings, cdeployment), settings.ResourceQuotasEnabled); err != nil {
+		c.log.Error("applying namespace limit range", "err", err, "lease", lid)
+		return err
+	}
+
 	cmanifest := builder.BuildManifest(c.log, settings, c.ns, cdeployment)
 	if err := applyManifest(ctx, c.ac, cmanifest); err != nil {
 		c.log.Error("applying manifest", "err", err, "lease", lid)
 		return err
 	}
 
-	if err := cleanupStaleResources(ctx, c.kc, lid, group); err != nil {
+	if err := c.cleanupStaleResources(ctx, settings, lid, group); err != nil {
 		c.log.Error("cleaning stale resources", "err", err, "lease", lid)
 		return err
 	}
@@ -234,16 +386,14 @@ func (c *client) Deploy(ctx context.Context, deployment ctypes.IDeployment) erro
 
 		service := &group.Services[svcIdx]
 
-		persistent := false
-		for i := range service.Resources.Storage {
-			attrVal := service.Resources.Storage[i].Attributes.Find(sdl.StorageAttributePersistent)
-			if persistent, _ = attrVal.AsBool(); persistent {
-				break
+		if servicePersistent(service) {
+			restartedAt, err := c.expandVolumes(ctx, builder.LidNS(lid), service)
+			if err != nil {
+				c.log.Error("expanding volumes", "err", err, "lease", lid, "service", service.Name)
+				return err
 			}
-		}
 
-		if persistent {
-			if err := applyStatefulSet(ctx, c.kc, builder.BuildStatefulSet(workload)); err != nil {
+			if err := applyStatefulSet(ctx, c.kc, builder.BuildStatefulSet(workload), restartedAt); err != nil {
 				c.log.Error("applying statefulSet", "err", err, "lease", lid, "service", service.Name)
 				return err
 			}
@@ -385,15 +535,14 @@ func (c *client) LeaseEvents(ctx context.Context, lid mtypes.LeaseID, services s
 	return wtch, nil
 }
 
-func (c *client) LeaseLogs(ctx context.Context, lid mtypes.LeaseID,
-	services string, follow bool, tailLines *int64) ([]*ctypes.ServiceLog, error) {
+func (c *client) LeaseLogs(ctx context.Context, lid mtypes.LeaseID, opts ctypes.LeaseLogsOptions) ([]*ctypes.ServiceLog, error) {
 	if err := c.leaseExists(ctx, lid); err != nil {
 		return nil, err
 	}
 
 	listOpts := metav1.ListOptions{}
-	if len(services) != 0 {
-		listOpts.LabelSelector = fmt.Sprintf(builder.AkashManifestServiceLabelName+" in (%s)", services)
+	if len(opts.Services) != 0 {
+		listOpts.LabelSelector = fmt.Sprintf(builder.AkashManifestServiceLabelName+" in (%s)", opts.Services)
 	}
 
 	c.log.Info("filtering pods", "labelSelector", listOpts.LabelSelector)
@@ -405,25 +554,73 @@ func (c *client) LeaseLogs(ctx context.Context, lid mtypes.LeaseID,
 		c.log.Error("listing pods", "err", err)
 		return nil, errors.Wrap(err, kubeclienterrors.ErrInternalError.Error())
 	}
-	streams := make([]*ctypes.ServiceLog, len(pods.Items))
-	for i, pod := range pods.Items {
+
+	items := pods.Items
+	if opts.Replica != nil {
+		items = podsAtReplica(items, *opts.Replica)
+	}
+
+	logOpts := &corev1.PodLogOptions{
+		Follow:       opts.Follow,
+		TailLines:    opts.TailLines,
+		Timestamps:   opts.Timestamps,
+		Previous:     opts.Previous,
+		SinceSeconds: opts.SinceSeconds,
+		LimitBytes:   opts.LimitBytes,
+	}
+
+	if opts.SinceTime != nil {
+		since := metav1.NewTime(*opts.SinceTime)
+		logOpts.SinceTime = &since
+	}
+
+	streams := make([]*ctypes.ServiceLog, 0, len(items))
+	for _, pod := range items {
 		stream, err := wrapKubeCall("pods-getlogs", func() (io.ReadCloser, error) {
-			return c.kc.CoreV1().Pods(builder.LidNS(lid)).GetLogs(pod.Name, &corev1.PodLogOptions{
-				Follow:     follow,
-				TailLines:  tailLines,
-				Timestamps: false,
-			}).Stream(ctx)
+			return c.kc.CoreV1().Pods(builder.LidNS(lid)).GetLogs(pod.Name, logOpts).Stream(ctx)
 		})
 
+		// containers which have never been restarted do not have previous logs
+		if err != nil && opts.Previous && kubeErrors.IsBadRequest(err) {
+			c.log.Debug("no previous logs", "pod", pod.Name, "err", err)
+			continue
+		}
+
 		if err != nil {
 			c.log.Error("get pod logs", "err", err)
+			for _, stream := range streams {
+				_ = stream.Stream.Close()
+			}
 			return nil, errors.Wrap(err, kubeclienterrors.ErrInternalError.Error())
 		}
-		streams[i] = cluster.NewServiceLog(pod.Name, stream)
+		streams = append(streams, cluster.NewServiceLog(pod.Name, stream))
 	}
 	return streams, nil
 }
 
+// podsAtReplica picks pod of every service by its index, in the same order replicas are numbered by Exec
+func podsAtReplica(pods []corev1.Pod, replica uint) []corev1.Pod {
+	services := make(map[string]sortablePods)
+	for _, pod := range pods {
+		name := pod.Labels[builder.AkashManifestServiceLabelName]
+		services[name] = append(services[name], pod)
+	}
+
+	result := make(sortablePods, 0, len(services))
+	for _, spods := range services {
+		if replica >= uint(len(spods)) {
+			continue
+		}
+
+		sort.Sort(spods)
+		result = append(result, spods[replica])
+	}
+
+	sort.Sort(result)
+
+	return result
+}
+
 func (c *client) ForwardedPortStatus(ctx context.Context, leaseID mtypes.LeaseID) (map[string][]ctypes.ForwardedPortStatus, error) {
 	settingsI := ctx.Value(builder.SettingsKey)
 	if nil == settingsI {
@@ -549,8 +746,14 @@ func (c *client) ServiceStatus(ctx context.Context, lid mtypes.LeaseID, name str
 	for _, svc := range mani.Spec.Group.Services {
 		if svc.Name == name {
 			if params := svc.Params; params != nil {
+				ram := make(map[string]bool)
+				for _, storage := range svc.Resources.Storage {
+					ram[storage.Name] = storage.Class == ctypes.StorageClassRAM
+				}
+
 				for _, param := range params.Storage {
-					if param.Mount != "" {
+					// memory backed volumes are mounted into deployments
+					if param.Mount != "" && !ram[param.Name] {
 						isDeployment = false
 					}
 				}
@@ -575,16 +778,7 @@ func (c *client) ServiceStatus(ctx context.Context, lid mtypes.LeaseID, name str
 			return nil, kubeclienterrors.ErrNoDeploymentForLease
 		}
 
-		result = &ctypes.ServiceStatus{
-			Name:               deployment.Name,
-			Available:          deployment.Status.AvailableReplicas,
-			Total:              deployment.Status.Replicas,
-			ObservedGeneration: deployment.Status.ObservedGeneration,
-			Replicas:           deployment.Status.Replicas,
-			UpdatedReplicas:    deployment.Status.UpdatedReplicas,
-			ReadyReplicas:      deployment.Status.ReadyReplicas,
-			AvailableReplicas:  deployment.Status.AvailableReplicas,
-		}
+		result = deploymentServiceStatus(deployment)
 	} else {
 		c.log.Debug("get statefulsets", "lease-ns", builder.LidNS(lid), "name", name)
 		statefulset, err := wrapKubeCall("statefulsets-get", func() (*appsv1.StatefulSet, error) {
@@ -600,16 +794,7 @@ func (c *client) ServiceStatus(ctx context.Context, lid mtypes.LeaseID, name str
 			return nil, kubeclienterrors.ErrNoDeploymentForLease
 		}
 
-		result = &ctypes.ServiceStatus{
-			Name:               statefulset.Name,
-			Available:          statefulset.Status.CurrentReplicas,
-			Total:              statefulset.Status.Replicas,
-			ObservedGeneration: statefulset.Status.ObservedGeneration,
-			Replicas:           statefulset.Status.Replicas,
-			UpdatedReplicas:    statefulset.Status.UpdatedReplicas,
-			ReadyReplicas:      statefulset.Status.ReadyReplicas,
-			AvailableReplicas:  statefulset.Status.CurrentReplicas,
-		}
+		result = statefulSetServiceStatus(statefulset)
 	}
 
 	hasHostnames := false
@@ -716,32 +901,16 @@ func (c *client) deploymentsForLease(ctx context.Context, lid mtypes.LeaseID) (m
 	serviceStatus := make(map[string]*ctypes.ServiceStatus)
 
 	if deployments != nil {
-		for _, deployment := range deployments.Items {
-			serviceStatus[deployment.Name] = &ctypes.ServiceStatus{
-				Name:               deployment.Name,
-				Available:          deployment.Status.AvailableReplicas,
-				Total:              deployment.Status.Replicas,
-				ObservedGeneration: deployment.Status.ObservedGeneration,
-				Replicas:           deployment.Status.Replicas,
-				UpdatedReplicas:    deployment.Status.UpdatedReplicas,
-				ReadyReplicas:      deployment.Status.ReadyReplicas,
-				AvailableReplicas:  deployment.Status.AvailableReplicas,
-			}
+		for i := range deployments.Items {
+			deployment := &deployments.Items[i]
+			serviceStatus[deployment.Name] = deploymentServiceStatus(deployment)
 		}
 	}
 
 	if statefulsets != nil {
-		for _, statefulset := range statefulsets.Items {
-			serviceStatus[statefulset.Name] = &ctypes.ServiceStatus{
-				Name:               statefulset.Name,
-				Available:          statefulset.Status.CurrentReplicas,
-				Total:              statefulset.Status.Replicas,
-				ObservedGeneration: statefulset.Status.ObservedGeneration,
-				Replicas:           statefulset.Status.Replicas,
-				UpdatedReplicas:    statefulset.Status.UpdatedReplicas,
-				ReadyReplicas:      statefulset.Status.ReadyReplicas,
-				AvailableReplicas:  statefulset.Status.CurrentReplicas,
-			}
+		for i := range statefulsets.Items {
+			statefulset := &statefulsets.Items[i]
+			serviceStatus[statefulset.Name] = statefulSetServiceStatus(statefulset)
 		}
 	}
 
@@ -753,6 +922,48 @@ func (c *client) deploymentsForLease(ctx context.Context, lid mtypes.LeaseID) (m
 	return serviceStatus, nil
 }
 
+func deploymentServiceStatus(deployment *appsv1.Deployment) *ctypes.ServiceStatus {
+	progressing := false
+	for _, cond := range deployment.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing {
+			progressing = cond.Status == corev1.ConditionTrue
+			break
+		}
+	}
+
+	return &ctypes.ServiceStatus{
+		Name:               deployment.Name,
+		Available:          deployment.Status.AvailableReplicas,
+		Ready:              deployment.Status.ReadyReplicas,
+		Total:              deployment.Status.Replicas,
+		ObservedGeneration: deployment.Status.ObservedGeneration,
+		Replicas:           deployment.Status.Replicas,
+		UpdatedReplicas:    deployment.Status.UpdatedReplicas,
+		ReadyReplicas:      deployment.Status.ReadyReplicas,
+		AvailableReplicas:  deployment.Status.AvailableReplicas,
+		Generation:         deployment.Generation,
+		Progressing:        progressing,
+	}
+}
+
+func statefulSetServiceStatus(statefulset *appsv1.StatefulSet) *ctypes.ServiceStatus {
+	return &ctypes.ServiceStatus{
+		Name:               statefulset.Name,
+		Available:          statefulset.Status.CurrentReplicas,
+		Ready:              statefulset.Status.ReadyReplicas,
+		Total:              statefulset.Status.Replicas,
+		ObservedGeneration: statefulset.Status.ObservedGeneration,
+		Replicas:           statefulset.Status.Replicas,
+		UpdatedReplicas:    statefulset.Status.UpdatedReplicas,
+		ReadyReplicas:      statefulset.Status.ReadyReplicas,
+		AvailableReplicas:  statefulset.Status.CurrentReplicas,
+		Generation:         statefulset.Generation,
+		// statefulset controller does not report stalled rollouts, pods stuck on the update revision
+		// keep UpdatedReplicas or ReadyReplicas below the target
+		Progressing: true,
+	}
+}
+
 func (c *client) KubeVersion() (*version.Info, error) {
 	return wrapKubeCall("discovery-serverversion", func() (*version.Info, error) {
 		return c.kc.Discovery().ServerVersion()
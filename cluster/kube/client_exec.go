@@ -21,6 +21,7 @@ import (
 	"github.com/akash-network/provider/cluster"
 	"github.com/akash-network/provider/cluster/kube/builder"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 )
 
 // the type implementing the interface returned by the Exec command
@@ -53,20 +54,97 @@ func (c *client) Exec(ctx context.Context, leaseID mtypes.LeaseID, serviceName s
 	tsq remotecommand.TerminalSizeQueue) (ctypes.ExecResult, error) {
 	namespace := builder.LidNS(leaseID)
 
+	_, selectedPod, err := c.leaseServicePod(ctx, leaseID, serviceName, podIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	podName := selectedPod.Name
+	containerName := serviceName // Container name is always the same as the service name
+
+	kubeRestClient, kubeConfig, myParameterCodec, err := c.podsRESTClient()
+	if err != nil {
+		return nil, err
+	}
+
+	c.log.Info("Opening container shell", "namespace", namespace, "pod", podName, "container", containerName)
+	if tty {
+		// disable stderr if running as a TTY, results come back over stdout
+		stderr = nil
+	}
+
+	const subResource = "exec" // This value copied from kubectl and never changes
+	// Configure the request
+	req := kubeRestClient.Post().Resource("pods").Name(podName).Namespace(namespace).SubResource(subResource)
+	req.VersionedParams(&corev1.PodExecOptions{
+		Container: containerName,
+		Command:   cmd,
+		Stdin:     stdin != nil,
+		Stdout:    stdout != nil,
+		Stderr:    stderr != nil,
+		TTY:       tty,
+	}, myParameterCodec)
+
+	// Make the request with SPDY
+	exec, err := remotecommand.NewSPDYExecutor(kubeConfig, "POST", req.URL())
+	if err != nil {
+		return nil, fmt.Errorf("%w: execution via SPDY failed", err)
+	}
+
+	// Run, passing in the streams and everything else. This runs until the remote end closes
+	// or the streams close
+	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:             stdin,  // any reader
+		Stdout:            stdout, // any writer
+		Stderr:            stderr, // any writer
+		Tty:               tty,
+		TerminalSizeQueue: tsq,
+	})
+	if err == nil {
+		// No error means the process returned a 0 exit code
+		return execResult{exitCode: 0}, nil
+	}
+
+	// Check to see if the process ran & returned an exit code
+	// If this is true, don't return an error. Something ran in the
+	// container which is what this code was trying to do
+	if err, ok := err.(executil.CodeExitError); ok {
+		return execResult{exitCode: err.Code}, nil
+	}
+
+	// Some errors are untyped, use string matching to give better answers
+	if strings.Contains(err.Error(), "error executing command in container") {
+		if strings.Contains(err.Error(), "no such file or directory") || strings.Contains(err.Error(), "executable file not found in $PATH") {
+			return nil, cluster.ErrExecCommandDoesNotExist
+		}
+		// Don't send the full text of unknown errors back to the user
+		// Log the error here so this can be tracked down somehow in the provider logs at least
+		c.log.Error("command execution failed", "err", err)
+		return nil, cluster.ErrExecCommandExecutionFailed
+	}
+
+	return nil, err
+}
+
+// leaseServicePod finds manifest of the service and its running replica at podIndex
+func (c *client) leaseServicePod(ctx context.Context, leaseID mtypes.LeaseID, serviceName string, podIndex uint) (*crd.ManifestService, *corev1.Pod, error) {
+	namespace := builder.LidNS(leaseID)
+
 	mani, err := c.ac.AkashV2beta2().Manifests(c.ns).Get(ctx, namespace, metav1.GetOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed getting manifest", err)
+		return nil, nil, fmt.Errorf("%w: failed getting manifest", err)
 	}
 
-loop:
+	var service *crd.ManifestService
 	for idx := range mani.Spec.Group.Services {
 		if mani.Spec.Group.Services[idx].Name == serviceName {
-			break loop
+			service = &mani.Spec.Group.Services[idx]
+			break
 		}
+	}
 
-		if idx == len(mani.Spec.Group.Services)-1 {
-			return nil, cluster.ErrExecNoServiceWithName
-		}
+	if service == nil {
+		return nil, nil, cluster.ErrExecNoServiceWithName
 	}
 
 	// Check that the pod exists
@@ -75,17 +153,17 @@ loop:
 		LabelSelector: fmt.Sprintf("akash.network/manifest-service=%s", serviceName),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed getting pods in namespace %q", err, namespace)
+		return nil, nil, fmt.Errorf("%w: failed getting pods in namespace %q", err, namespace)
 	}
 
 	// if no pods are found yet then the deployment hasn't been spun up kubernetes yet
 	if 0 == len(pods.Items) {
-		return nil, cluster.ErrExecServiceNotRunning
+		return nil, nil, cluster.ErrExecServiceNotRunning
 	}
 
 	// check that the requested pod is within the range
 	if podIndex >= uint(len(pods.Items)) {
-		return nil, fmt.Errorf("%w: valid range is [0, %d]", cluster.ErrExecPodIndexOutOfRange, len(pods.Items)-1)
+		return nil, nil, fmt.Errorf("%w: valid range is [0, %d]", cluster.ErrExecPodIndexOutOfRange, len(pods.Items)-1)
 	}
 
 	// sort the pods, since we have no idea what order kubernetes returns them in
@@ -95,9 +173,9 @@ loop:
 	// validate the pod is in a state where it can be connected to
 	switch selectedPod.Status.Phase {
 	case corev1.PodSucceeded:
-		return nil, fmt.Errorf("%w: the service has completed", cluster.ErrExecServiceNotRunning)
+		return nil, nil, fmt.Errorf("%w: the service has completed", cluster.ErrExecServiceNotRunning)
 	case corev1.PodFailed:
-		return nil, fmt.Errorf("%w: the service has failed", cluster.ErrExecServiceNotRunning)
+		return nil, nil, fmt.Errorf("%w: the service has failed", cluster.ErrExecServiceNotRunning)
 	default:
 	}
 
@@ -110,18 +188,20 @@ loop:
 	}
 
 	if !isReady {
-		return nil, fmt.Errorf("%w: the service is not ready", cluster.ErrExecServiceNotRunning)
+		return nil, nil, fmt.Errorf("%w: the service is not ready", cluster.ErrExecServiceNotRunning)
 	}
 
-	podName := selectedPod.Name
-	containerName := serviceName // Container name is always the same as the service name
+	return service, &selectedPod, nil
+}
 
+// podsRESTClient creates REST client for pods subresources streamed over SPDY
+func (c *client) podsRESTClient() (*restclient.RESTClient, *restclient.Config, runtime.ParameterCodec, error) {
 	// Define the necessary runtime scheme & codec to send the request
 	groupVersion := schema.GroupVersion{Group: "api", Version: "v1"}
 	myScheme := runtime.NewScheme()
-	err = corev1.AddToScheme(myScheme)
+	err := corev1.AddToScheme(myScheme)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	myParameterCodec := runtime.NewParameterCodec(myScheme)
 	myScheme.AddKnownTypes(groupVersion, &corev1.PodExecOptions{})
@@ -135,64 +215,8 @@ loop:
 
 	kubeRestClient, err := restclient.RESTClientFor(&kubeConfig)
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed getting REST client", err)
-	}
-
-	c.log.Info("Opening container shell", "namespace", namespace, "pod", podName, "container", containerName)
-	if tty {
-		// disable stderr if running as a TTY, results come back over stdout
-		stderr = nil
-	}
-
-	const subResource = "exec" // This value copied from kubectl and never changes
-	// Configure the request
-	req := kubeRestClient.Post().Resource("pods").Name(podName).Namespace(namespace).SubResource(subResource)
-	req.VersionedParams(&corev1.PodExecOptions{
-		Container: containerName,
-		Command:   cmd,
-		Stdin:     stdin != nil,
-		Stdout:    stdout != nil,
-		Stderr:    stderr != nil,
-		TTY:       tty,
-	}, myParameterCodec)
-
-	// Make the request with SPDY
-	exec, err := remotecommand.NewSPDYExecutor(&kubeConfig, "POST", req.URL())
-	if err != nil {
-		return nil, fmt.Errorf("%w: execution via SPDY failed", err)
+		return nil, nil, nil, fmt.Errorf("%w: failed getting REST client", err)
 	}
 
-	// Run, passing in the streams and everything else. This runs until the remote end closes
-	// or the streams close
-	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
-		Stdin:             stdin,  // any reader
-		Stdout:            stdout, // any writer
-		Stderr:            stderr, // any writer
-		Tty:               tty,
-		TerminalSizeQueue: tsq,
-	})
-	if err == nil {
-		// No error means the process returned a 0 exit code
-		return execResult{exitCode: 0}, nil
-	}
-
-	// Check to see if the process ran & returned an exit code
-	// If this is true, don't return an error. Something ran in the
-	// container which is what this code was trying to do
-	if err, ok := err.(executil.CodeExitError); ok {
-		return execResult{exitCode: err.Code}, nil
-	}
-
-	// Some errors are untyped, use string matching to give better answers
-	if strings.Contains(err.Error(), "error executing command in container") {
-		if strings.Contains(err.Error(), "no such file or directory") || strings.Contains(err.Error(), "executable file not found in $PATH") {
-			return nil, cluster.ErrExecCommandDoesNotExist
-		}
-		// Don't send the full text of unknown errors back to the user
-		// Log the error here so this can be tracked down somehow in the provider logs at least
-		c.log.Error("command execution failed", "err", err)
-		return nil, cluster.ErrExecCommandExecutionFailed
-	}
-
-	return nil, err
+	return kubeRestClient, &kubeConfig, myParameterCodec, nil
 }
@@ -0,0 +1,241 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+// podUsage is resource usage of the pod as reported by either of metrics sources
+type podUsage struct {
+	cpu       uint64
+	memory    uint64
+	rx        uint64
+	tx        uint64
+	timestamp time.Time
+}
+
+// kubeletSummary is subset of kubelet stats summary (stats/v1alpha1) used for lease metrics
+type kubeletSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		CPU *struct {
+			Time           metav1.Time `json:"time"`
+			UsageNanoCores *uint64     `json:"usageNanoCores"`
+		} `json:"cpu"`
+		Memory *struct {
+			WorkingSetBytes *uint64 `json:"workingSetBytes"`
+		} `json:"memory"`
+		Network *struct {
+			RxBytes *uint64 `json:"rxBytes"`
+			TxBytes *uint64 `json:"txBytes"`
+		} `json:"network"`
+	} `json:"pods"`
+}
+
+func (c *client) LeaseMetrics(ctx context.Context, lid mtypes.LeaseID) (*ctypes.LeaseMetrics, error) {
+	if err := c.leaseExists(ctx, lid); err != nil {
+		return nil, err
+	}
+
+	ns := builder.LidNS(lid)
+
+	pods, err := wrapKubeCall("pods-list", func() (*corev1.PodList, error) {
+		return c.kc.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		c.log.Error("listing pods", "err", err)
+		return nil, errors.Wrap(err, kubeclienterrors.ErrInternalError.Error())
+	}
+
+	running := make([]corev1.Pod, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			running = append(running, pod)
+		}
+	}
+
+	result := &ctypes.LeaseMetrics{
+		Source:   ctypes.MetricsSourceAPI,
+		Services: make(map[string]*ctypes.ServiceMetrics),
+	}
+
+	usage, err := c.podUsageFromMetricsAPI(ctx, ns)
+	if err != nil {
+		// metrics-server is optional, kubelet summary is always there
+		c.log.Debug("metrics api unavailable, falling back to kubelet summary", "ns", ns, "err", err)
+
+		usage, err = c.podUsageFromKubelet(ctx, ns, running)
+		if err != nil {
+			c.log.Error("reading kubelet summary", "ns", ns, "err", err)
+			return nil, errors.Wrap(err, kubeclienterrors.ErrInternalError.Error())
+		}
+
+		result.Source = ctypes.MetricsSourceKubelet
+	}
+
+	for _, pod := range running {
+		name := pod.Labels[builder.AkashManifestServiceLabelName]
+		if name == "" {
+			continue
+		}
+
+		service, exists := result.Services[name]
+		if !exists {
+			service = &ctypes.ServiceMetrics{}
+			result.Services[name] = service
+		}
+
+		pu := usage[pod.Name]
+		pm := ctypes.PodMetrics{
+			Name:      pod.Name,
+			CPU:       pu.cpu,
+			Memory:    pu.memory,
+			GPU:       podGPUs(pod),
+			Timestamp: pu.timestamp,
+		}
+
+		service.CPU += pm.CPU
+		service.Memory += pm.Memory
+		service.GPU += pm.GPU
+
+		// network counters are only reported by kubelet, leave them unset otherwise
+		if result.Source == ctypes.MetricsSourceKubelet {
+			rx, tx := pu.rx, pu.tx
+			pm.NetworkRx = &rx
+			pm.NetworkTx = &tx
+
+			if service.NetworkRx == nil {
+				service.NetworkRx = new(uint64)
+				service.NetworkTx = new(uint64)
+			}
+			*service.NetworkRx += rx
+			*service.NetworkTx += tx
+		}
+
+		service.Pods = append(service.Pods, pm)
+	}
+
+	return result, nil
+}
+
+func (c *client) podUsageFromMetricsAPI(ctx context.Context, ns string) (map[string]podUsage, error) {
+	list, err := wrapKubeCall("podmetrics-list", func() (*metricsv1beta1.PodMetricsList, error) {
+		return c.metc.MetricsV1beta1().PodMetricses(ns).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]podUsage, len(list.Items))
+	for _, item := range list.Items {
+		pu := podUsage{
+			timestamp: item.Timestamp.Time,
+		}
+
+		for _, container := range item.Containers {
+			pu.cpu += uint64(container.Usage.Cpu().MilliValue())
+			pu.memory += uint64(container.Usage.Memory().Value())
+		}
+
+		res[item.Name] = pu
+	}
+
+	return res, nil
+}
+
+// podUsageFromKubelet reads stats summary from kubelets of every node running pods of the lease
+func (c *client) podUsageFromKubelet(ctx context.Context, ns string, pods []corev1.Pod) (map[string]podUsage, error) {
+	nodes := make(map[string]bool)
+	res := make(map[string]podUsage, len(pods))
+
+	for _, pod := range pods {
+		node := pod.Spec.NodeName
+		if node == "" || nodes[node] {
+			continue
+		}
+		nodes[node] = true
+
+		data, err := wrapKubeCall("nodes-stats-summary", func() ([]byte, error) {
+			return c.kc.CoreV1().RESTClient().Get().AbsPath("/api/v1/nodes", node, "proxy", "stats", "summary").DoRaw(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err = podUsageFromSummary(data, ns, res); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// podUsageFromSummary collects usage of pods in namespace ns from kubelet stats summary into res
+func podUsageFromSummary(data []byte, ns string, res map[string]podUsage) error {
+	summary := kubeletSummary{}
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return err
+	}
+
+	for _, stats := range summary.Pods {
+		if stats.PodRef.Namespace != ns {
+			continue
+		}
+
+		pu := podUsage{}
+
+		if stats.CPU != nil {
+			pu.timestamp = stats.CPU.Time.Time
+			if stats.CPU.UsageNanoCores != nil {
+				pu.cpu = *stats.CPU.UsageNanoCores / 1000000
+			}
+		}
+
+		if stats.Memory != nil && stats.Memory.WorkingSetBytes != nil {
+			pu.memory = *stats.Memory.WorkingSetBytes
+		}
+
+		if stats.Network != nil {
+			if stats.Network.RxBytes != nil {
+				pu.rx = *stats.Network.RxBytes
+			}
+			if stats.Network.TxBytes != nil {
+				pu.tx = *stats.Network.TxBytes
+			}
+		}
+
+		res[stats.PodRef.Name] = pu
+	}
+
+	return nil
+}
+
+// podGPUs returns number of GPU devices requested by containers of the pod
+func podGPUs(pod corev1.Pod) uint64 {
+	var res uint64
+
+	for _, container := range pod.Spec.Containers {
+		for _, name := range []corev1.ResourceName{builder.ResourceGPUNvidia, builder.ResourceGPUAMD} {
+			if val, exists := container.Resources.Limits[name]; exists {
+				res += uint64(val.Value())
+			}
+		}
+	}
+
+	return res
+}
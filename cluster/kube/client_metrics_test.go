@@ -0,0 +1,164 @@
+package kube
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+
+	"github.com/akash-network/node/testutil"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	akashclient_fake "github.com/akash-network/provider/pkg/client/clientset/versioned/fake"
+)
+
+func metricsTestPod(ns string, name string, labels map[string]string, phase corev1.PodPhase, gpus int64) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: labels},
+		Spec: corev1.PodSpec{
+			NodeName: "node0",
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+
+	if gpus > 0 {
+		pod.Spec.Containers[0].Resources.Limits[builder.ResourceGPUNvidia] = *resource.NewQuantity(gpus, resource.DecimalSI)
+	}
+
+	return pod
+}
+
+func TestLeaseMetrics(t *testing.T) {
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	kc := kubefake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		metricsTestPod(ns, "web-0", serviceLabels(lid, "web"), corev1.PodRunning, 0),
+		metricsTestPod(ns, "web-1", serviceLabels(lid, "web"), corev1.PodRunning, 0),
+		metricsTestPod(ns, "web-2", serviceLabels(lid, "web"), corev1.PodPending, 0),
+		metricsTestPod(ns, "ml-0", serviceLabels(lid, "ml"), corev1.PodRunning, 2),
+	)
+
+	usage := func(cpu string, memory string) corev1.ResourceList {
+		return corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}
+	}
+
+	metc := metricsfake.NewSimpleClientset()
+	// fake clientset lists resource "pods" while its tracker keeps PodMetrics under "podmetricses"
+	metc.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &metricsv1beta1.PodMetricsList{
+			Items: []metricsv1beta1.PodMetrics{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: ns},
+					Timestamp:  metav1.NewTime(now),
+					Containers: []metricsv1beta1.ContainerMetrics{
+						{Name: "app", Usage: usage("150m", "64Mi")},
+						{Name: "sidecar", Usage: usage("50m", "16Mi")},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: ns},
+					Timestamp:  metav1.NewTime(now),
+					Containers: []metricsv1beta1.ContainerMetrics{
+						{Name: "app", Usage: usage("1", "128Mi")},
+					},
+				},
+			},
+		}, nil
+	})
+
+	c := clientForTest(t, kc, akashclient_fake.NewSimpleClientset()).(*client)
+	c.metc = metc
+
+	ctx := context.Background()
+
+	_, err := c.LeaseMetrics(ctx, testutil.LeaseID(t))
+	require.ErrorIs(t, err, kubeclienterrors.ErrLeaseNotFound)
+
+	metrics, err := c.LeaseMetrics(ctx, lid)
+	require.NoError(t, err)
+	require.Equal(t, ctypes.MetricsSourceAPI, metrics.Source)
+	require.Len(t, metrics.Services, 2)
+
+	web := metrics.Services["web"]
+	require.NotNil(t, web)
+	require.Len(t, web.Pods, 2)
+	require.Equal(t, uint64(1200), web.CPU)
+	require.Equal(t, uint64(208*1024*1024), web.Memory)
+	require.Nil(t, web.NetworkRx)
+	require.Nil(t, web.NetworkTx)
+
+	for _, pod := range web.Pods {
+		require.Equal(t, now, pod.Timestamp.UTC())
+		require.Nil(t, pod.NetworkRx)
+		if pod.Name == "web-0" {
+			require.Equal(t, uint64(200), pod.CPU)
+			require.Equal(t, uint64(80*1024*1024), pod.Memory)
+		}
+	}
+
+	// pod without metrics yet is reported with zero usage
+	ml := metrics.Services["ml"]
+	require.NotNil(t, ml)
+	require.Len(t, ml.Pods, 1)
+	require.Equal(t, uint64(2), ml.GPU)
+	require.Zero(t, ml.CPU)
+}
+
+func TestPodUsageFromSummary(t *testing.T) {
+	const summary = `{
+  "node": {"nodeName": "node0"},
+  "pods": [
+    {
+      "podRef": {"name": "web-0", "namespace": "lease"},
+      "cpu": {"time": "2023-10-17T12:00:00Z", "usageNanoCores": 250000000},
+      "memory": {"workingSetBytes": 1048576},
+      "network": {"name": "eth0", "rxBytes": 1000, "txBytes": 2000}
+    },
+    {
+      "podRef": {"name": "other-0", "namespace": "other"},
+      "cpu": {"time": "2023-10-17T12:00:00Z", "usageNanoCores": 1000000000}
+    },
+    {
+      "podRef": {"name": "web-1", "namespace": "lease"}
+    }
+  ]
+}`
+
+	res := make(map[string]podUsage)
+	require.NoError(t, podUsageFromSummary([]byte(summary), "lease", res))
+	require.Len(t, res, 2)
+
+	web := res["web-0"]
+	require.Equal(t, uint64(250), web.cpu)
+	require.Equal(t, uint64(1048576), web.memory)
+	require.Equal(t, uint64(1000), web.rx)
+	require.Equal(t, uint64(2000), web.tx)
+	require.True(t, time.Date(2023, 10, 17, 12, 0, 0, 0, time.UTC).Equal(web.timestamp))
+
+	require.Equal(t, podUsage{}, res["web-1"])
+
+	require.Error(t, podUsageFromSummary([]byte("{"), "lease", res))
+}
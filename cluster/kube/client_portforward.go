@@ -0,0 +1,141 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+
+	"github.com/akash-network/provider/cluster"
+	"github.com/akash-network/provider/cluster/kube/builder"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+// servicePortExposed checks the service declares TCP port in its manifest.
+// Ports do not have to be global to be forwarded
+func servicePortExposed(service *crd.ManifestService, port uint32) bool {
+	for _, expose := range service.Expose {
+		if uint32(expose.Port) != port {
+			continue
+		}
+
+		if expose.Proto == "" || strings.EqualFold(expose.Proto, string(corev1.ProtocolTCP)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *client) PortForward(ctx context.Context, leaseID mtypes.LeaseID, serviceName string, podIndex uint, port uint32, input io.Reader, output io.Writer) error {
+	namespace := builder.LidNS(leaseID)
+
+	service, pod, err := c.leaseServicePod(ctx, leaseID, serviceName, podIndex)
+	if err != nil {
+		return err
+	}
+
+	if !servicePortExposed(service, port) {
+		return fmt.Errorf("%w: %d", cluster.ErrExecPortNotExposed, port)
+	}
+
+	kubeRestClient, kubeConfig, _, err := c.podsRESTClient()
+	if err != nil {
+		return err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	const subResource = "portforward"
+	req := kubeRestClient.Post().Resource("pods").Namespace(namespace).Name(pod.Name).SubResource(subResource)
+
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	conn, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+	if err != nil {
+		return fmt.Errorf("%w: failed dialing port forward", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	c.log.Info("forwarding port", "namespace", namespace, "pod", pod.Name, "port", port)
+
+	headers := http.Header{}
+	headers.Set(corev1.StreamType, corev1.StreamTypeError)
+	headers.Set(corev1.PortHeader, strconv.FormatUint(uint64(port), 10))
+	headers.Set(corev1.PortForwardRequestIDHeader, "0")
+
+	errorStream, err := conn.CreateStream(headers)
+	if err != nil {
+		return fmt.Errorf("%w: failed creating error stream", err)
+	}
+	// nothing is ever written to error stream
+	_ = errorStream.Close()
+
+	remoteErr := make(chan error, 1)
+	go func() {
+		message, err := io.ReadAll(errorStream)
+		switch {
+		case err != nil:
+			remoteErr <- fmt.Errorf("%w: failed reading error stream", err)
+		case len(message) > 0:
+			c.log.Error("port forward failed", "namespace", namespace, "pod", pod.Name, "port", port, "err", string(message))
+			remoteErr <- cluster.ErrExecPortForwardFailed
+		}
+		close(remoteErr)
+	}()
+
+	headers.Set(corev1.StreamType, corev1.StreamTypeData)
+	dataStream, err := conn.CreateStream(headers)
+	if err != nil {
+		return fmt.Errorf("%w: failed creating data stream", err)
+	}
+
+	localDone := make(chan error, 1)
+	go func() {
+		// let the pod know nothing more is coming once input is drained
+		_, err := io.Copy(dataStream, input)
+		_ = dataStream.Close()
+		localDone <- err
+	}()
+
+	remoteDone := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(output, dataStream)
+		remoteDone <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err = <-localDone:
+		if err != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err = <-remoteDone:
+		}
+	case err = <-remoteDone:
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return <-remoteErr
+}
@@ -0,0 +1,54 @@
+package kube
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/akash-network/provider/cluster"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+func TestServicePortExposed(t *testing.T) {
+	service := &crd.ManifestService{
+		Expose: []crd.ManifestServiceExpose{
+			{Port: 5432, Proto: "TCP"},
+			{Port: 53, Proto: "UDP"},
+			{Port: 8080, Global: true},
+		},
+	}
+
+	require.True(t, servicePortExposed(service, 5432))
+	require.True(t, servicePortExposed(service, 8080))
+	require.False(t, servicePortExposed(service, 53))
+	require.False(t, servicePortExposed(service, 22))
+}
+
+func TestClientPortForward(t *testing.T) {
+	withExecTestScaffold(t, nil, func(s *execScaffold) {
+		err := s.client.PortForward(s.ctx, s.leaseID, execTestServiceName, 0, 80, &bytes.Buffer{}, &bytes.Buffer{})
+		// The arguments are valid, so we expect the code to try & establish a SPDY connection
+		// which has been hijacked & blocked by the scaffold
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "SPDY connections blocked")
+	})
+}
+
+func TestClientPortForwardPortNotExposed(t *testing.T) {
+	withExecTestScaffold(t, nil, func(s *execScaffold) {
+		err := s.client.PortForward(s.ctx, s.leaseID, execTestServiceName, 0, 22, &bytes.Buffer{}, &bytes.Buffer{})
+		require.ErrorIs(t, err, cluster.ErrExecPortNotExposed)
+	})
+}
+
+func TestClientPortForwardPodNotReady(t *testing.T) {
+	withExecTestScaffold(t, func(pod *corev1.Pod) error {
+		pod.Status.Conditions = nil
+		return nil
+	}, func(s *execScaffold) {
+		err := s.client.PortForward(s.ctx, s.leaseID, execTestServiceName, 0, 80, &bytes.Buffer{}, &bytes.Buffer{})
+		require.ErrorIs(t, err, cluster.ErrExecServiceNotRunning)
+	})
+}
@@ -0,0 +1,186 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	kubeErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+
+	"github.com/akash-network/provider/cluster"
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+)
+
+// restartedAtAnnotation is the pod template annotation kubectl rollout restart uses.
+// Builder keeps unknown pod annotations on update, and statefulset recreated by volume expansion
+// gets it carried over (see applyStatefulSet), so redeploying the manifest does not restart service again
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+func (c *client) RestartService(ctx context.Context, lid mtypes.LeaseID, service string, podIndex *uint) error {
+	if err := c.leaseExists(ctx, lid); err != nil {
+		return err
+	}
+
+	ns := builder.LidNS(lid)
+
+	var regarding corev1.ObjectReference
+	var note string
+	var err error
+
+	if podIndex == nil {
+		regarding, err = c.rolloutRestart(ctx, ns, service)
+		note = fmt.Sprintf("service %q restarted by tenant", service)
+	} else {
+		regarding, err = c.restartPod(ctx, ns, service, *podIndex)
+		note = fmt.Sprintf("replica %d of service %q restarted by tenant", *podIndex, service)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	c.log.Info("restarted service", "lease", lid, "service", service, "object", regarding.Name)
+
+	// tenant sees the restart in lease events
+	_, err = wrapKubeCall("events-create", func() (*eventsv1.Event, error) {
+		return c.kc.EventsV1().Events(ns).Create(ctx, &eventsv1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "akash-restart-",
+				Namespace:    ns,
+				Labels: map[string]string{
+					builder.AkashManifestServiceLabelName: service,
+				},
+			},
+			EventTime:           metav1.NowMicro(),
+			ReportingController: "akash.network/provider",
+			ReportingInstance:   "akash-provider",
+			Action:              "Restart",
+			Reason:              "Restarted",
+			Regarding:           regarding,
+			Note:                note,
+			Type:                corev1.EventTypeNormal,
+		}, metav1.CreateOptions{})
+	})
+	if err != nil {
+		c.log.Error("recording restart event", "err", err, "lease", lid)
+	}
+
+	return nil
+}
+
+// rolloutRestart bumps restart annotation of pod template of the service, which makes
+// deployment or statefulset controller replace all pods the same way it rolls out an update.
+// Annotation is patched alone, so restart neither conflicts with nor overwrites concurrent manifest updates
+func (c *client) rolloutRestart(ctx context.Context, ns string, service string) (corev1.ObjectReference, error) {
+	patch, err := restartedAtPatch(time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return corev1.ObjectReference{}, err
+	}
+
+	_, err = wrapKubeCall("deployments-patch", func() (*appsv1.Deployment, error) {
+		return c.kc.AppsV1().Deployments(ns).Patch(ctx, service, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	})
+
+	switch {
+	case err == nil:
+		return corev1.ObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Namespace:  ns,
+			Name:       service,
+		}, nil
+	case !kubeErrors.IsNotFound(err):
+		return corev1.ObjectReference{}, err
+	}
+
+	_, err = wrapKubeCall("statefulsets-patch", func() (*appsv1.StatefulSet, error) {
+		return c.kc.AppsV1().StatefulSets(ns).Patch(ctx, service, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	})
+	if kubeErrors.IsNotFound(err) {
+		return corev1.ObjectReference{}, fmt.Errorf("%w: service %q", kubeclienterrors.ErrNoServiceForLease, service)
+	}
+	if err != nil {
+		return corev1.ObjectReference{}, err
+	}
+
+	return corev1.ObjectReference{
+		APIVersion: "apps/v1",
+		Kind:       "StatefulSet",
+		Namespace:  ns,
+		Name:       service,
+	}, nil
+}
+
+// restartPod deletes replica of the service, so its controller replaces it with a new one.
+// Unlike exec, replica does not have to be ready, restarting wedged replica is the point
+func (c *client) restartPod(ctx context.Context, ns string, service string, podIndex uint) (corev1.ObjectReference, error) {
+	pods, err := wrapKubeCall("pods-list", func() (*corev1.PodList, error) {
+		return c.kc.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", builder.AkashManifestServiceLabelName, service),
+		})
+	})
+	if err != nil {
+		return corev1.ObjectReference{}, err
+	}
+
+	if len(pods.Items) == 0 {
+		return corev1.ObjectReference{}, fmt.Errorf("%w: service %q", kubeclienterrors.ErrNoServiceForLease, service)
+	}
+
+	if podIndex >= uint(len(pods.Items)) {
+		return corev1.ObjectReference{}, fmt.Errorf("%w: valid range is [0, %d]", cluster.ErrRestartPodIndexOutOfRange, len(pods.Items)-1)
+	}
+
+	items := sortablePods(pods.Items)
+	sort.Sort(items)
+	pod := items[podIndex]
+
+	_, err = wrapKubeCall("pods-delete", func() (interface{}, error) {
+		return nil, c.kc.CoreV1().Pods(ns).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+	})
+	if err != nil {
+		return corev1.ObjectReference{}, err
+	}
+
+	return corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Namespace:  ns,
+		Name:       pod.Name,
+		UID:        pod.UID,
+	}, nil
+}
+
+func restartedAtPatch(restartedAt string) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						restartedAtAnnotation: restartedAt,
+					},
+				},
+			},
+		},
+	})
+}
+
+func withRestartedAt(annotations map[string]string, restartedAt string) map[string]string {
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[restartedAtAnnotation] = restartedAt
+
+	return annotations
+}
@@ -0,0 +1,121 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	kubeErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/akash-network/node/testutil"
+
+	"github.com/akash-network/provider/cluster"
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	akashclient_fake "github.com/akash-network/provider/pkg/client/clientset/versioned/fake"
+)
+
+func TestRestartService(t *testing.T) {
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+
+	pod := func(name string, service string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: serviceLabels(lid, service)},
+			// wedged replica is not ready, it still has to be restartable
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	kc := kubefake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: ns, Labels: serviceLabels(lid, "web")},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"keep": "me"}},
+				},
+			},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: ns, Labels: serviceLabels(lid, "db")},
+		},
+		pod("web-b", "web"),
+		pod("web-a", "web"),
+		pod("db-0", "db"),
+	)
+
+	// fake tracker does not generate names, do it the way api server would
+	kc.PrependReactor("create", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8stesting.CreateAction).GetObject().(*eventsv1.Event)
+		obj.Name = obj.GenerateName + rand.String(5)
+		return false, nil, nil
+	})
+
+	// deployment manager updating the service concurrently makes read-modify-write of it conflict,
+	// restart must not depend on it
+	conflict := func(action k8stesting.Action) (bool, runtime.Object, error) {
+		res := action.GetResource()
+		return true, nil, kubeErrors.NewConflict(res.GroupResource(), "", errors.New("object has been modified")) // nolint: goerr113
+	}
+	kc.PrependReactor("update", "deployments", conflict)
+	kc.PrependReactor("update", "statefulsets", conflict)
+
+	c := clientForTest(t, kc, akashclient_fake.NewSimpleClientset()).(*client)
+	ctx := context.Background()
+
+	require.ErrorIs(t, c.RestartService(ctx, testutil.LeaseID(t), "web", nil), kubeclienterrors.ErrLeaseNotFound)
+	require.ErrorIs(t, c.RestartService(ctx, lid, "cache", nil), kubeclienterrors.ErrNoServiceForLease)
+
+	require.NoError(t, c.RestartService(ctx, lid, "web", nil))
+
+	deployment, err := kc.AppsV1().Deployments(ns).Get(ctx, "web", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, deployment.Spec.Template.Annotations[restartedAtAnnotation])
+	require.Equal(t, "me", deployment.Spec.Template.Annotations["keep"])
+
+	require.NoError(t, c.RestartService(ctx, lid, "db", nil))
+
+	sset, err := kc.AppsV1().StatefulSets(ns).Get(ctx, "db", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, sset.Spec.Template.Annotations[restartedAtAnnotation])
+
+	podIndex := uint(2)
+	require.ErrorIs(t, c.RestartService(ctx, lid, "web", &podIndex), cluster.ErrRestartPodIndexOutOfRange)
+
+	// replicas are ordered by name
+	podIndex = 1
+	require.NoError(t, c.RestartService(ctx, lid, "web", &podIndex))
+
+	_, err = kc.CoreV1().Pods(ns).Get(ctx, "web-b", metav1.GetOptions{})
+	require.True(t, kubeErrors.IsNotFound(err))
+
+	_, err = kc.CoreV1().Pods(ns).Get(ctx, "web-a", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	events, err := kc.EventsV1().Events(ns).List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, events.Items, 3)
+
+	kinds := make(map[string]string)
+	for _, evt := range events.Items {
+		require.Equal(t, "Restarted", evt.Reason)
+		require.Equal(t, corev1.EventTypeNormal, evt.Type)
+		kinds[evt.Regarding.Kind] = evt.Regarding.Name
+	}
+
+	require.Equal(t, map[string]string{
+		"Deployment":  "web",
+		"StatefulSet": "db",
+		"Pod":         "web-b",
+	}, kinds)
+}
@@ -2,6 +2,8 @@ package kube
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
@@ -13,7 +15,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
 
 	manifest "github.com/akash-network/akash-api/go/manifest/v2beta2"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
@@ -22,6 +26,7 @@ import (
 
 	"github.com/akash-network/provider/cluster/kube/builder"
 	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 	akashclient "github.com/akash-network/provider/pkg/client/clientset/versioned"
 	akashclient_fake "github.com/akash-network/provider/pkg/client/clientset/versioned/fake"
@@ -749,3 +754,86 @@ func TestServiceStatusWithoutIngress(t *testing.T) {
 	require.NotNil(t, status)
 	require.Len(t, status.URIs, 0)
 }
+
+func TestLeaseLogsReplica(t *testing.T) {
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+
+	logPod := func(name string, service string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+				Labels:    map[string]string{builder.AkashManifestServiceLabelName: service},
+			},
+		}
+	}
+
+	kc := kubefake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		logPod("web-b", "web"),
+		logPod("web-a", "web"),
+		logPod("db-a", "db"),
+	)
+
+	c := clientForTest(t, kc, akashclient_fake.NewSimpleClientset())
+
+	logNames := func(opts ctypes.LeaseLogsOptions) []string {
+		logs, err := c.LeaseLogs(context.Background(), lid, opts)
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(logs))
+		for _, lg := range logs {
+			names = append(names, lg.Name)
+			require.NoError(t, lg.Stream.Close())
+		}
+		return names
+	}
+
+	require.ElementsMatch(t, []string{"web-a", "web-b", "db-a"}, logNames(ctypes.LeaseLogsOptions{}))
+
+	replica := uint(0)
+	require.Equal(t, []string{"db-a", "web-a"}, logNames(ctypes.LeaseLogsOptions{Replica: &replica}))
+
+	// services without such replica are skipped
+	replica = 1
+	require.Equal(t, []string{"web-b"}, logNames(ctypes.LeaseLogsOptions{Replica: &replica}))
+
+	_, err := c.LeaseLogs(context.Background(), testutil.LeaseID(t), ctypes.LeaseLogsOptions{})
+	require.ErrorIs(t, err, kubeclienterrors.ErrLeaseNotFound)
+}
+
+func TestReapplyNetworkPolicies(t *testing.T) {
+	failed := testutil.LeaseID(t)
+	applied := testutil.LeaseID(t)
+
+	ac := akashclient_fake.NewSimpleClientset()
+	for i, lid := range []mtypes.LeaseID{failed, applied} {
+		m, err := crd.NewManifest(testKubeClientNs, lid, &manifest.Group{Name: "somename"}, crd.ClusterSettings{})
+		require.NoError(t, err)
+		m.Name = fmt.Sprintf("manifest-%d", i)
+
+		_, err = ac.AkashV2beta2().Manifests(testKubeClientNs).Create(context.Background(), m, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	kc := kubefake.NewSimpleClientset()
+	kc.PrependReactor("create", "networkpolicies", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == builder.LidNS(failed) {
+			return true, nil, kubeErrors.NewInternalError(errors.New("boom")) // nolint: goerr113
+		}
+		return false, nil, nil
+	})
+
+	settings := builder.NewDefaultSettings()
+	settings.NetworkPoliciesEnabled = true
+	ctx := context.WithValue(context.Background(), builder.SettingsKey, settings)
+
+	// failure of one lease does not prevent others from being updated
+	err := clientForTest(t, kc, ac).(*client).ReapplyNetworkPolicies(ctx)
+	require.Error(t, err)
+
+	policies, err := kc.NetworkingV1().NetworkPolicies(builder.LidNS(applied)).List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, policies.Items)
+}
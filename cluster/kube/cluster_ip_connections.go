@@ -55,7 +55,7 @@ func (c *client) PurgeDeclaredIP(ctx context.Context, leaseID mtypes.LeaseID, se
 	_, _ = fmt.Fprintf(labelSelector, ",%s=%s", protoLabel, proto.ToString())
 	_, _ = fmt.Fprintf(labelSelector, ",%s=%d", externalPortLabel, externalPort)
 	return c.ac.AkashV2beta2().ProviderLeasedIPs(c.ns).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("%s=true", builder.AkashManagedLabelName),
+		LabelSelector: labelSelector.String(),
 	})
 }
 
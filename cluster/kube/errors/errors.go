@@ -15,4 +15,7 @@ var (
 	ErrInvalidHostnameConnection = fmt.Errorf("%w: invalid hostname connection", ErrKubeClient)
 	ErrNotConfiguredWithSettings = fmt.Errorf("%w: not configured with settings in the context passed to function", ErrKubeClient)
 	ErrAlreadyExists             = fmt.Errorf("%w: resource already exists", ErrKubeClient)
+	ErrNoLastGoodManifest        = fmt.Errorf("%w: no last good manifest to roll back to", ErrKubeClient)
+	ErrVolumeShrink              = fmt.Errorf("%w: persistent volume cannot be shrunk", ErrKubeClient)
+	ErrVolumeExpansionNotAllowed = fmt.Errorf("%w: storage class does not allow volume expansion", ErrKubeClient)
 )
@@ -78,6 +78,13 @@ func (inv *inventory) tryAdjust(node string, res *types.ResourceUnits) (*crd.Sch
 			return nil, false, false
 		}
 
+		if attrs.Class == ctypes.StorageClassRAM {
+			if !nd.tryAdjustMemory(&types.Memory{Quantity: storage.Quantity}) {
+				return nil, false, true
+			}
+			continue
+		}
+
 		if !attrs.Persistent {
 			if !nd.tryAdjustEphemeralStorage(&res.Storage[i]) {
 				return nil, false, true
@@ -113,6 +120,27 @@ func (inv *inventory) tryAdjust(node string, res *types.ResourceUnits) (*crd.Sch
 	return sparams, true, true
 }
 
+// AdjustStorage reserves additional space of persistent storage classes.
+// Inventory is left unchanged if any of the classes cannot satisfy request
+func (inv *inventory) AdjustStorage(requests map[string]types.ResourceValue) error {
+	storageClasses := inv.storageClasses.dup()
+
+	for class, val := range requests {
+		cstorage, exists := storageClasses[class]
+		if !exists {
+			return fmt.Errorf("%w: unknown storage class %q", ctypes.ErrInsufficientCapacity, class)
+		}
+
+		if !cstorage.subNLZ(val) {
+			return fmt.Errorf("%w: storage class %q", ctypes.ErrInsufficientCapacity, class)
+		}
+	}
+
+	inv.storageClasses = storageClasses
+
+	return nil
+}
+
 func (inv *inventory) Adjust(reservation ctypes.ReservationGroup, opts ...ctypes.InventoryOption) error {
 	cfg := &ctypes.InventoryOptions{}
 	for _, opt := range opts {
@@ -0,0 +1,433 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubeErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+
+	"github.com/akash-network/provider/cluster"
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+const (
+	volumeSnapshotGroup      = "snapshot.storage.k8s.io"
+	volumeSnapshotKind       = "VolumeSnapshot"
+	volumeSnapshotTimeFormat = "20060102150405"
+
+	snapshotRestoreInterval = time.Second
+	snapshotRestoreTimeout  = 2 * time.Minute
+	snapshotScaleTimeout    = 30 * time.Second
+)
+
+var volumeSnapshotGVR = schema.GroupVersionResource{
+	Group:    volumeSnapshotGroup,
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+// volumeSnapshot mirrors fields of snapshot.storage.k8s.io/v1 VolumeSnapshot used by provider.
+// Snapshot CRDs are installed by CSI snapshotter, so objects are accessed through dynamic client
+type volumeSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   volumeSnapshotSpec    `json:"spec"`
+	Status *volumeSnapshotStatus `json:"status,omitempty"`
+}
+
+type volumeSnapshotSpec struct {
+	Source                  volumeSnapshotSource `json:"source"`
+	VolumeSnapshotClassName *string              `json:"volumeSnapshotClassName,omitempty"`
+}
+
+type volumeSnapshotSource struct {
+	PersistentVolumeClaimName *string `json:"persistentVolumeClaimName,omitempty"`
+}
+
+type volumeSnapshotStatus struct {
+	CreationTime *metav1.Time         `json:"creationTime,omitempty"`
+	ReadyToUse   *bool                `json:"readyToUse,omitempty"`
+	RestoreSize  *resource.Quantity   `json:"restoreSize,omitempty"`
+	Error        *volumeSnapshotError `json:"error,omitempty"`
+}
+
+type volumeSnapshotError struct {
+	Message *string `json:"message,omitempty"`
+}
+
+func (vs *volumeSnapshot) ready() bool {
+	return vs.Status != nil && vs.Status.ReadyToUse != nil && *vs.Status.ReadyToUse
+}
+
+func (vs *volumeSnapshot) status() ctypes.VolumeSnapshot {
+	res := ctypes.VolumeSnapshot{
+		Name:      vs.Labels[builder.AkashVolumeSnapshotLabelName],
+		Service:   vs.Labels[builder.AkashManifestServiceLabelName],
+		Ready:     vs.ready(),
+		CreatedAt: vs.CreationTimestamp.Time,
+	}
+
+	if vs.Spec.Source.PersistentVolumeClaimName != nil {
+		res.Volume = *vs.Spec.Source.PersistentVolumeClaimName
+	}
+
+	if vs.Status != nil {
+		if vs.Status.CreationTime != nil {
+			res.CreatedAt = vs.Status.CreationTime.Time
+		}
+
+		if vs.Status.RestoreSize != nil {
+			res.Size = vs.Status.RestoreSize.String()
+		}
+
+		if vs.Status.Error != nil && vs.Status.Error.Message != nil {
+			res.Error = *vs.Status.Error.Message
+		}
+	}
+
+	return res
+}
+
+func snapshotSelector(service string, name string) string {
+	selector := fmt.Sprintf("%s=true,%s", builder.AkashManagedLabelName, builder.AkashVolumeSnapshotLabelName)
+	if name != "" {
+		selector = fmt.Sprintf("%s=true,%s=%s", builder.AkashManagedLabelName, builder.AkashVolumeSnapshotLabelName, name)
+	}
+
+	if service != "" {
+		selector += fmt.Sprintf(",%s=%s", builder.AkashManifestServiceLabelName, service)
+	}
+
+	return selector
+}
+
+func (c *client) listVolumeSnapshots(ctx context.Context, ns string, selector string) ([]volumeSnapshot, error) {
+	list, err := wrapKubeCall("volume-snapshots-list", func() (*unstructured.UnstructuredList, error) {
+		return c.dc.Resource(volumeSnapshotGVR).Namespace(ns).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]volumeSnapshot, 0, len(list.Items))
+
+	for _, item := range list.Items {
+		var vs volumeSnapshot
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &vs); err != nil {
+			return nil, err
+		}
+
+		res = append(res, vs)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if !res[i].CreationTimestamp.Equal(&res[j].CreationTimestamp) {
+			return res[i].CreationTimestamp.Before(&res[j].CreationTimestamp)
+		}
+
+		return res[i].Name < res[j].Name
+	})
+
+	return res, nil
+}
+
+// pruneSnapshots deletes snapshots of the lease namespace older than retention period
+func (c *client) pruneSnapshots(ctx context.Context, ns string, retention time.Duration, now time.Time) error {
+	if retention == 0 {
+		return nil
+	}
+
+	snapshots, err := c.listVolumeSnapshots(ctx, ns, snapshotSelector("", ""))
+	if err != nil {
+		return err
+	}
+
+	for _, vs := range snapshots {
+		if now.Sub(vs.CreationTimestamp.Time) < retention {
+			continue
+		}
+
+		_, err := wrapKubeCall("volume-snapshots-delete", func() (interface{}, error) {
+			return nil, c.dc.Resource(volumeSnapshotGVR).Namespace(ns).Delete(ctx, vs.Name, metav1.DeleteOptions{})
+		})
+		if err != nil && !kubeErrors.IsNotFound(err) {
+			return err
+		}
+
+		c.log.Info("deleted expired volume snapshot", "ns", ns, "snapshot", vs.Name)
+	}
+
+	return nil
+}
+
+func (c *client) CreateSnapshot(ctx context.Context, lid mtypes.LeaseID, service string) ([]ctypes.VolumeSnapshot, error) {
+	settingsI := ctx.Value(builder.SettingsKey)
+	if nil == settingsI {
+		return nil, kubeclienterrors.ErrNotConfiguredWithSettings
+	}
+	settings := settingsI.(builder.Settings)
+
+	if settings.Snapshots.Quota == 0 {
+		return nil, cluster.ErrSnapshotDisabled
+	}
+
+	ns := builder.LidNS(lid)
+	now := time.Now()
+
+	if err := c.pruneSnapshots(ctx, ns, settings.Snapshots.Retention, now); err != nil {
+		return nil, err
+	}
+
+	existing, err := c.listVolumeSnapshots(ctx, ns, snapshotSelector("", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for _, vs := range existing {
+		names[vs.Labels[builder.AkashVolumeSnapshotLabelName]] = true
+	}
+
+	if uint(len(names)) >= settings.Snapshots.Quota {
+		return nil, fmt.Errorf("%w: lease has %d snapshots", cluster.ErrSnapshotQuotaExceeded, len(names))
+	}
+
+	claims, err := wrapKubeCall("persistent-volume-claims-list", func() (*corev1.PersistentVolumeClaimList, error) {
+		return c.kc.CoreV1().PersistentVolumeClaims(ns).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=true,%s=%s", builder.AkashManagedLabelName, builder.AkashManifestServiceLabelName, service),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(claims.Items) == 0 {
+		return nil, cluster.ErrSnapshotNoVolumes
+	}
+
+	sort.Slice(claims.Items, func(i, j int) bool {
+		return claims.Items[i].Name < claims.Items[j].Name
+	})
+
+	name := fmt.Sprintf("%s-%s", service, now.UTC().Format(volumeSnapshotTimeFormat))
+	if names[name] {
+		return nil, fmt.Errorf("%w: snapshot %q", kubeclienterrors.ErrAlreadyExists, name)
+	}
+
+	res := make([]ctypes.VolumeSnapshot, 0, len(claims.Items))
+
+	for i := range claims.Items {
+		claimName := claims.Items[i].Name
+
+		vs := &volumeSnapshot{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: volumeSnapshotGVR.GroupVersion().String(),
+				Kind:       volumeSnapshotKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s", name, claimName),
+				Namespace: ns,
+				Labels: builder.AppendLeaseLabels(lid, map[string]string{
+					builder.AkashManagedLabelName:         "true",
+					builder.AkashManifestServiceLabelName: service,
+					builder.AkashVolumeSnapshotLabelName:  name,
+				}),
+			},
+			Spec: volumeSnapshotSpec{
+				Source: volumeSnapshotSource{
+					PersistentVolumeClaimName: &claimName,
+				},
+			},
+		}
+
+		if settings.Snapshots.Class != "" {
+			vs.Spec.VolumeSnapshotClassName = &settings.Snapshots.Class
+		}
+
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(vs)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = wrapKubeCall("volume-snapshots-create", func() (*unstructured.Unstructured, error) {
+			return c.dc.Resource(volumeSnapshotGVR).Namespace(ns).Create(ctx, &unstructured.Unstructured{Object: obj}, metav1.CreateOptions{})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		vs.CreationTimestamp = metav1.NewTime(now)
+		res = append(res, vs.status())
+	}
+
+	c.log.Info("created volume snapshot", "lease", lid, "service", service, "snapshot", name, "volumes", len(res))
+
+	return res, nil
+}
+
+func (c *client) ListSnapshots(ctx context.Context, lid mtypes.LeaseID, service string) ([]ctypes.VolumeSnapshot, error) {
+	snapshots, err := c.listVolumeSnapshots(ctx, builder.LidNS(lid), snapshotSelector(service, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]ctypes.VolumeSnapshot, 0, len(snapshots))
+	for i := range snapshots {
+		res = append(res, snapshots[i].status())
+	}
+
+	return res, nil
+}
+
+// RestoreSnapshot stops the service, replaces its volume claims with claims populated from the snapshot
+// and starts the service again. Data written after snapshot has been taken is lost
+func (c *client) RestoreSnapshot(ctx context.Context, lid mtypes.LeaseID, service string, name string) error {
+	ns := builder.LidNS(lid)
+
+	snapshots, err := c.listVolumeSnapshots(ctx, ns, snapshotSelector(service, name))
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) == 0 {
+		return fmt.Errorf("%w: %q", cluster.ErrSnapshotNotFound, name)
+	}
+
+	for i := range snapshots {
+		if !snapshots[i].ready() {
+			return fmt.Errorf("%w: %q", cluster.ErrSnapshotNotReady, snapshots[i].Name)
+		}
+	}
+
+	replicas, err := c.scaleStatefulSet(ctx, ns, service, 0)
+	if kubeErrors.IsNotFound(err) {
+		return fmt.Errorf("%w: service %q", kubeclienterrors.ErrNoServiceForLease, service)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.log.Info("restoring volume snapshot", "lease", lid, "service", service, "snapshot", name)
+
+	// once service is stopped restore is carried out even if caller goes away,
+	// otherwise claims could be left deleted and service stopped
+	rctx, rcancel := context.WithTimeout(context.Background(), time.Duration(len(snapshots))*snapshotRestoreTimeout)
+	defer rcancel()
+
+	for i := range snapshots {
+		if err = c.restoreClaim(rctx, ns, &snapshots[i]); err != nil {
+			break
+		}
+	}
+
+	// service is started again even if restore failed, so it is not left stopped
+	sctx, scancel := context.WithTimeout(context.Background(), snapshotScaleTimeout)
+	defer scancel()
+
+	if _, serr := c.scaleStatefulSet(sctx, ns, service, replicas); serr != nil && err == nil {
+		err = serr
+	}
+
+	return err
+}
+
+// scaleStatefulSet sets replicas of the statefulset and returns previous replica count
+func (c *client) scaleStatefulSet(ctx context.Context, ns string, name string, replicas int32) (int32, error) {
+	sset, err := wrapKubeCall("statefulsets-get", func() (*appsv1.StatefulSet, error) {
+		return c.kc.AppsV1().StatefulSets(ns).Get(ctx, name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	prev := int32(1)
+	if sset.Spec.Replicas != nil {
+		prev = *sset.Spec.Replicas
+	}
+
+	sset.Spec.Replicas = &replicas
+
+	_, err = wrapKubeCall("statefulsets-update", func() (interface{}, error) {
+		return c.kc.AppsV1().StatefulSets(ns).Update(ctx, sset, metav1.UpdateOptions{})
+	})
+
+	return prev, err
+}
+
+// restoreClaim recreates the volume claim snapshot was taken from with the snapshot as data source
+func (c *client) restoreClaim(ctx context.Context, ns string, vs *volumeSnapshot) error {
+	if vs.Spec.Source.PersistentVolumeClaimName == nil {
+		return fmt.Errorf("%w: %q has no source volume", cluster.ErrSnapshotNotFound, vs.Name)
+	}
+
+	claimName := *vs.Spec.Source.PersistentVolumeClaimName
+
+	claim, err := c.kc.CoreV1().PersistentVolumeClaims(ns).Get(ctx, claimName, metav1.GetOptions{})
+	if err != nil {
+		if kubeErrors.IsNotFound(err) {
+			return fmt.Errorf("%w: volume %q of snapshot %q no longer exists", cluster.ErrSnapshotNotFound, claimName, vs.Name)
+		}
+		return err
+	}
+
+	group := volumeSnapshotGroup
+
+	restored := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   claim.Name,
+			Labels: claim.Labels,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      claim.Spec.AccessModes,
+			Resources:        *claim.Spec.Resources.DeepCopy(),
+			StorageClassName: claim.Spec.StorageClassName,
+			VolumeMode:       claim.Spec.VolumeMode,
+			DataSource: &corev1.TypedLocalObjectReference{
+				APIGroup: &group,
+				Kind:     volumeSnapshotKind,
+				Name:     vs.Name,
+			},
+		},
+	}
+
+	_, err = wrapKubeCall("persistent-volume-claims-delete", func() (interface{}, error) {
+		return nil, c.kc.CoreV1().PersistentVolumeClaims(ns).Delete(ctx, claim.Name, metav1.DeleteOptions{})
+	})
+	if err != nil && !kubeErrors.IsNotFound(err) {
+		return err
+	}
+
+	// claim is removed once pods using it are gone
+	err = wait.PollImmediateWithContext(ctx, snapshotRestoreInterval, snapshotRestoreTimeout, func(ctx context.Context) (bool, error) {
+		_, err := c.kc.CoreV1().PersistentVolumeClaims(ns).Get(ctx, claim.Name, metav1.GetOptions{})
+		if kubeErrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = wrapKubeCall("persistent-volume-claims-create", func() (interface{}, error) {
+		return c.kc.CoreV1().PersistentVolumeClaims(ns).Create(ctx, restored, metav1.CreateOptions{})
+	})
+
+	return err
+}
@@ -0,0 +1,211 @@
+package kube
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/akash-network/node/testutil"
+
+	"github.com/akash-network/provider/cluster"
+	"github.com/akash-network/provider/cluster/kube/builder"
+	akashclient_fake "github.com/akash-network/provider/pkg/client/clientset/versioned/fake"
+)
+
+func TestVolumeSnapshots(t *testing.T) {
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+
+	claim := claimTemplate("db-data-db-0", 1024)
+	claim.Namespace = ns
+	claim.Labels = serviceLabels(lid, "db")
+
+	replicas := int32(2)
+
+	kc := kubefake.NewSimpleClientset(
+		&claim,
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: ns, Labels: serviceLabels(lid, "db")},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		},
+	)
+
+	dc := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		volumeSnapshotGVR: "VolumeSnapshotList",
+	})
+
+	// fake tracker does not set creation timestamp, do it the way api server would
+	dc.PrependReactor("create", "volumesnapshots", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		obj.SetCreationTimestamp(metav1.Now())
+		return false, nil, nil
+	})
+
+	c := clientForTest(t, kc, akashclient_fake.NewSimpleClientset()).(*client)
+	c.dc = dc
+
+	settings := builder.NewDefaultSettings()
+	ctx := context.WithValue(context.Background(), builder.SettingsKey, settings)
+
+	_, err := c.CreateSnapshot(ctx, lid, "db")
+	require.ErrorIs(t, err, cluster.ErrSnapshotDisabled)
+
+	settings.Snapshots = builder.SnapshotSettings{Class: "csi-rbd", Quota: 1, Retention: time.Hour}
+	ctx = context.WithValue(context.Background(), builder.SettingsKey, settings)
+
+	_, err = c.CreateSnapshot(ctx, lid, "web")
+	require.ErrorIs(t, err, cluster.ErrSnapshotNoVolumes)
+
+	created, err := c.CreateSnapshot(ctx, lid, "db")
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	require.Equal(t, "db", created[0].Service)
+	require.Equal(t, "db-data-db-0", created[0].Volume)
+	require.False(t, created[0].Ready)
+
+	_, err = c.CreateSnapshot(ctx, lid, "db")
+	require.ErrorIs(t, err, cluster.ErrSnapshotQuotaExceeded)
+
+	listed, err := c.ListSnapshots(ctx, lid, "")
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Equal(t, created[0].Name, listed[0].Name)
+
+	listed, err = c.ListSnapshots(ctx, lid, "web")
+	require.NoError(t, err)
+	require.Empty(t, listed)
+
+	name := created[0].Name
+	vsName := name + "-db-data-db-0"
+
+	obj, err := dc.Resource(volumeSnapshotGVR).Namespace(ns).Get(ctx, vsName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	class, _, _ := unstructured.NestedString(obj.Object, "spec", "volumeSnapshotClassName")
+	require.Equal(t, "csi-rbd", class)
+
+	require.ErrorIs(t, c.RestoreSnapshot(ctx, lid, "db", name), cluster.ErrSnapshotNotReady)
+	require.ErrorIs(t, c.RestoreSnapshot(ctx, lid, "db", "db-1"), cluster.ErrSnapshotNotFound)
+
+	require.NoError(t, unstructured.SetNestedField(obj.Object, true, "status", "readyToUse"))
+	_, err = dc.Resource(volumeSnapshotGVR).Namespace(ns).Update(ctx, obj, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, c.RestoreSnapshot(ctx, lid, "db", name))
+
+	restored, err := kc.CoreV1().PersistentVolumeClaims(ns).Get(ctx, "db-data-db-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, restored.Spec.DataSource)
+	require.Equal(t, volumeSnapshotKind, restored.Spec.DataSource.Kind)
+	require.Equal(t, vsName, restored.Spec.DataSource.Name)
+	require.Equal(t, claim.Labels, restored.Labels)
+
+	// service is started again with previous replica count
+	sset, err := kc.AppsV1().StatefulSets(ns).Get(ctx, "db", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, replicas, *sset.Spec.Replicas)
+
+	// expired snapshots are pruned
+	require.NoError(t, c.pruneSnapshots(ctx, ns, time.Hour, time.Now().Add(2*time.Hour)))
+
+	listed, err = c.ListSnapshots(ctx, lid, "")
+	require.NoError(t, err)
+	require.Empty(t, listed)
+}
+
+func TestRestoreSnapshotCallerCanceled(t *testing.T) {
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+
+	claim := claimTemplate("db-data-db-0", 1024)
+	claim.Namespace = ns
+	claim.Labels = serviceLabels(lid, "db")
+
+	replicas := int32(2)
+
+	kc := kubefake.NewSimpleClientset(
+		&claim,
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: ns, Labels: serviceLabels(lid, "db")},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		},
+	)
+
+	dc := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		volumeSnapshotGVR: "VolumeSnapshotList",
+	})
+
+	dc.PrependReactor("create", "volumesnapshots", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		obj.SetCreationTimestamp(metav1.Now())
+		return false, nil, nil
+	})
+
+	c := clientForTest(t, kc, akashclient_fake.NewSimpleClientset()).(*client)
+	c.dc = dc
+
+	settings := builder.NewDefaultSettings()
+	settings.Snapshots = builder.SnapshotSettings{Class: "csi-rbd", Quota: 1, Retention: time.Hour}
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), builder.SettingsKey, settings))
+	defer cancel()
+
+	created, err := c.CreateSnapshot(ctx, lid, "db")
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+
+	vsName := created[0].Name + "-db-data-db-0"
+
+	obj, err := dc.Resource(volumeSnapshotGVR).Namespace(ns).Get(ctx, vsName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NoError(t, unstructured.SetNestedField(obj.Object, true, "status", "readyToUse"))
+	_, err = dc.Resource(volumeSnapshotGVR).Namespace(ns).Update(ctx, obj, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// caller goes away once the original claim is being deleted,
+	// claim itself is removed a bit later as it is still used by pods being stopped
+	deleting := false
+	kc.PrependReactor("delete", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		deleting = true
+		cancel()
+		return true, nil, nil
+	})
+
+	gets := 0
+	kc.PrependReactor("get", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if !deleting {
+			return false, nil, nil
+		}
+
+		if gets++; gets == 2 {
+			deleting = false
+			gvr := corev1.SchemeGroupVersion.WithResource("persistentvolumeclaims")
+			require.NoError(t, kc.Tracker().Delete(gvr, ns, "db-data-db-0"))
+		}
+
+		return false, nil, nil
+	})
+
+	require.NoError(t, c.RestoreSnapshot(ctx, lid, "db", created[0].Name))
+	require.Error(t, ctx.Err())
+
+	restored, err := kc.CoreV1().PersistentVolumeClaims(ns).Get(context.Background(), "db-data-db-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, restored.Spec.DataSource)
+	require.Equal(t, vsName, restored.Spec.DataSource.Name)
+
+	sset, err := kc.AppsV1().StatefulSets(ns).Get(context.Background(), "db", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, replicas, *sset.Spec.Replicas)
+}
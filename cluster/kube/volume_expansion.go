@@ -0,0 +1,298 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/log"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	kubeErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	mani "github.com/akash-network/akash-api/go/manifest/v2beta2"
+	types "github.com/akash-network/akash-api/go/node/types/v1beta3"
+	"github.com/akash-network/node/sdl"
+	metricsutils "github.com/akash-network/node/util/metrics"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+const (
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+	statefulSetDeleteInterval = time.Second
+	statefulSetDeleteTimeout  = 30 * time.Second
+)
+
+// volumeExpansion is the persistent volume of the service which size has been increased by the manifest
+type volumeExpansion struct {
+	// name of the volume claim template
+	name  string
+	class string
+	to    resource.Quantity
+}
+
+// claimExpansion is the volume claim of statefulset replica to be grown to given size
+type claimExpansion struct {
+	claim *corev1.PersistentVolumeClaim
+	size  resource.Quantity
+}
+
+// volumeExpansions compares persistent storage of the service with claim templates of the deployed statefulset.
+// Volumes added to or removed from the service are not expansions and are ignored
+func volumeExpansions(service *mani.Service, templates []corev1.PersistentVolumeClaim) ([]volumeExpansion, error) {
+	current := make(map[string]resource.Quantity, len(templates))
+	for _, tmpl := range templates {
+		current[tmpl.Name] = tmpl.Spec.Resources.Requests[corev1.ResourceStorage]
+	}
+
+	var res []volumeExpansion // nolint:prealloc
+
+	for _, storage := range service.Resources.Storage {
+		attrs, err := ctypes.ParseStorageAttributes(storage.Attributes)
+		if err != nil {
+			return nil, err
+		}
+
+		if !attrs.Persistent {
+			continue
+		}
+
+		// matches claim template name produced by the statefulset builder
+		name := fmt.Sprintf("%s-%s", service.Name, storage.Name)
+
+		from, exists := current[name]
+		if !exists {
+			continue
+		}
+
+		to := *resource.NewQuantity(int64(storage.Quantity.Value()), resource.DecimalSI)
+
+		switch from.Cmp(to) {
+		case 1:
+			return nil, fmt.Errorf("%w: volume %q of service %q requested %s, currently %s",
+				kubeclienterrors.ErrVolumeShrink, storage.Name, service.Name, to.String(), from.String())
+		case -1:
+			res = append(res, volumeExpansion{
+				name:  name,
+				class: attrs.Class,
+				to:    to,
+			})
+		}
+	}
+
+	return res, nil
+}
+
+// volumeExpansionPlan is the set of volume claims of the deployed persistent service to be grown
+type volumeExpansionPlan struct {
+	statefulSet string
+	restartedAt string
+	claims      []claimExpansion
+	// requests is additional space needed, keyed by storage class
+	requests map[string]types.ResourceValue
+}
+
+// StorageExpansion returns additional space of persistent storage classes needed to grow volumes
+// of the deployed lease to sizes requested by the deployment
+func (c *client) StorageExpansion(ctx context.Context, deployment ctypes.IDeployment) (map[string]types.ResourceValue, error) {
+	ns := builder.LidNS(deployment.LeaseID())
+	group := deployment.ManifestGroup()
+
+	requests := make(map[string]types.ResourceValue)
+
+	for i := range group.Services {
+		service := &group.Services[i]
+		if !servicePersistent(service) {
+			continue
+		}
+
+		plan, err := c.planVolumeExpansion(ctx, ns, service)
+		if err != nil {
+			return nil, err
+		}
+
+		if plan == nil {
+			continue
+		}
+
+		for class, val := range plan.requests {
+			addStorageRequest(requests, class, val.Val.Int64())
+		}
+	}
+
+	return requests, nil
+}
+
+// expandVolumes grows volume claims of the persistent service in place.
+// Additional space is expected to be reserved in the inventory beforehand, see StorageExpansion.
+// Claim templates of statefulset are immutable, so statefulset is deleted leaving its pods and claims
+// behind and is recreated by the following apply with new templates, adopting the existing pods.
+// It returns restart annotation of the removed statefulset, which recreated one has to keep
+// for adopted pods to stay at their revision
+func (c *client) expandVolumes(ctx context.Context, ns string, service *mani.Service) (string, error) {
+	plan, err := c.planVolumeExpansion(ctx, ns, service)
+	if err != nil || plan == nil {
+		return "", err
+	}
+
+	if err := expandClaims(ctx, c.kc, c.log, ns, plan.statefulSet, plan.claims); err != nil {
+		return "", err
+	}
+
+	return plan.restartedAt, nil
+}
+
+// planVolumeExpansion returns nil plan if service is not deployed yet or none of its volumes has been grown
+func (c *client) planVolumeExpansion(ctx context.Context, ns string, service *mani.Service) (*volumeExpansionPlan, error) {
+	sset, err := c.kc.AppsV1().StatefulSets(ns).Get(ctx, service.Name, metav1.GetOptions{})
+	metricsutils.IncCounterVecWithLabelValuesFiltered(kubeCallsCounter, "statefulsets-get", err, kubeErrors.IsNotFound)
+	if kubeErrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	expansions, err := volumeExpansions(service, sset.Spec.VolumeClaimTemplates)
+	if err != nil || len(expansions) == 0 {
+		return nil, err
+	}
+
+	for _, expansion := range expansions {
+		if err := c.checkStorageClassExpansion(ctx, expansion.class); err != nil {
+			return nil, err
+		}
+	}
+
+	claims, err := c.kc.CoreV1().PersistentVolumeClaims(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true,%s=%s", builder.AkashManagedLabelName, builder.AkashManifestServiceLabelName, service.Name),
+	})
+	metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "persistent-volume-claims-list", err)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &volumeExpansionPlan{
+		statefulSet: sset.Name,
+		restartedAt: sset.Spec.Template.Annotations[restartedAtAnnotation],
+		requests:    make(map[string]types.ResourceValue),
+	}
+
+	for _, expansion := range expansions {
+		// statefulset names claims as <template>-<statefulset>-<ordinal>
+		prefix := fmt.Sprintf("%s-%s-", expansion.name, sset.Name)
+
+		for i := range claims.Items {
+			claim := &claims.Items[i]
+			if !strings.HasPrefix(claim.Name, prefix) {
+				continue
+			}
+
+			// claim might have been expanded by previous attempt
+			size := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+			if size.Cmp(expansion.to) >= 0 {
+				continue
+			}
+
+			delta := expansion.to.DeepCopy()
+			delta.Sub(size)
+
+			addStorageRequest(plan.requests, expansion.class, delta.Value())
+
+			plan.claims = append(plan.claims, claimExpansion{claim: claim, size: expansion.to})
+		}
+	}
+
+	return plan, nil
+}
+
+func addStorageRequest(requests map[string]types.ResourceValue, class string, val int64) {
+	request := requests[class]
+	if request.Val.IsNil() {
+		request = types.NewResourceValue(0)
+	}
+
+	request.Val = request.Val.AddRaw(val)
+	requests[class] = request
+}
+
+// expandClaims updates requested size of the claims and removes statefulset, leaving its pods and claims behind.
+// It returns once statefulset is gone so it can be created again with new claim templates
+func expandClaims(ctx context.Context, kc kubernetes.Interface, log log.Logger, ns string, name string, pending []claimExpansion) error {
+	for _, item := range pending {
+		item.claim.Spec.Resources.Requests[corev1.ResourceStorage] = item.size
+
+		_, err := kc.CoreV1().PersistentVolumeClaims(ns).Update(ctx, item.claim, metav1.UpdateOptions{})
+		metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "persistent-volume-claims-update", err)
+		if err != nil {
+			return err
+		}
+
+		log.Info("expanding volume", "ns", ns, "claim", item.claim.Name, "size", item.size.String())
+	}
+
+	orphan := metav1.DeletePropagationOrphan
+	err := kc.AppsV1().StatefulSets(ns).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &orphan})
+	metricsutils.IncCounterVecWithLabelValuesFiltered(kubeCallsCounter, "statefulsets-delete", err, kubeErrors.IsNotFound)
+	if err != nil && !kubeErrors.IsNotFound(err) {
+		return err
+	}
+
+	// orphaning dependents is done by garbage collector before statefulset is removed
+	return wait.PollImmediateWithContext(ctx, statefulSetDeleteInterval, statefulSetDeleteTimeout, func(ctx context.Context) (bool, error) {
+		_, err := kc.AppsV1().StatefulSets(ns).Get(ctx, name, metav1.GetOptions{})
+		if kubeErrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, err
+	})
+}
+
+// checkStorageClassExpansion returns error unless kubernetes storage class backing given akash class allows volume expansion
+func (c *client) checkStorageClassExpansion(ctx context.Context, class string) error {
+	var sclass *storagev1.StorageClass
+
+	if class == sdl.StorageClassDefault {
+		// claims of default class have no storage class name set and use cluster default
+		list, err := c.kc.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+		metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "storage-classes-list", err)
+		if err != nil {
+			return err
+		}
+
+		for i := range list.Items {
+			if list.Items[i].Annotations[defaultStorageClassAnnotation] == "true" {
+				sclass = &list.Items[i]
+				break
+			}
+		}
+
+		if sclass == nil {
+			return fmt.Errorf("%w: no default storage class", kubeclienterrors.ErrVolumeExpansionNotAllowed)
+		}
+	} else {
+		var err error
+
+		sclass, err = c.kc.StorageV1().StorageClasses().Get(ctx, class, metav1.GetOptions{})
+		metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "storage-classes-get", err)
+		if err != nil {
+			return err
+		}
+	}
+
+	if sclass.AllowVolumeExpansion == nil || !*sclass.AllowVolumeExpansion {
+		return fmt.Errorf("%w: %q", kubeclienterrors.ErrVolumeExpansionNotAllowed, sclass.Name)
+	}
+
+	return nil
+}
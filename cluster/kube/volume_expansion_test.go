@@ -0,0 +1,260 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	kubeErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	mani "github.com/akash-network/akash-api/go/manifest/v2beta2"
+	types "github.com/akash-network/akash-api/go/node/types/v1beta3"
+	"github.com/akash-network/node/testutil"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	akashclient_fake "github.com/akash-network/provider/pkg/client/clientset/versioned/fake"
+)
+
+func persistentService(size uint64) *mani.Service {
+	return &mani.Service{
+		Name: "db",
+		Resources: types.ResourceUnits{
+			Storage: types.Volumes{
+				{
+					Name:     "data",
+					Quantity: types.NewResourceValue(size),
+					Attributes: types.Attributes{
+						{Key: "persistent", Value: "true"},
+						{Key: "class", Value: "beta2"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func claimTemplate(name string, size int64) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: *resource.NewQuantity(size, resource.DecimalSI),
+				},
+			},
+		},
+	}
+}
+
+func TestVolumeExpansions(t *testing.T) {
+	templates := []corev1.PersistentVolumeClaim{claimTemplate("db-data", 1024)}
+
+	expansions, err := volumeExpansions(persistentService(1024), templates)
+	require.NoError(t, err)
+	require.Empty(t, expansions)
+
+	expansions, err = volumeExpansions(persistentService(2048), templates)
+	require.NoError(t, err)
+	require.Len(t, expansions, 1)
+	require.Equal(t, "db-data", expansions[0].name)
+	require.Equal(t, "beta2", expansions[0].class)
+	require.Equal(t, int64(2048), expansions[0].to.Value())
+
+	_, err = volumeExpansions(persistentService(512), templates)
+	require.ErrorIs(t, err, kubeclienterrors.ErrVolumeShrink)
+}
+
+func TestInventoryAdjustStorage(t *testing.T) {
+	inv := newInventory(clusterStorage{
+		"beta2": &resourcePair{
+			allocatable: *resource.NewQuantity(10000, resource.DecimalSI),
+			allocated:   *resource.NewQuantity(5000, resource.DecimalSI),
+		},
+	}, nil)
+
+	require.NoError(t, inv.AdjustStorage(map[string]types.ResourceValue{"beta2": types.NewResourceValue(4000)}))
+	require.Equal(t, int64(9000), inv.storageClasses["beta2"].allocated.Value())
+
+	err := inv.AdjustStorage(map[string]types.ResourceValue{"beta2": types.NewResourceValue(2000)})
+	require.ErrorIs(t, err, ctypes.ErrInsufficientCapacity)
+	require.Equal(t, int64(9000), inv.storageClasses["beta2"].allocated.Value())
+
+	err = inv.AdjustStorage(map[string]types.ResourceValue{"beta3": types.NewResourceValue(1)})
+	require.ErrorIs(t, err, ctypes.ErrInsufficientCapacity)
+}
+
+func TestExpandVolumes(t *testing.T) {
+	ctx := context.Background()
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+
+	allow := false
+	sclass := &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "beta2"},
+		AllowVolumeExpansion: &allow,
+	}
+
+	kc := kubefake.NewSimpleClientset(
+		sclass,
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: ns, Labels: serviceLabels(lid, "db")},
+			Spec: appsv1.StatefulSetSpec{
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{claimTemplate("db-data", 1024)},
+			},
+		},
+	)
+
+	c := clientForTest(t, kc, akashclient_fake.NewSimpleClientset()).(*client)
+
+	// nothing to do for unchanged volumes
+	_, err := c.expandVolumes(ctx, ns, persistentService(1024))
+	require.NoError(t, err)
+
+	_, err = c.expandVolumes(ctx, ns, persistentService(512))
+	require.ErrorIs(t, err, kubeclienterrors.ErrVolumeShrink)
+
+	_, err = c.expandVolumes(ctx, ns, persistentService(2048))
+	require.ErrorIs(t, err, kubeclienterrors.ErrVolumeExpansionNotAllowed)
+
+	_, err = kc.AppsV1().StatefulSets(ns).Get(ctx, "db", metav1.GetOptions{})
+	require.NoError(t, err)
+}
+
+func TestExpandVolumesKeepsRestartedAt(t *testing.T) {
+	ctx := context.Background()
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+
+	allow := true
+	sclass := &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "beta2"},
+		AllowVolumeExpansion: &allow,
+	}
+
+	claim := claimTemplate("db-data-db-0", 1024)
+	claim.Namespace = ns
+	claim.Labels = serviceLabels(lid, "db")
+
+	kc := kubefake.NewSimpleClientset(
+		sclass,
+		&claim,
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: ns, Labels: serviceLabels(lid, "db")},
+			Spec: appsv1.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{restartedAtAnnotation: "2024-01-02T03:04:05Z"},
+					},
+				},
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{claimTemplate("db-data", 1024)},
+			},
+		},
+	)
+
+	c := clientForTest(t, kc, akashclient_fake.NewSimpleClientset()).(*client)
+
+	// statefulset is recreated from the builder, restart annotation has to survive it
+	restartedAt, err := c.expandVolumes(ctx, ns, persistentService(2048))
+	require.NoError(t, err)
+	require.Equal(t, "2024-01-02T03:04:05Z", restartedAt)
+
+	_, err = kc.AppsV1().StatefulSets(ns).Get(ctx, "db", metav1.GetOptions{})
+	require.True(t, kubeErrors.IsNotFound(err))
+}
+
+func TestExpandClaims(t *testing.T) {
+	ctx := context.Background()
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+
+	claim := claimTemplate("db-data-db-0", 1024)
+	claim.Namespace = ns
+	claim.Labels = serviceLabels(lid, "db")
+
+	kc := kubefake.NewSimpleClientset(
+		&claim,
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: ns, Labels: serviceLabels(lid, "db")}},
+	)
+
+	pending := []claimExpansion{{claim: claim.DeepCopy(), size: *resource.NewQuantity(2048, resource.DecimalSI)}}
+
+	require.NoError(t, expandClaims(ctx, kc, testutil.Logger(t), ns, "db", pending))
+
+	res, err := kc.CoreV1().PersistentVolumeClaims(ns).Get(ctx, "db-data-db-0", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	size := res.Spec.Resources.Requests[corev1.ResourceStorage]
+	require.Equal(t, int64(2048), size.Value())
+
+	_, err = kc.AppsV1().StatefulSets(ns).Get(ctx, "db", metav1.GetOptions{})
+	require.True(t, kubeErrors.IsNotFound(err))
+}
+
+func TestStorageExpansion(t *testing.T) {
+	ctx := context.Background()
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+
+	allow := true
+	sclass := &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "beta2"},
+		AllowVolumeExpansion: &allow,
+	}
+
+	claim0 := claimTemplate("db-data-db-0", 1024)
+	claim0.Namespace = ns
+	claim0.Labels = serviceLabels(lid, "db")
+
+	// expanded by previous attempt
+	claim1 := claimTemplate("db-data-db-1", 2048)
+	claim1.Namespace = ns
+	claim1.Labels = serviceLabels(lid, "db")
+
+	kc := kubefake.NewSimpleClientset(
+		sclass,
+		&claim0,
+		&claim1,
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: ns, Labels: serviceLabels(lid, "db")},
+			Spec: appsv1.StatefulSetSpec{
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{claimTemplate("db-data", 1024)},
+			},
+		},
+	)
+
+	c := clientForTest(t, kc, akashclient_fake.NewSimpleClientset()).(*client)
+
+	deployment := &ctypes.Deployment{
+		Lid: lid,
+		MGroup: &mani.Group{
+			Name:     "g",
+			Services: []mani.Service{*persistentService(2048)},
+		},
+	}
+
+	requests, err := c.StorageExpansion(ctx, deployment)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	require.Equal(t, int64(1024), requests["beta2"].Val.Int64())
+
+	// claims are left intact until deployment is applied
+	res, err := kc.CoreV1().PersistentVolumeClaims(ns).Get(ctx, "db-data-db-0", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	size := res.Spec.Resources.Requests[corev1.ResourceStorage]
+	require.Equal(t, int64(1024), size.Value())
+
+	deployment.MGroup.Services[0] = *persistentService(1024)
+
+	requests, err = c.StorageExpansion(ctx, deployment)
+	require.NoError(t, err)
+	require.Empty(t, requests)
+}
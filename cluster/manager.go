@@ -67,6 +67,7 @@ type deploymentManager struct {
 	log                 log.Logger
 	lc                  lifecycle.Lifecycle
 	hostnameService     ctypes.HostnameServiceClient
+	inventory           *inventoryService
 	config              Config
 	serviceShuttingDown <-chan struct{}
 }
@@ -89,6 +90,7 @@ func newDeploymentManager(s *service, deployment ctypes.IDeployment, isNewLease
 		log:                 logger,
 		lc:                  lifecycle.New(),
 		hostnameService:     s.HostnameService(),
+		inventory:           s.inventory,
 		config:              s.config,
 		serviceShuttingDown: s.lc.ShuttingDown(),
 		currentHostnames:    make(map[string]struct{}),
@@ -312,6 +314,15 @@ func (dm *deploymentManager) startTeardown() <-chan error {
 	})
 }
 
+func (dm *deploymentManager) adjustStorage(ctx context.Context) error {
+	storage, err := dm.client.StorageExpansion(ctx, dm.deployment)
+	if err != nil || len(storage) == 0 {
+		return err
+	}
+
+	return dm.inventory.adjustStorage(ctx, dm.deployment.LeaseID().OrderID(), storage)
+}
+
 type serviceExposeWithServiceName struct {
 	expose mani.ServiceExpose
 	name   string
@@ -373,6 +384,13 @@ func (dm *deploymentManager) doDeploy(ctx context.Context) ([]string, []string,
 		}
 	}
 
+	// volumes grown by the manifest take space beyond the reservation, claim it before deploying
+	if err = dm.adjustStorage(ctx); err != nil {
+		deploymentCounter.WithLabelValues("adjust-storage", "err").Inc()
+		dm.log.Error("adjusting storage of reservation", "err", err)
+		return nil, nil, err
+	}
+
 	// Don't use a context tied to the lifecycle, as we don't want to cancel Kubernetes operations
 	deployCtx := util.ApplyToContext(context.Background(), dm.config.ClusterSettings)
 
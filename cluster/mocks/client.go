@@ -9,6 +9,10 @@ import (
 
 	io "io"
 
+	time "time"
+
+	typesv1beta3 "github.com/akash-network/akash-api/go/node/types/v1beta3"
+
 	marketv1beta3 "github.com/akash-network/akash-api/go/node/market/v1beta3"
 
 	mock "github.com/stretchr/testify/mock"
@@ -132,6 +136,62 @@ func (_c *Client_ConnectHostnameToDeployment_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// CreateSnapshot provides a mock function with given fields: ctx, lID, service
+func (_m *Client) CreateSnapshot(ctx context.Context, lID marketv1beta3.LeaseID, service string) ([]v1beta3.VolumeSnapshot, error) {
+	ret := _m.Called(ctx, lID, service)
+
+	var r0 []v1beta3.VolumeSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, string) ([]v1beta3.VolumeSnapshot, error)); ok {
+		return rf(ctx, lID, service)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, string) []v1beta3.VolumeSnapshot); ok {
+		r0 = rf(ctx, lID, service)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1beta3.VolumeSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, marketv1beta3.LeaseID, string) error); ok {
+		r1 = rf(ctx, lID, service)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_CreateSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSnapshot'
+type Client_CreateSnapshot_Call struct {
+	*mock.Call
+}
+
+// CreateSnapshot is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lID marketv1beta3.LeaseID
+//   - service string
+func (_e *Client_Expecter) CreateSnapshot(ctx interface{}, lID interface{}, service interface{}) *Client_CreateSnapshot_Call {
+	return &Client_CreateSnapshot_Call{Call: _e.mock.On("CreateSnapshot", ctx, lID, service)}
+}
+
+func (_c *Client_CreateSnapshot_Call) Run(run func(ctx context.Context, lID marketv1beta3.LeaseID, service string)) *Client_CreateSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Client_CreateSnapshot_Call) Return(_a0 []v1beta3.VolumeSnapshot, _a1 error) *Client_CreateSnapshot_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_CreateSnapshot_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID, string) ([]v1beta3.VolumeSnapshot, error)) *Client_CreateSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeclareHostname provides a mock function with given fields: ctx, lID, host, serviceName, externalPort
 func (_m *Client) DeclareHostname(ctx context.Context, lID marketv1beta3.LeaseID, host string, serviceName string, externalPort uint32) error {
 	ret := _m.Called(ctx, lID, host, serviceName, externalPort)
@@ -611,6 +671,59 @@ func (_c *Client_GetManifestGroup_Call) RunAndReturn(run func(context.Context, m
 	return _c
 }
 
+// GetManifestStatus provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetManifestStatus(_a0 context.Context, _a1 marketv1beta3.LeaseID) (akash_networkv2beta2.ManifestStatus, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 akash_networkv2beta2.ManifestStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID) (akash_networkv2beta2.ManifestStatus, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID) akash_networkv2beta2.ManifestStatus); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(akash_networkv2beta2.ManifestStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, marketv1beta3.LeaseID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_GetManifestStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetManifestStatus'
+type Client_GetManifestStatus_Call struct {
+	*mock.Call
+}
+
+// GetManifestStatus is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 marketv1beta3.LeaseID
+func (_e *Client_Expecter) GetManifestStatus(_a0 interface{}, _a1 interface{}) *Client_GetManifestStatus_Call {
+	return &Client_GetManifestStatus_Call{Call: _e.mock.On("GetManifestStatus", _a0, _a1)}
+}
+
+func (_c *Client_GetManifestStatus_Call) Run(run func(_a0 context.Context, _a1 marketv1beta3.LeaseID)) *Client_GetManifestStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID))
+	})
+	return _c
+}
+
+func (_c *Client_GetManifestStatus_Call) Return(_a0 akash_networkv2beta2.ManifestStatus, _a1 error) *Client_GetManifestStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_GetManifestStatus_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID) (akash_networkv2beta2.ManifestStatus, error)) *Client_GetManifestStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Inventory provides a mock function with given fields: _a0
 func (_m *Client) Inventory(_a0 context.Context) (v1beta3.Inventory, error) {
 	ret := _m.Called(_a0)
@@ -775,25 +888,25 @@ func (_c *Client_LeaseEvents_Call) RunAndReturn(run func(context.Context, market
 	return _c
 }
 
-// LeaseLogs provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
-func (_m *Client) LeaseLogs(_a0 context.Context, _a1 marketv1beta3.LeaseID, _a2 string, _a3 bool, _a4 *int64) ([]*v1beta3.ServiceLog, error) {
-	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+// LeaseLogs provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) LeaseLogs(_a0 context.Context, _a1 marketv1beta3.LeaseID, _a2 v1beta3.LeaseLogsOptions) ([]*v1beta3.ServiceLog, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 []*v1beta3.ServiceLog
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, string, bool, *int64) ([]*v1beta3.ServiceLog, error)); ok {
-		return rf(_a0, _a1, _a2, _a3, _a4)
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, v1beta3.LeaseLogsOptions) ([]*v1beta3.ServiceLog, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, string, bool, *int64) []*v1beta3.ServiceLog); ok {
-		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, v1beta3.LeaseLogsOptions) []*v1beta3.ServiceLog); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*v1beta3.ServiceLog)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, marketv1beta3.LeaseID, string, bool, *int64) error); ok {
-		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	if rf, ok := ret.Get(1).(func(context.Context, marketv1beta3.LeaseID, v1beta3.LeaseLogsOptions) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -809,16 +922,14 @@ type Client_LeaseLogs_Call struct {
 // LeaseLogs is a helper method to define mock.On call
 //   - _a0 context.Context
 //   - _a1 marketv1beta3.LeaseID
-//   - _a2 string
-//   - _a3 bool
-//   - _a4 *int64
-func (_e *Client_Expecter) LeaseLogs(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}, _a4 interface{}) *Client_LeaseLogs_Call {
-	return &Client_LeaseLogs_Call{Call: _e.mock.On("LeaseLogs", _a0, _a1, _a2, _a3, _a4)}
+//   - _a2 v1beta3.LeaseLogsOptions
+func (_e *Client_Expecter) LeaseLogs(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Client_LeaseLogs_Call {
+	return &Client_LeaseLogs_Call{Call: _e.mock.On("LeaseLogs", _a0, _a1, _a2)}
 }
 
-func (_c *Client_LeaseLogs_Call) Run(run func(_a0 context.Context, _a1 marketv1beta3.LeaseID, _a2 string, _a3 bool, _a4 *int64)) *Client_LeaseLogs_Call {
+func (_c *Client_LeaseLogs_Call) Run(run func(_a0 context.Context, _a1 marketv1beta3.LeaseID, _a2 v1beta3.LeaseLogsOptions)) *Client_LeaseLogs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID), args[2].(string), args[3].(bool), args[4].(*int64))
+		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID), args[2].(v1beta3.LeaseLogsOptions))
 	})
 	return _c
 }
@@ -828,7 +939,62 @@ func (_c *Client_LeaseLogs_Call) Return(_a0 []*v1beta3.ServiceLog, _a1 error) *C
 	return _c
 }
 
-func (_c *Client_LeaseLogs_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID, string, bool, *int64) ([]*v1beta3.ServiceLog, error)) *Client_LeaseLogs_Call {
+func (_c *Client_LeaseLogs_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID, v1beta3.LeaseLogsOptions) ([]*v1beta3.ServiceLog, error)) *Client_LeaseLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LeaseMetrics provides a mock function with given fields: _a0, _a1
+func (_m *Client) LeaseMetrics(_a0 context.Context, _a1 marketv1beta3.LeaseID) (*v1beta3.LeaseMetrics, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *v1beta3.LeaseMetrics
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID) (*v1beta3.LeaseMetrics, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID) *v1beta3.LeaseMetrics); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1beta3.LeaseMetrics)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, marketv1beta3.LeaseID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_LeaseMetrics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeaseMetrics'
+type Client_LeaseMetrics_Call struct {
+	*mock.Call
+}
+
+// LeaseMetrics is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 marketv1beta3.LeaseID
+func (_e *Client_Expecter) LeaseMetrics(_a0 interface{}, _a1 interface{}) *Client_LeaseMetrics_Call {
+	return &Client_LeaseMetrics_Call{Call: _e.mock.On("LeaseMetrics", _a0, _a1)}
+}
+
+func (_c *Client_LeaseMetrics_Call) Run(run func(_a0 context.Context, _a1 marketv1beta3.LeaseID)) *Client_LeaseMetrics_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID))
+	})
+	return _c
+}
+
+func (_c *Client_LeaseMetrics_Call) Return(_a0 *v1beta3.LeaseMetrics, _a1 error) *Client_LeaseMetrics_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_LeaseMetrics_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID) (*v1beta3.LeaseMetrics, error)) *Client_LeaseMetrics_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -888,6 +1054,62 @@ func (_c *Client_LeaseStatus_Call) RunAndReturn(run func(context.Context, market
 	return _c
 }
 
+// ListSnapshots provides a mock function with given fields: ctx, lID, service
+func (_m *Client) ListSnapshots(ctx context.Context, lID marketv1beta3.LeaseID, service string) ([]v1beta3.VolumeSnapshot, error) {
+	ret := _m.Called(ctx, lID, service)
+
+	var r0 []v1beta3.VolumeSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, string) ([]v1beta3.VolumeSnapshot, error)); ok {
+		return rf(ctx, lID, service)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, string) []v1beta3.VolumeSnapshot); ok {
+		r0 = rf(ctx, lID, service)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1beta3.VolumeSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, marketv1beta3.LeaseID, string) error); ok {
+		r1 = rf(ctx, lID, service)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_ListSnapshots_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSnapshots'
+type Client_ListSnapshots_Call struct {
+	*mock.Call
+}
+
+// ListSnapshots is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lID marketv1beta3.LeaseID
+//   - service string
+func (_e *Client_Expecter) ListSnapshots(ctx interface{}, lID interface{}, service interface{}) *Client_ListSnapshots_Call {
+	return &Client_ListSnapshots_Call{Call: _e.mock.On("ListSnapshots", ctx, lID, service)}
+}
+
+func (_c *Client_ListSnapshots_Call) Run(run func(ctx context.Context, lID marketv1beta3.LeaseID, service string)) *Client_ListSnapshots_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Client_ListSnapshots_Call) Return(_a0 []v1beta3.VolumeSnapshot, _a1 error) *Client_ListSnapshots_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_ListSnapshots_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID, string) ([]v1beta3.VolumeSnapshot, error)) *Client_ListSnapshots_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ObserveHostnameState provides a mock function with given fields: ctx
 func (_m *Client) ObserveHostnameState(ctx context.Context) (<-chan v1beta3.HostnameResourceEvent, error) {
 	ret := _m.Called(ctx)
@@ -996,6 +1218,54 @@ func (_c *Client_ObserveIPState_Call) RunAndReturn(run func(context.Context) (<-
 	return _c
 }
 
+// PortForward provides a mock function with given fields: ctx, lID, service, podIndex, port, input, output
+func (_m *Client) PortForward(ctx context.Context, lID marketv1beta3.LeaseID, service string, podIndex uint, port uint32, input io.Reader, output io.Writer) error {
+	ret := _m.Called(ctx, lID, service, podIndex, port, input, output)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, string, uint, uint32, io.Reader, io.Writer) error); ok {
+		r0 = rf(ctx, lID, service, podIndex, port, input, output)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_PortForward_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PortForward'
+type Client_PortForward_Call struct {
+	*mock.Call
+}
+
+// PortForward is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lID marketv1beta3.LeaseID
+//   - service string
+//   - podIndex uint
+//   - port uint32
+//   - input io.Reader
+//   - output io.Writer
+func (_e *Client_Expecter) PortForward(ctx interface{}, lID interface{}, service interface{}, podIndex interface{}, port interface{}, input interface{}, output interface{}) *Client_PortForward_Call {
+	return &Client_PortForward_Call{Call: _e.mock.On("PortForward", ctx, lID, service, podIndex, port, input, output)}
+}
+
+func (_c *Client_PortForward_Call) Run(run func(ctx context.Context, lID marketv1beta3.LeaseID, service string, podIndex uint, port uint32, input io.Reader, output io.Writer)) *Client_PortForward_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID), args[2].(string), args[3].(uint), args[4].(uint32), args[5].(io.Reader), args[6].(io.Writer))
+	})
+	return _c
+}
+
+func (_c *Client_PortForward_Call) Return(_a0 error) *Client_PortForward_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_PortForward_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID, string, uint, uint32, io.Reader, io.Writer) error) *Client_PortForward_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PurgeDeclaredHostname provides a mock function with given fields: ctx, lID, hostname
 func (_m *Client) PurgeDeclaredHostname(ctx context.Context, lID marketv1beta3.LeaseID, hostname string) error {
 	ret := _m.Called(ctx, lID, hostname)
@@ -1217,6 +1487,152 @@ func (_c *Client_RemoveHostnameFromDeployment_Call) RunAndReturn(run func(contex
 	return _c
 }
 
+// RestartService provides a mock function with given fields: ctx, lID, service, podIndex
+func (_m *Client) RestartService(ctx context.Context, lID marketv1beta3.LeaseID, service string, podIndex *uint) error {
+	ret := _m.Called(ctx, lID, service, podIndex)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, string, *uint) error); ok {
+		r0 = rf(ctx, lID, service, podIndex)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_RestartService_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestartService'
+type Client_RestartService_Call struct {
+	*mock.Call
+}
+
+// RestartService is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lID marketv1beta3.LeaseID
+//   - service string
+//   - podIndex *uint
+func (_e *Client_Expecter) RestartService(ctx interface{}, lID interface{}, service interface{}, podIndex interface{}) *Client_RestartService_Call {
+	return &Client_RestartService_Call{Call: _e.mock.On("RestartService", ctx, lID, service, podIndex)}
+}
+
+func (_c *Client_RestartService_Call) Run(run func(ctx context.Context, lID marketv1beta3.LeaseID, service string, podIndex *uint)) *Client_RestartService_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID), args[2].(string), args[3].(*uint))
+	})
+	return _c
+}
+
+func (_c *Client_RestartService_Call) Return(_a0 error) *Client_RestartService_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_RestartService_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID, string, *uint) error) *Client_RestartService_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreSnapshot provides a mock function with given fields: ctx, lID, service, name
+func (_m *Client) RestoreSnapshot(ctx context.Context, lID marketv1beta3.LeaseID, service string, name string) error {
+	ret := _m.Called(ctx, lID, service, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, string, string) error); ok {
+		r0 = rf(ctx, lID, service, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_RestoreSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreSnapshot'
+type Client_RestoreSnapshot_Call struct {
+	*mock.Call
+}
+
+// RestoreSnapshot is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lID marketv1beta3.LeaseID
+//   - service string
+//   - name string
+func (_e *Client_Expecter) RestoreSnapshot(ctx interface{}, lID interface{}, service interface{}, name interface{}) *Client_RestoreSnapshot_Call {
+	return &Client_RestoreSnapshot_Call{Call: _e.mock.On("RestoreSnapshot", ctx, lID, service, name)}
+}
+
+func (_c *Client_RestoreSnapshot_Call) Run(run func(ctx context.Context, lID marketv1beta3.LeaseID, service string, name string)) *Client_RestoreSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *Client_RestoreSnapshot_Call) Return(_a0 error) *Client_RestoreSnapshot_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_RestoreSnapshot_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID, string, string) error) *Client_RestoreSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RollbackDeployment provides a mock function with given fields: ctx, lID, reason
+func (_m *Client) RollbackDeployment(ctx context.Context, lID marketv1beta3.LeaseID, reason string) (v1beta3.IDeployment, error) {
+	ret := _m.Called(ctx, lID, reason)
+
+	var r0 v1beta3.IDeployment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, string) (v1beta3.IDeployment, error)); ok {
+		return rf(ctx, lID, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, string) v1beta3.IDeployment); ok {
+		r0 = rf(ctx, lID, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(v1beta3.IDeployment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, marketv1beta3.LeaseID, string) error); ok {
+		r1 = rf(ctx, lID, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_RollbackDeployment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RollbackDeployment'
+type Client_RollbackDeployment_Call struct {
+	*mock.Call
+}
+
+// RollbackDeployment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lID marketv1beta3.LeaseID
+//   - reason string
+func (_e *Client_Expecter) RollbackDeployment(ctx interface{}, lID interface{}, reason interface{}) *Client_RollbackDeployment_Call {
+	return &Client_RollbackDeployment_Call{Call: _e.mock.On("RollbackDeployment", ctx, lID, reason)}
+}
+
+func (_c *Client_RollbackDeployment_Call) Run(run func(ctx context.Context, lID marketv1beta3.LeaseID, reason string)) *Client_RollbackDeployment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Client_RollbackDeployment_Call) Return(_a0 v1beta3.IDeployment, _a1 error) *Client_RollbackDeployment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_RollbackDeployment_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID, string) (v1beta3.IDeployment, error)) *Client_RollbackDeployment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ServiceStatus provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) ServiceStatus(_a0 context.Context, _a1 marketv1beta3.LeaseID, _a2 string) (*v1beta3.ServiceStatus, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -1273,6 +1689,160 @@ func (_c *Client_ServiceStatus_Call) RunAndReturn(run func(context.Context, mark
 	return _c
 }
 
+// SetLastGoodDeployment provides a mock function with given fields: ctx, lID
+func (_m *Client) SetLastGoodDeployment(ctx context.Context, lID marketv1beta3.LeaseID) error {
+	ret := _m.Called(ctx, lID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID) error); ok {
+		r0 = rf(ctx, lID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_SetLastGoodDeployment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetLastGoodDeployment'
+type Client_SetLastGoodDeployment_Call struct {
+	*mock.Call
+}
+
+// SetLastGoodDeployment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lID marketv1beta3.LeaseID
+func (_e *Client_Expecter) SetLastGoodDeployment(ctx interface{}, lID interface{}) *Client_SetLastGoodDeployment_Call {
+	return &Client_SetLastGoodDeployment_Call{Call: _e.mock.On("SetLastGoodDeployment", ctx, lID)}
+}
+
+func (_c *Client_SetLastGoodDeployment_Call) Run(run func(ctx context.Context, lID marketv1beta3.LeaseID)) *Client_SetLastGoodDeployment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID))
+	})
+	return _c
+}
+
+func (_c *Client_SetLastGoodDeployment_Call) Return(_a0 error) *Client_SetLastGoodDeployment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_SetLastGoodDeployment_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID) error) *Client_SetLastGoodDeployment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StorageExpansion provides a mock function with given fields: ctx, deployment
+func (_m *Client) StorageExpansion(ctx context.Context, deployment v1beta3.IDeployment) (map[string]typesv1beta3.ResourceValue, error) {
+	ret := _m.Called(ctx, deployment)
+
+	var r0 map[string]typesv1beta3.ResourceValue
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta3.IDeployment) (map[string]typesv1beta3.ResourceValue, error)); ok {
+		return rf(ctx, deployment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta3.IDeployment) map[string]typesv1beta3.ResourceValue); ok {
+		r0 = rf(ctx, deployment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]typesv1beta3.ResourceValue)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta3.IDeployment) error); ok {
+		r1 = rf(ctx, deployment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_StorageExpansion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StorageExpansion'
+type Client_StorageExpansion_Call struct {
+	*mock.Call
+}
+
+// StorageExpansion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deployment v1beta3.IDeployment
+func (_e *Client_Expecter) StorageExpansion(ctx interface{}, deployment interface{}) *Client_StorageExpansion_Call {
+	return &Client_StorageExpansion_Call{Call: _e.mock.On("StorageExpansion", ctx, deployment)}
+}
+
+func (_c *Client_StorageExpansion_Call) Run(run func(ctx context.Context, deployment v1beta3.IDeployment)) *Client_StorageExpansion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta3.IDeployment))
+	})
+	return _c
+}
+
+func (_c *Client_StorageExpansion_Call) Return(_a0 map[string]typesv1beta3.ResourceValue, _a1 error) *Client_StorageExpansion_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_StorageExpansion_Call) RunAndReturn(run func(context.Context, v1beta3.IDeployment) (map[string]typesv1beta3.ResourceValue, error)) *Client_StorageExpansion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SweepOrphans provides a mock function with given fields: ctx, active, minAge
+func (_m *Client) SweepOrphans(ctx context.Context, active []marketv1beta3.LeaseID, minAge time.Duration) ([]marketv1beta3.LeaseID, error) {
+	ret := _m.Called(ctx, active, minAge)
+
+	var r0 []marketv1beta3.LeaseID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []marketv1beta3.LeaseID, time.Duration) ([]marketv1beta3.LeaseID, error)); ok {
+		return rf(ctx, active, minAge)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []marketv1beta3.LeaseID, time.Duration) []marketv1beta3.LeaseID); ok {
+		r0 = rf(ctx, active, minAge)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]marketv1beta3.LeaseID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []marketv1beta3.LeaseID, time.Duration) error); ok {
+		r1 = rf(ctx, active, minAge)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_SweepOrphans_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SweepOrphans'
+type Client_SweepOrphans_Call struct {
+	*mock.Call
+}
+
+// SweepOrphans is a helper method to define mock.On call
+//   - ctx context.Context
+//   - active []marketv1beta3.LeaseID
+//   - minAge time.Duration
+func (_e *Client_Expecter) SweepOrphans(ctx interface{}, active interface{}, minAge interface{}) *Client_SweepOrphans_Call {
+	return &Client_SweepOrphans_Call{Call: _e.mock.On("SweepOrphans", ctx, active, minAge)}
+}
+
+func (_c *Client_SweepOrphans_Call) Run(run func(ctx context.Context, active []marketv1beta3.LeaseID, minAge time.Duration)) *Client_SweepOrphans_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]marketv1beta3.LeaseID), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Client_SweepOrphans_Call) Return(_a0 []marketv1beta3.LeaseID, _a1 error) *Client_SweepOrphans_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_SweepOrphans_Call) RunAndReturn(run func(context.Context, []marketv1beta3.LeaseID, time.Duration) ([]marketv1beta3.LeaseID, error)) *Client_SweepOrphans_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // TeardownLease provides a mock function with given fields: _a0, _a1
 func (_m *Client) TeardownLease(_a0 context.Context, _a1 marketv1beta3.LeaseID) error {
 	ret := _m.Called(_a0, _a1)
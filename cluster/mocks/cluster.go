@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	clustertypesv1beta3 "github.com/akash-network/provider/cluster/types/v1beta3"
 	mock "github.com/stretchr/testify/mock"
 
@@ -24,6 +26,115 @@ func (_m *Cluster) EXPECT() *Cluster_Expecter {
 	return &Cluster_Expecter{mock: &_m.Mock}
 }
 
+// DryRunReserve provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Cluster) DryRunReserve(_a0 context.Context, _a1 v1beta3.OrderID, _a2 typesv1beta3.ResourceGroup) (clustertypesv1beta3.Reservation, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 clustertypesv1beta3.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta3.OrderID, typesv1beta3.ResourceGroup) (clustertypesv1beta3.Reservation, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta3.OrderID, typesv1beta3.ResourceGroup) clustertypesv1beta3.Reservation); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(clustertypesv1beta3.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta3.OrderID, typesv1beta3.ResourceGroup) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Cluster_DryRunReserve_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DryRunReserve'
+type Cluster_DryRunReserve_Call struct {
+	*mock.Call
+}
+
+// DryRunReserve is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 v1beta3.OrderID
+//   - _a2 typesv1beta3.ResourceGroup
+func (_e *Cluster_Expecter) DryRunReserve(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Cluster_DryRunReserve_Call {
+	return &Cluster_DryRunReserve_Call{Call: _e.mock.On("DryRunReserve", _a0, _a1, _a2)}
+}
+
+func (_c *Cluster_DryRunReserve_Call) Run(run func(_a0 context.Context, _a1 v1beta3.OrderID, _a2 typesv1beta3.ResourceGroup)) *Cluster_DryRunReserve_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta3.OrderID), args[2].(typesv1beta3.ResourceGroup))
+	})
+	return _c
+}
+
+func (_c *Cluster_DryRunReserve_Call) Return(_a0 clustertypesv1beta3.Reservation, _a1 error) *Cluster_DryRunReserve_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Cluster_DryRunReserve_Call) RunAndReturn(run func(context.Context, v1beta3.OrderID, typesv1beta3.ResourceGroup) (clustertypesv1beta3.Reservation, error)) *Cluster_DryRunReserve_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// OwnerUsage provides a mock function with given fields: _a0, _a1
+func (_m *Cluster) OwnerUsage(_a0 context.Context, _a1 string) (clustertypesv1beta3.InventoryOwnerUsage, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 clustertypesv1beta3.InventoryOwnerUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (clustertypesv1beta3.InventoryOwnerUsage, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) clustertypesv1beta3.InventoryOwnerUsage); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(clustertypesv1beta3.InventoryOwnerUsage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Cluster_OwnerUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OwnerUsage'
+type Cluster_OwnerUsage_Call struct {
+	*mock.Call
+}
+
+// OwnerUsage is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *Cluster_Expecter) OwnerUsage(_a0 interface{}, _a1 interface{}) *Cluster_OwnerUsage_Call {
+	return &Cluster_OwnerUsage_Call{Call: _e.mock.On("OwnerUsage", _a0, _a1)}
+}
+
+func (_c *Cluster_OwnerUsage_Call) Run(run func(_a0 context.Context, _a1 string)) *Cluster_OwnerUsage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Cluster_OwnerUsage_Call) Return(_a0 clustertypesv1beta3.InventoryOwnerUsage, _a1 error) *Cluster_OwnerUsage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Cluster_OwnerUsage_Call) RunAndReturn(run func(context.Context, string) (clustertypesv1beta3.InventoryOwnerUsage, error)) *Cluster_OwnerUsage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Reserve provides a mock function with given fields: _a0, _a1
 func (_m *Cluster) Reserve(_a0 v1beta3.OrderID, _a1 typesv1beta3.ResourceGroup) (clustertypesv1beta3.Reservation, error) {
 	ret := _m.Called(_a0, _a1)
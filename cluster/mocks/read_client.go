@@ -304,6 +304,59 @@ func (_c *ReadClient_GetManifestGroup_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// GetManifestStatus provides a mock function with given fields: _a0, _a1
+func (_m *ReadClient) GetManifestStatus(_a0 context.Context, _a1 marketv1beta3.LeaseID) (v2beta2.ManifestStatus, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 v2beta2.ManifestStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID) (v2beta2.ManifestStatus, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID) v2beta2.ManifestStatus); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(v2beta2.ManifestStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, marketv1beta3.LeaseID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadClient_GetManifestStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetManifestStatus'
+type ReadClient_GetManifestStatus_Call struct {
+	*mock.Call
+}
+
+// GetManifestStatus is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 marketv1beta3.LeaseID
+func (_e *ReadClient_Expecter) GetManifestStatus(_a0 interface{}, _a1 interface{}) *ReadClient_GetManifestStatus_Call {
+	return &ReadClient_GetManifestStatus_Call{Call: _e.mock.On("GetManifestStatus", _a0, _a1)}
+}
+
+func (_c *ReadClient_GetManifestStatus_Call) Run(run func(_a0 context.Context, _a1 marketv1beta3.LeaseID)) *ReadClient_GetManifestStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID))
+	})
+	return _c
+}
+
+func (_c *ReadClient_GetManifestStatus_Call) Return(_a0 v2beta2.ManifestStatus, _a1 error) *ReadClient_GetManifestStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReadClient_GetManifestStatus_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID) (v2beta2.ManifestStatus, error)) *ReadClient_GetManifestStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LeaseEvents provides a mock function with given fields: _a0, _a1, _a2, _a3
 func (_m *ReadClient) LeaseEvents(_a0 context.Context, _a1 marketv1beta3.LeaseID, _a2 string, _a3 bool) (v1beta3.EventsWatcher, error) {
 	ret := _m.Called(_a0, _a1, _a2, _a3)
@@ -361,25 +414,25 @@ func (_c *ReadClient_LeaseEvents_Call) RunAndReturn(run func(context.Context, ma
 	return _c
 }
 
-// LeaseLogs provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
-func (_m *ReadClient) LeaseLogs(_a0 context.Context, _a1 marketv1beta3.LeaseID, _a2 string, _a3 bool, _a4 *int64) ([]*v1beta3.ServiceLog, error) {
-	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+// LeaseLogs provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ReadClient) LeaseLogs(_a0 context.Context, _a1 marketv1beta3.LeaseID, _a2 v1beta3.LeaseLogsOptions) ([]*v1beta3.ServiceLog, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 []*v1beta3.ServiceLog
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, string, bool, *int64) ([]*v1beta3.ServiceLog, error)); ok {
-		return rf(_a0, _a1, _a2, _a3, _a4)
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, v1beta3.LeaseLogsOptions) ([]*v1beta3.ServiceLog, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, string, bool, *int64) []*v1beta3.ServiceLog); ok {
-		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID, v1beta3.LeaseLogsOptions) []*v1beta3.ServiceLog); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*v1beta3.ServiceLog)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, marketv1beta3.LeaseID, string, bool, *int64) error); ok {
-		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	if rf, ok := ret.Get(1).(func(context.Context, marketv1beta3.LeaseID, v1beta3.LeaseLogsOptions) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -395,16 +448,14 @@ type ReadClient_LeaseLogs_Call struct {
 // LeaseLogs is a helper method to define mock.On call
 //   - _a0 context.Context
 //   - _a1 marketv1beta3.LeaseID
-//   - _a2 string
-//   - _a3 bool
-//   - _a4 *int64
-func (_e *ReadClient_Expecter) LeaseLogs(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}, _a4 interface{}) *ReadClient_LeaseLogs_Call {
-	return &ReadClient_LeaseLogs_Call{Call: _e.mock.On("LeaseLogs", _a0, _a1, _a2, _a3, _a4)}
+//   - _a2 v1beta3.LeaseLogsOptions
+func (_e *ReadClient_Expecter) LeaseLogs(_a0 interface{}, _a1 interface{}, _a2 interface{}) *ReadClient_LeaseLogs_Call {
+	return &ReadClient_LeaseLogs_Call{Call: _e.mock.On("LeaseLogs", _a0, _a1, _a2)}
 }
 
-func (_c *ReadClient_LeaseLogs_Call) Run(run func(_a0 context.Context, _a1 marketv1beta3.LeaseID, _a2 string, _a3 bool, _a4 *int64)) *ReadClient_LeaseLogs_Call {
+func (_c *ReadClient_LeaseLogs_Call) Run(run func(_a0 context.Context, _a1 marketv1beta3.LeaseID, _a2 v1beta3.LeaseLogsOptions)) *ReadClient_LeaseLogs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID), args[2].(string), args[3].(bool), args[4].(*int64))
+		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID), args[2].(v1beta3.LeaseLogsOptions))
 	})
 	return _c
 }
@@ -414,7 +465,62 @@ func (_c *ReadClient_LeaseLogs_Call) Return(_a0 []*v1beta3.ServiceLog, _a1 error
 	return _c
 }
 
-func (_c *ReadClient_LeaseLogs_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID, string, bool, *int64) ([]*v1beta3.ServiceLog, error)) *ReadClient_LeaseLogs_Call {
+func (_c *ReadClient_LeaseLogs_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID, v1beta3.LeaseLogsOptions) ([]*v1beta3.ServiceLog, error)) *ReadClient_LeaseLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LeaseMetrics provides a mock function with given fields: _a0, _a1
+func (_m *ReadClient) LeaseMetrics(_a0 context.Context, _a1 marketv1beta3.LeaseID) (*v1beta3.LeaseMetrics, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *v1beta3.LeaseMetrics
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID) (*v1beta3.LeaseMetrics, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta3.LeaseID) *v1beta3.LeaseMetrics); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1beta3.LeaseMetrics)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, marketv1beta3.LeaseID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadClient_LeaseMetrics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeaseMetrics'
+type ReadClient_LeaseMetrics_Call struct {
+	*mock.Call
+}
+
+// LeaseMetrics is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 marketv1beta3.LeaseID
+func (_e *ReadClient_Expecter) LeaseMetrics(_a0 interface{}, _a1 interface{}) *ReadClient_LeaseMetrics_Call {
+	return &ReadClient_LeaseMetrics_Call{Call: _e.mock.On("LeaseMetrics", _a0, _a1)}
+}
+
+func (_c *ReadClient_LeaseMetrics_Call) Run(run func(_a0 context.Context, _a1 marketv1beta3.LeaseID)) *ReadClient_LeaseMetrics_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(marketv1beta3.LeaseID))
+	})
+	return _c
+}
+
+func (_c *ReadClient_LeaseMetrics_Call) Return(_a0 *v1beta3.LeaseMetrics, _a1 error) *ReadClient_LeaseMetrics_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReadClient_LeaseMetrics_Call) RunAndReturn(run func(context.Context, marketv1beta3.LeaseID) (*v1beta3.LeaseMetrics, error)) *ReadClient_LeaseMetrics_Call {
 	_c.Call.Return(run)
 	return _c
 }
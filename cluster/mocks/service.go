@@ -114,6 +114,62 @@ func (_c *Service_Done_Call) RunAndReturn(run func() <-chan struct{}) *Service_D
 	return _c
 }
 
+// DryRunReserve provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Service) DryRunReserve(_a0 context.Context, _a1 v1beta3.OrderID, _a2 nodetypesv1beta3.ResourceGroup) (typesv1beta3.Reservation, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 typesv1beta3.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta3.OrderID, nodetypesv1beta3.ResourceGroup) (typesv1beta3.Reservation, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta3.OrderID, nodetypesv1beta3.ResourceGroup) typesv1beta3.Reservation); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(typesv1beta3.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta3.OrderID, nodetypesv1beta3.ResourceGroup) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_DryRunReserve_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DryRunReserve'
+type Service_DryRunReserve_Call struct {
+	*mock.Call
+}
+
+// DryRunReserve is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 v1beta3.OrderID
+//   - _a2 nodetypesv1beta3.ResourceGroup
+func (_e *Service_Expecter) DryRunReserve(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Service_DryRunReserve_Call {
+	return &Service_DryRunReserve_Call{Call: _e.mock.On("DryRunReserve", _a0, _a1, _a2)}
+}
+
+func (_c *Service_DryRunReserve_Call) Run(run func(_a0 context.Context, _a1 v1beta3.OrderID, _a2 nodetypesv1beta3.ResourceGroup)) *Service_DryRunReserve_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta3.OrderID), args[2].(nodetypesv1beta3.ResourceGroup))
+	})
+	return _c
+}
+
+func (_c *Service_DryRunReserve_Call) Return(_a0 typesv1beta3.Reservation, _a1 error) *Service_DryRunReserve_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_DryRunReserve_Call) RunAndReturn(run func(context.Context, v1beta3.OrderID, nodetypesv1beta3.ResourceGroup) (typesv1beta3.Reservation, error)) *Service_DryRunReserve_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindActiveLease provides a mock function with given fields: ctx, owner, dseq, gseq
 func (_m *Service) FindActiveLease(ctx context.Context, owner types.Address, dseq uint64, gseq uint32) (bool, v1beta3.LeaseID, v2beta2.ManifestGroup, error) {
 	ret := _m.Called(ctx, owner, dseq, gseq)
@@ -226,6 +282,59 @@ func (_c *Service_HostnameService_Call) RunAndReturn(run func() typesv1beta3.Hos
 	return _c
 }
 
+// OwnerUsage provides a mock function with given fields: _a0, _a1
+func (_m *Service) OwnerUsage(_a0 context.Context, _a1 string) (typesv1beta3.InventoryOwnerUsage, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 typesv1beta3.InventoryOwnerUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (typesv1beta3.InventoryOwnerUsage, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) typesv1beta3.InventoryOwnerUsage); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(typesv1beta3.InventoryOwnerUsage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_OwnerUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OwnerUsage'
+type Service_OwnerUsage_Call struct {
+	*mock.Call
+}
+
+// OwnerUsage is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *Service_Expecter) OwnerUsage(_a0 interface{}, _a1 interface{}) *Service_OwnerUsage_Call {
+	return &Service_OwnerUsage_Call{Call: _e.mock.On("OwnerUsage", _a0, _a1)}
+}
+
+func (_c *Service_OwnerUsage_Call) Run(run func(_a0 context.Context, _a1 string)) *Service_OwnerUsage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Service_OwnerUsage_Call) Return(_a0 typesv1beta3.InventoryOwnerUsage, _a1 error) *Service_OwnerUsage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_OwnerUsage_Call) RunAndReturn(run func(context.Context, string) (typesv1beta3.InventoryOwnerUsage, error)) *Service_OwnerUsage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Ready provides a mock function with given fields:
 func (_m *Service) Ready() <-chan struct{} {
 	ret := _m.Called()
@@ -2,6 +2,8 @@ package cluster
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/rand"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	"github.com/akash-network/node/pubsub"
 	"github.com/akash-network/node/util/runner"
 
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	"github.com/akash-network/provider/cluster/util"
 	"github.com/akash-network/provider/event"
@@ -42,8 +45,12 @@ type deploymentMonitor struct {
 	client  Client
 
 	deployment ctypes.IDeployment
+	// redeploy hands deployment back to the manager
+	redeploy func(ctypes.IDeployment) error
 
 	attempts int
+	// lastGood is set once deployment has been recorded as the last good one
+	lastGood bool
 	log      log.Logger
 	lc       lifecycle.Lifecycle
 
@@ -56,6 +63,7 @@ func newDeploymentMonitor(dm *deploymentManager) *deploymentMonitor {
 		session:         dm.session,
 		client:          dm.client,
 		deployment:      dm.deployment,
+		redeploy:        dm.update,
 		log:             dm.log.With("cmp", "deployment-monitor"),
 		lc:              lifecycle.New(),
 		clusterSettings: dm.config.ClusterSettings,
@@ -130,9 +138,8 @@ loop:
 				break
 			}
 
-			m.log.Error("deployment failed.  closing lease.")
 			deploymentHealthCheckCounter.WithLabelValues("failed").Inc()
-			closech = m.runCloseLease(ctx)
+			closech = m.runRollback(ctx)
 
 		case <-closech:
 			closech = nil
@@ -178,11 +185,19 @@ func (m *deploymentMonitor) doCheck(ctx context.Context) (bool, error) {
 	for _, spec := range m.deployment.ManifestGroup().Services {
 		service, foundService := status[spec.Name]
 		if foundService {
-			if uint32(service.Available) < spec.Count {
+			// old replicas stay available while new revision fails to roll out,
+			// so availability alone does not tell that the current group is healthy
+			if !service.RolledOut(spec.Count) {
 				badsvc++
-				m.log.Debug("service available replicas below target",
+				m.log.Debug("service not rolled out",
 					"service", spec.Name,
 					"available", service.Available,
+					"ready", service.ReadyReplicas,
+					"updated", service.UpdatedReplicas,
+					"replicas", service.Replicas,
+					"observed-generation", service.ObservedGeneration,
+					"generation", service.Generation,
+					"progressing", service.Progressing,
 					"target", spec.Count,
 				)
 			}
@@ -194,22 +209,64 @@ func (m *deploymentMonitor) doCheck(ctx context.Context) (bool, error) {
 		}
 	}
 
+	if badsvc == 0 && !m.lastGood {
+		if err := m.client.SetLastGoodDeployment(clientCtx, m.deployment.LeaseID()); err != nil {
+			m.log.Error("recording last good deployment", "err", err)
+		} else {
+			m.lastGood = true
+		}
+	}
+
 	return badsvc == 0, nil
 }
 
-func (m *deploymentMonitor) runCloseLease(ctx context.Context) <-chan runner.Result {
+// runRollback reverts failed update of the lease to its last good deployment.
+// Lease is closed when there is nothing to revert to
+func (m *deploymentMonitor) runRollback(ctx context.Context) <-chan runner.Result {
 	return runner.Do(func() runner.Result {
-		// TODO: retry, timeout
-		err := m.session.Client().Tx().Broadcast(ctx, &mtypes.MsgCloseBid{
-			BidID: m.deployment.LeaseID().BidID(),
-		})
-		if err != nil {
-			m.log.Error("closing deployment", "err", err)
-		} else {
-			m.log.Info("bidding on lease closed")
+		err := m.doRollback(ctx)
+		if err == nil {
+			return runner.NewResult(nil, nil)
+		}
+
+		if !errors.Is(err, kubeclienterrors.ErrNoLastGoodManifest) {
+			m.log.Error("rolling back deployment", "err", err)
 		}
-		return runner.NewResult(nil, err)
+
+		m.log.Error("deployment failed.  closing lease.")
+
+		return runner.NewResult(nil, m.doCloseLease(ctx))
+	})
+}
+
+func (m *deploymentMonitor) doRollback(ctx context.Context) error {
+	clientCtx := util.ApplyToContext(ctx, m.clusterSettings)
+
+	reason := fmt.Sprintf("services did not become available after %d checks", m.attempts)
+
+	deployment, err := m.client.RollbackDeployment(clientCtx, m.deployment.LeaseID(), reason)
+	if err != nil {
+		return err
+	}
+
+	m.log.Info("deployment failed.  rolling back to the last good deployment.")
+	deploymentHealthCheckCounter.WithLabelValues("rollback").Inc()
+
+	return m.redeploy(deployment)
+}
+
+func (m *deploymentMonitor) doCloseLease(ctx context.Context) error {
+	// TODO: retry, timeout
+	err := m.session.Client().Tx().Broadcast(ctx, &mtypes.MsgCloseBid{
+		BidID: m.deployment.LeaseID().BidID(),
 	})
+	if err != nil {
+		m.log.Error("closing deployment", "err", err)
+	} else {
+		m.log.Info("bidding on lease closed")
+	}
+
+	return err
 }
 
 func (m *deploymentMonitor) publishStatus(status event.ClusterDeploymentStatus) {
@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"context"
 	"testing"
 
 	"github.com/boz/go-lifecycle"
@@ -11,6 +12,7 @@ import (
 	"github.com/akash-network/node/pubsub"
 	"github.com/akash-network/node/testutil"
 
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
 	"github.com/akash-network/provider/cluster/mocks"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	"github.com/akash-network/provider/event"
@@ -115,13 +117,16 @@ func TestMonitorSendsClusterDeploymentDeployed(t *testing.T) {
 		Available:          3,
 		Total:              3,
 		URIs:               nil,
-		ObservedGeneration: 0,
-		Replicas:           0,
-		UpdatedReplicas:    0,
-		ReadyReplicas:      0,
-		AvailableReplicas:  0,
+		ObservedGeneration: 2,
+		Replicas:           3,
+		UpdatedReplicas:    3,
+		ReadyReplicas:      3,
+		AvailableReplicas:  3,
+		Generation:         2,
+		Progressing:        true,
 	}
 	client.On("LeaseStatus", mock.Anything, deployment.LeaseID()).Return(statusResult, nil)
+	client.On("SetLastGoodDeployment", mock.Anything, deployment.LeaseID()).Return(nil)
 	mySession := session.New(myLog, nil, nil, -1)
 
 	sub, err := bus.Subscribe()
@@ -144,4 +149,113 @@ func TestMonitorSendsClusterDeploymentDeployed(t *testing.T) {
 	require.Equal(t, event.ClusterDeploymentDeployed, result.Status)
 
 	monitor.lc.Shutdown(nil)
+
+	// healthy deployment is recorded as the last good one
+	client.AssertCalled(t, "SetLastGoodDeployment", mock.Anything, deployment.LeaseID())
+}
+
+func TestMonitorRollback(t *testing.T) {
+	myLog := testutil.Logger(t)
+
+	client := &mocks.Client{}
+	deployment := &ctypes.Deployment{
+		Lid:    testutil.LeaseID(t),
+		MGroup: &manifest.Group{Name: "updated"},
+	}
+
+	lastGood := &ctypes.Deployment{
+		Lid:    deployment.Lid,
+		MGroup: &manifest.Group{Name: "last-good"},
+	}
+
+	client.On("RollbackDeployment", mock.Anything, deployment.LeaseID(), mock.Anything).Return(lastGood, nil).Once()
+	client.On("RollbackDeployment", mock.Anything, deployment.LeaseID(), mock.Anything).Return(nil, kubeclienterrors.ErrNoLastGoodManifest)
+
+	var redeployed []ctypes.IDeployment
+
+	monitor := &deploymentMonitor{
+		client:     client,
+		deployment: deployment,
+		redeploy: func(deployment ctypes.IDeployment) error {
+			redeployed = append(redeployed, deployment)
+			return nil
+		},
+		log: myLog,
+	}
+
+	require.NoError(t, monitor.doRollback(context.Background()))
+	require.Equal(t, []ctypes.IDeployment{lastGood}, redeployed)
+
+	// failed rollback leaves lease to be closed
+	require.ErrorIs(t, monitor.doRollback(context.Background()), kubeclienterrors.ErrNoLastGoodManifest)
+	require.Len(t, redeployed, 1)
+}
+
+func TestMonitorCheckRequiresRollout(t *testing.T) {
+	const serviceName = "web"
+
+	group := &manifest.Group{
+		Services: manifest.Services{{Name: serviceName, Count: 3}},
+	}
+
+	rolledOut := ctypes.ServiceStatus{
+		Name:               serviceName,
+		Available:          3,
+		Total:              3,
+		ObservedGeneration: 2,
+		Replicas:           3,
+		UpdatedReplicas:    3,
+		ReadyReplicas:      3,
+		AvailableReplicas:  3,
+		Generation:         2,
+		Progressing:        true,
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*ctypes.ServiceStatus)
+		ok     bool
+	}{
+		{name: "rolled out", mutate: func(*ctypes.ServiceStatus) {}, ok: true},
+		{name: "spec not observed", mutate: func(s *ctypes.ServiceStatus) { s.ObservedGeneration = 1 }},
+		// old replicas keep service available while new ones fail
+		{name: "new replicas not updated", mutate: func(s *ctypes.ServiceStatus) { s.UpdatedReplicas = 1 }},
+		{name: "old replicas not scaled down", mutate: func(s *ctypes.ServiceStatus) { s.Replicas = 4 }},
+		{name: "replicas not ready", mutate: func(s *ctypes.ServiceStatus) { s.ReadyReplicas = 2 }},
+		{name: "progress deadline exceeded", mutate: func(s *ctypes.ServiceStatus) { s.Progressing = false }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status := rolledOut
+			test.mutate(&status)
+
+			client := &mocks.Client{}
+			deployment := &ctypes.Deployment{
+				Lid:    testutil.LeaseID(t),
+				MGroup: group,
+			}
+
+			client.On("LeaseStatus", mock.Anything, deployment.LeaseID()).
+				Return(map[string]*ctypes.ServiceStatus{serviceName: &status}, nil)
+			client.On("SetLastGoodDeployment", mock.Anything, deployment.LeaseID()).Return(nil)
+
+			monitor := &deploymentMonitor{
+				client:     client,
+				deployment: deployment,
+				log:        testutil.Logger(t),
+			}
+
+			ok, err := monitor.doCheck(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, test.ok, ok)
+
+			// only fully rolled out group becomes the last good one
+			if test.ok {
+				client.AssertCalled(t, "SetLastGoodDeployment", mock.Anything, deployment.LeaseID())
+			} else {
+				client.AssertNotCalled(t, "SetLastGoodDeployment", mock.Anything, mock.Anything)
+			}
+		})
+	}
 }
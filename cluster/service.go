@@ -2,6 +2,7 @@ package cluster
 
 import (
 	"context"
+	"time"
 
 	"github.com/boz/go-lifecycle"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/akash-network/provider/cluster/operatorclients"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	"github.com/akash-network/provider/cluster/util"
 	"github.com/akash-network/provider/event"
 	"github.com/akash-network/provider/operator/waiter"
 	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
@@ -39,12 +41,14 @@ var (
 	})
 )
 
-// Cluster is the interface that wraps Reserve and Unreserve methods
+// Cluster is the interface that wraps Reserve, DryRunReserve, Unreserve and OwnerUsage methods
 //
 //go:generate mockery --name Cluster
 type Cluster interface {
 	Reserve(mtypes.OrderID, atypes.ResourceGroup) (ctypes.Reservation, error)
+	DryRunReserve(context.Context, mtypes.OrderID, atypes.ResourceGroup) (ctypes.Reservation, error)
 	Unreserve(mtypes.OrderID) error
+	OwnerUsage(context.Context, string) (ctypes.InventoryOwnerUsage, error)
 }
 
 // StatusClient is the interface which includes status of service
@@ -219,6 +223,15 @@ func (s *service) Reserve(order mtypes.OrderID, resources atypes.ResourceGroup)
 	return s.inventory.reserve(order, resources)
 }
 
+// DryRunReserve checks if resources can be reserved without changing inventory
+func (s *service) DryRunReserve(ctx context.Context, order mtypes.OrderID, resources atypes.ResourceGroup) (ctypes.Reservation, error) {
+	return s.inventory.dryRunReserve(ctx, order, resources)
+}
+
+func (s *service) OwnerUsage(ctx context.Context, owner string) (ctypes.InventoryOwnerUsage, error) {
+	return s.inventory.usage(ctx, owner)
+}
+
 func (s *service) Unreserve(order mtypes.OrderID) error {
 	return s.inventory.unreserve(order)
 }
@@ -280,6 +293,16 @@ func (s *service) run(ctx context.Context, deployments []ctypes.IDeployment) {
 		s.updateDeploymentManagerGauge()
 	}
 
+	var sweepch <-chan time.Time
+	if s.config.OrphanSweepPeriod > 0 {
+		ticker := time.NewTicker(s.config.OrphanSweepPeriod)
+		defer ticker.Stop()
+		sweepch = ticker.C
+	}
+
+	sweepDone := make(chan struct{}, 1)
+	sweeping := false
+
 loop:
 	for {
 		select {
@@ -304,10 +327,16 @@ loop:
 					break
 				}
 
+				cparams := reservation.ClusterParams()
+				if settings, valid := cparams.(crd.ClusterSettings); valid {
+					// probes come with every manifest, so update without them removes probes set previously
+					cparams = settings.WithProbes(ev.GroupProbes())
+				}
+
 				deployment := &ctypes.Deployment{
 					Lid:     ev.LeaseID,
 					MGroup:  mgroup,
-					CParams: reservation.ClusterParams(),
+					CParams: cparams,
 				}
 
 				key := ev.LeaseID
@@ -340,9 +369,30 @@ loop:
 			delete(s.managers, dm.deployment.LeaseID())
 		case req := <-s.checkDeploymentExistsRequestCh:
 			s.doCheckDeploymentExists(req)
+		case <-sweepch:
+			if sweeping {
+				break
+			}
+
+			active := make([]mtypes.LeaseID, 0, len(s.managers))
+			for lid := range s.managers {
+				active = append(active, lid)
+			}
+
+			sweeping = true
+			go func() {
+				s.sweepOrphans(ctx, active)
+				sweepDone <- struct{}{}
+			}()
+		case <-sweepDone:
+			sweeping = false
 		}
 		s.updateDeploymentManagerGauge()
 	}
+	if sweeping {
+		<-sweepDone
+	}
+
 	s.log.Debug("draining deployment managers...", "qty", len(s.managers))
 	for _, manager := range s.managers {
 		if manager != nil {
@@ -385,6 +435,19 @@ func (s *service) teardownLease(lid mtypes.LeaseID) {
 	}
 }
 
+// sweepOrphans removes objects of leases which have no deployment manager
+func (s *service) sweepOrphans(ctx context.Context, active []mtypes.LeaseID) {
+	swept, err := s.client.SweepOrphans(util.ApplyToContext(ctx, s.config.ClusterSettings), active, s.config.OrphanSweepMinAge)
+	if err != nil {
+		s.log.Error("sweeping orphaned leases", "err", err)
+		return
+	}
+
+	for _, lid := range swept {
+		s.log.Info("swept orphaned lease", "lease", lid)
+	}
+}
+
 func findDeployments(ctx context.Context, log log.Logger, client Client, _ session.Session) ([]ctypes.IDeployment, error) {
 	deployments, err := client.Deployments(ctx)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/pkg/errors"
@@ -41,6 +42,13 @@ type InventoryStorageStatus struct {
 	Size  int64  `json:"size"`
 }
 
+// InventoryOwnerUsage stores amount of resources reserved for the single owner.
+// Leases counts both active leases and pending reservations
+type InventoryOwnerUsage struct {
+	Leases uint32 `json:"leases"`
+	InventoryMetricTotal
+}
+
 // InventoryStatus stores active, pending and available units
 type InventoryStatus struct {
 	Active    []InventoryMetricTotal `json:"active,omitempty"`
@@ -61,11 +69,23 @@ type InventoryNodeMetric struct {
 
 type GPUAttributes map[string][]string
 
+// StorageClassRAM is the class of non-persistent volume backed by memory.
+// Its size is accounted and priced as memory.
+// SDL parser of akash-network/node v0.23 still rejects class on ephemeral storage,
+// so tenants need SDL tooling which allows it until the dependency is bumped
+const StorageClassRAM = "ram"
+
 type StorageAttributes struct {
 	Persistent bool   `json:"persistent"`
 	Class      string `json:"class,omitempty"`
 }
 
+// IsRAMStorage checks if volume with given attributes is backed by memory
+func IsRAMStorage(attrs types.Attributes) bool {
+	class, _ := attrs.Find(sdl.StorageAttributeClass).AsString()
+	return class == StorageClassRAM
+}
+
 func ParseGPUAttributes(attrs types.Attributes) (GPUAttributes, error) {
 	var nvidia []string
 	var amd []string
@@ -115,6 +135,10 @@ func ParseStorageAttributes(attrs types.Attributes) (StorageAttributes, error) {
 		return StorageAttributes{}, fmt.Errorf("persistent volume must specify storage class") // nolint: goerr113
 	}
 
+	if persistent && class == StorageClassRAM {
+		return StorageAttributes{}, fmt.Errorf("memory backed volume cannot be persistent") // nolint: goerr113
+	}
+
 	res := StorageAttributes{
 		Persistent: persistent,
 		Class:      class,
@@ -142,7 +166,9 @@ func (inv *InventoryMetricTotal) AddResources(res types.Resources) {
 	}
 
 	for _, storage := range res.Resources.Storage {
-		if storageClass, found := storage.Attributes.Find(sdl.StorageAttributeClass).AsString(); !found {
+		if IsRAMStorage(storage.Attributes) {
+			mem = mem.Add(storage.Quantity.Val.MulRaw(int64(res.Count)))
+		} else if storageClass, found := storage.Attributes.Find(sdl.StorageAttributeClass).AsString(); !found {
 			ephemeralStorage = ephemeralStorage.Add(storage.Quantity.Val.MulRaw(int64(res.Count)))
 		} else {
 			val := sdk.NewIntFromUint64(uint64(inv.Storage[storageClass]))
@@ -173,6 +199,7 @@ type InventoryMetrics struct {
 type ServiceStatus struct {
 	Name      string   `json:"name"`
 	Available int32    `json:"available"`
+	Ready     int32    `json:"ready"`
 	Total     int32    `json:"total"`
 	URIs      []string `json:"uris"`
 
@@ -181,6 +208,21 @@ type ServiceStatus struct {
 	UpdatedReplicas    int32 `json:"updated_replicas"`
 	ReadyReplicas      int32 `json:"ready_replicas"`
 	AvailableReplicas  int32 `json:"available_replicas"`
+
+	Generation int64 `json:"generation"`
+	// Progressing is false when controller reports rollout of the latest revision as stalled
+	Progressing bool `json:"progressing"`
+}
+
+// RolledOut returns true when controller has observed the latest spec and count replicas,
+// all of them running the latest revision, are ready and available
+func (s ServiceStatus) RolledOut(count uint32) bool {
+	return s.ObservedGeneration >= s.Generation &&
+		s.Progressing &&
+		uint32(s.Replicas) == count &&
+		uint32(s.UpdatedReplicas) == count &&
+		uint32(s.ReadyReplicas) >= count &&
+		uint32(s.Available) >= count
 }
 
 type ForwardedPortStatus struct {
@@ -191,6 +233,57 @@ type ForwardedPortStatus struct {
 	Name         string                   `json:"name"`
 }
 
+// VolumeSnapshot is the snapshot of single persistent volume of the service.
+// Volumes of the service snapshotted together share the same name
+type VolumeSnapshot struct {
+	Name      string    `json:"name"`
+	Service   string    `json:"service"`
+	Volume    string    `json:"volume"`
+	Ready     bool      `json:"ready"`
+	Size      string    `json:"size,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Error     string    `json:"error,omitempty"`
+}
+
+const (
+	// MetricsSourceAPI marks usage read from metrics.k8s.io
+	MetricsSourceAPI = "metrics-api"
+	// MetricsSourceKubelet marks usage read from kubelet summary, the only source of network counters
+	MetricsSourceKubelet = "kubelet"
+)
+
+// PodMetrics is resource usage of single replica of the service
+type PodMetrics struct {
+	Name string `json:"name"`
+	// CPU is usage in millicores
+	CPU uint64 `json:"cpu"`
+	// Memory is working set in bytes
+	Memory uint64 `json:"memory"`
+	// GPU is number of devices allocated to the pod, their utilization is not reported by kubernetes
+	GPU uint64 `json:"gpu"`
+	// NetworkRx and NetworkTx are bytes received and transmitted since the pod started.
+	// They are nil when metrics source does not report network counters
+	NetworkRx *uint64   `json:"network_rx,omitempty"`
+	NetworkTx *uint64   `json:"network_tx,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ServiceMetrics sums resource usage of all replicas of the service
+type ServiceMetrics struct {
+	CPU       uint64       `json:"cpu"`
+	Memory    uint64       `json:"memory"`
+	GPU       uint64       `json:"gpu"`
+	NetworkRx *uint64      `json:"network_rx,omitempty"`
+	NetworkTx *uint64      `json:"network_tx,omitempty"`
+	Pods      []PodMetrics `json:"pods"`
+}
+
+// LeaseMetrics is snapshot of resource usage of running pods of the lease
+type LeaseMetrics struct {
+	Source   string                     `json:"source"`
+	Services map[string]*ServiceMetrics `json:"services"`
+}
+
 // LeaseStatus includes list of services with their status
 type LeaseStatus struct {
 	Services       map[string]*ServiceStatus        `json:"services"`
@@ -212,9 +305,31 @@ func WithDryRun() InventoryOption {
 
 type Inventory interface {
 	Adjust(ReservationGroup, ...InventoryOption) error
+	// AdjustStorage takes additional space of persistent storage classes, keyed by class name.
+	// Inventory is left unchanged if any of the classes cannot satisfy request
+	AdjustStorage(map[string]types.ResourceValue) error
 	Metrics() InventoryMetrics
 }
 
+// LeaseLogsOptions selects containers of the lease and range of their logs to read
+type LeaseLogsOptions struct {
+	// Services is comma separated list of services, empty means all of them
+	Services string
+	Follow   bool
+	// TailLines limits logs to the last lines, nil reads everything
+	TailLines *int64
+	// SinceSeconds and SinceTime are mutually exclusive
+	SinceSeconds *int64
+	SinceTime    *time.Time
+	// Previous reads logs of the previous instance of restarted containers
+	Previous   bool
+	Timestamps bool
+	// LimitBytes stops reading logs of every container after that many bytes
+	LimitBytes *int64
+	// Replica selects replica of every service by its index, nil reads all of them
+	Replica *uint
+}
+
 // ServiceLog stores name, stream and scanner
 type ServiceLog struct {
 	Name    string
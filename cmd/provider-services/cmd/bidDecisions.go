@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"crypto/tls"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spf13/cobra"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+
+	"github.com/akash-network/node/app"
+	akashclient "github.com/akash-network/node/client"
+	cmdcommon "github.com/akash-network/node/cmd/common"
+	cutils "github.com/akash-network/node/x/cert/utils"
+
+	"github.com/akash-network/provider/bidengine"
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+const flagOwner = "owner"
+
+func bidDecisionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "bid-decisions",
+		Short:        "explain why provider did or did not bid on orders",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doBidDecisions(cmd)
+		},
+	}
+
+	cmd.Flags().String(FlagProvider, "", "provider")
+	cmd.Flags().String(flagOwner, "", "order owner, only provider may query orders of other tenants")
+	cmd.Flags().Uint64(FlagDSeq, 0, "deployment sequence")
+	cmd.Flags().Uint32(FlagGSeq, 0, "group sequence")
+	cmd.Flags().Uint32(FlagOSeq, 0, "order sequence")
+	cmd.Flags().String(flags.FlagHome, app.DefaultHome, "the application home directory")
+	cmd.Flags().String(flags.FlagFrom, "", "name or address of private key with which to sign")
+	cmd.Flags().String(flags.FlagKeyringBackend, flags.DefaultKeyringBackend, "select keyring's backend (os|file|kwallet|pass|test)")
+
+	if err := cmd.MarkFlagRequired(FlagProvider); err != nil {
+		panic(err.Error())
+	}
+
+	if err := cmd.MarkFlagRequired(flags.FlagFrom); err != nil {
+		panic(err.Error())
+	}
+
+	return cmd
+}
+
+func doBidDecisions(cmd *cobra.Command) error {
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	filter := bidengine.DecisionFilter{}
+
+	if filter.Owner, err = cmd.Flags().GetString(flagOwner); err != nil {
+		return err
+	}
+
+	if filter.Owner != "" {
+		if _, err = sdk.AccAddressFromBech32(filter.Owner); err != nil {
+			return err
+		}
+	}
+
+	if filter.DSeq, err = cmd.Flags().GetUint64(FlagDSeq); err != nil {
+		return err
+	}
+
+	if filter.GSeq, err = cmd.Flags().GetUint32(FlagGSeq); err != nil {
+		return err
+	}
+
+	if filter.OSeq, err = cmd.Flags().GetUint32(FlagOSeq); err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(akashclient.NewQueryClientFromCtx(cctx), prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	result, err := gclient.BidDecisions(cmd.Context(), filter)
+	if err != nil {
+		return showErrorToUser(err)
+	}
+
+	return cmdcommon.PrintJSON(cctx, result)
+}
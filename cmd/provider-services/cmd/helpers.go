@@ -20,14 +20,19 @@ import (
 )
 
 const (
-	FlagService  = "service"
-	FlagProvider = "provider"
-	FlagDSeq     = "dseq"
-	FlagGSeq     = "gseq"
-	FlagOSeq     = "oseq"
-	flagOutput   = "output"
-	flagFollow   = "follow"
-	flagTail     = "tail"
+	FlagService    = "service"
+	FlagProvider   = "provider"
+	FlagDSeq       = "dseq"
+	FlagGSeq       = "gseq"
+	FlagOSeq       = "oseq"
+	flagOutput     = "output"
+	flagFollow     = "follow"
+	flagTail       = "tail"
+	flagSince      = "since"
+	flagSinceTime  = "since-time"
+	flagPrevious   = "previous"
+	flagTimestamps = "timestamps"
+	flagLimitBytes = "limit-bytes"
 )
 
 const (
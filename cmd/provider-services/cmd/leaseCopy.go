@@ -0,0 +1,619 @@
+package cmd
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	akashclient "github.com/akash-network/node/client"
+	cutils "github.com/akash-network/node/x/cert/utils"
+	dcli "github.com/akash-network/node/x/deployment/client/cli"
+	mcli "github.com/akash-network/node/x/market/client/cli"
+
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+const (
+	FlagChunkSize = "chunk-size"
+	FlagResume    = "resume"
+	FlagProgress  = "progress"
+
+	defaultCopyChunkSize = 64 << 20
+)
+
+var (
+	errLeaseCopyArgs       = errors.New("exactly one of source and destination must be in form <service>:<absolute path>")
+	errLeaseCopyResume     = errors.New("cannot resume")
+	errLeaseCopyShortChunk = errors.New("remote file changed during transfer")
+)
+
+func leaseCopyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lease-cp <src> <dst>",
+		Short: "copy files and directories to and from containers of the lease",
+		Long: `copy files and directories to and from containers of the lease.
+One of the arguments is path in the container given as <service>:<absolute path>.
+
+  lease-cp ./data web:/var/lib/data    upload directory
+  lease-cp web:/var/log/app.log .      download file into current directory
+
+Files larger than --chunk-size are transferred in chunks, --resume continues interrupted transfer of single file`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE:         doLeaseCopy,
+	}
+
+	addLeaseFlags(cmd)
+
+	cmd.Flags().Uint(FlagReplicaIndex, 0, "replica index to copy from or to")
+	cmd.Flags().Uint64(FlagChunkSize, defaultCopyChunkSize, "size in bytes of single chunk of large file transfer")
+	cmd.Flags().Bool(FlagResume, false, "resume interrupted transfer of single file")
+	cmd.Flags().Bool(FlagProgress, false, "report progress of the transfer on stderr")
+
+	return cmd
+}
+
+// parseLeaseCopyTarget splits <service>:<path> argument. Anything else is local path
+func parseLeaseCopyTarget(arg string) (string, string, bool) {
+	idx := strings.Index(arg, ":")
+	if idx <= 0 || strings.ContainsAny(arg[:idx], `/\`) {
+		return "", arg, false
+	}
+
+	return arg[:idx], arg[idx+1:], true
+}
+
+type leaseCopier struct {
+	ctx       context.Context
+	gclient   gwrest.Client
+	lid       mtypes.LeaseID
+	service   string
+	podIndex  uint
+	chunkSize uint64
+	resume    bool
+	progress  io.Writer
+	stderr    io.Writer
+}
+
+func doLeaseCopy(cmd *cobra.Command, args []string) error {
+	srcService, src, srcRemote := parseLeaseCopyTarget(args[0])
+	dstService, dst, dstRemote := parseLeaseCopyTarget(args[1])
+
+	if srcRemote == dstRemote {
+		return errLeaseCopyArgs
+	}
+
+	remotePath := dst
+	if srcRemote {
+		remotePath = src
+	}
+
+	if !path.IsAbs(remotePath) {
+		return errLeaseCopyArgs
+	}
+
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	bidID, err := mcli.BidIDFromFlags(cmd.Flags(), dcli.WithOwner(cctx.FromAddress))
+	if err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(akashclient.NewQueryClientFromCtx(cctx), prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	cp := &leaseCopier{
+		ctx:     cmd.Context(),
+		gclient: gclient,
+		lid:     bidID.LeaseID(),
+		service: dstService,
+		stderr:  cmd.ErrOrStderr(),
+	}
+
+	if srcRemote {
+		cp.service = srcService
+	}
+
+	if cp.podIndex, err = cmd.Flags().GetUint(FlagReplicaIndex); err != nil {
+		return err
+	}
+
+	if cp.chunkSize, err = cmd.Flags().GetUint64(FlagChunkSize); err != nil {
+		return err
+	}
+
+	if cp.chunkSize == 0 {
+		return fmt.Errorf("%s must be greater than 0", FlagChunkSize)
+	}
+
+	if cp.resume, err = cmd.Flags().GetBool(FlagResume); err != nil {
+		return err
+	}
+
+	progress, err := cmd.Flags().GetBool(FlagProgress)
+	if err != nil {
+		return err
+	}
+
+	if progress {
+		cp.progress = cmd.ErrOrStderr()
+	}
+
+	if srcRemote {
+		err = cp.download(src, dst)
+	} else {
+		err = cp.upload(src, dst)
+	}
+
+	if err != nil {
+		return showErrorToUser(err)
+	}
+
+	return nil
+}
+
+func (cp *leaseCopier) upload(src string, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(dst, "/") {
+		dst = path.Join(dst, filepath.Base(src))
+	}
+	dst = path.Clean(dst)
+
+	if info.Mode().IsRegular() && (cp.resume || uint64(info.Size()) > cp.chunkSize) {
+		return cp.uploadChunked(src, dst, uint64(info.Size()))
+	}
+
+	if cp.resume {
+		return fmt.Errorf("%w: %s is not a regular file", errLeaseCopyResume, src)
+	}
+
+	ctx, cancel := context.WithCancel(cp.ctx)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+
+	archived := make(chan error, 1)
+	go func() {
+		err := writeTar(pw, src, path.Base(dst), cp.stderr)
+		if err != nil {
+			// remote side would wait for the rest of archive forever
+			cancel()
+		}
+		_ = pw.CloseWithError(err)
+		archived <- err
+	}()
+
+	var input io.Reader = pr
+	if cp.progress != nil {
+		bar := newCopyProgress(cp.progress, src, 0)
+		defer bar.done()
+		input = io.TeeReader(pr, bar)
+	}
+
+	err = cp.gclient.LeaseCopy(ctx, cp.lid, gwrest.LeaseCopyRequest{
+		Service:  cp.service,
+		PodIndex: cp.podIndex,
+		Op:       gwrest.LeaseCopyOpUpload,
+		Path:     path.Dir(dst),
+	}, readCloser{Reader: input, Closer: pr}, nil)
+
+	if aerr := <-archived; aerr != nil && !errors.Is(aerr, io.ErrClosedPipe) {
+		return aerr
+	}
+
+	return err
+}
+
+func (cp *leaseCopier) uploadChunked(src string, dst string, size uint64) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var offset uint64
+	if cp.resume {
+		// missing file on remote means there is nothing to resume, start from the beginning
+		if offset, err = cp.gclient.LeaseFileSize(cp.ctx, cp.lid, cp.service, cp.podIndex, dst); err != nil {
+			offset = 0
+		}
+
+		if offset > size {
+			return fmt.Errorf("%w: remote file %s is larger than %s", errLeaseCopyResume, dst, src)
+		}
+	}
+
+	var bar *copyProgress
+	if cp.progress != nil {
+		bar = newCopyProgress(cp.progress, src, size)
+		bar.add(offset)
+		defer bar.done()
+	}
+
+	for first := true; first || offset < size; first = false {
+		length := size - offset
+		if length > cp.chunkSize {
+			length = cp.chunkSize
+		}
+
+		var input io.Reader = io.NewSectionReader(file, int64(offset), int64(length))
+		if bar != nil {
+			input = io.TeeReader(input, bar)
+		}
+
+		err = cp.gclient.LeaseCopy(cp.ctx, cp.lid, gwrest.LeaseCopyRequest{
+			Service:  cp.service,
+			PodIndex: cp.podIndex,
+			Op:       gwrest.LeaseCopyOpUpload,
+			Path:     dst,
+			Raw:      true,
+			Offset:   offset,
+		}, io.NopCloser(input), nil)
+		if err != nil {
+			return fmt.Errorf("%w: uploaded %d of %d bytes, rerun with --%s to continue", err, offset, size, FlagResume)
+		}
+
+		offset += length
+	}
+
+	return nil
+}
+
+func (cp *leaseCopier) download(src string, dst string) error {
+	src = path.Clean(src)
+
+	target := dst
+	if info, err := os.Stat(dst); err == nil && info.IsDir() {
+		target = filepath.Join(dst, path.Base(src))
+	}
+
+	// only regular files have size, anything else is transferred as archive
+	size, err := cp.gclient.LeaseFileSize(cp.ctx, cp.lid, cp.service, cp.podIndex, src)
+	if err == nil && (cp.resume || size > cp.chunkSize) {
+		return cp.downloadChunked(src, target, size)
+	}
+
+	if cp.resume {
+		return fmt.Errorf("%w: %s is not a regular file", errLeaseCopyResume, src)
+	}
+
+	pr, pw := io.Pipe()
+
+	var output io.Writer = pw
+	if cp.progress != nil {
+		bar := newCopyProgress(cp.progress, src, 0)
+		defer bar.done()
+		output = io.MultiWriter(pw, bar)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		err := cp.gclient.LeaseCopy(cp.ctx, cp.lid, gwrest.LeaseCopyRequest{
+			Service:  cp.service,
+			PodIndex: cp.podIndex,
+			Op:       gwrest.LeaseCopyOpDownload,
+			Path:     src,
+		}, nil, output)
+		_ = pw.CloseWithError(err)
+		result <- err
+	}()
+
+	err = readTar(pr, path.Base(src), target, cp.stderr)
+	_ = pr.CloseWithError(err)
+
+	if rerr := <-result; rerr != nil {
+		return rerr
+	}
+
+	return err
+}
+
+func (cp *leaseCopier) downloadChunked(src string, target string, size uint64) error {
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var offset uint64
+	if cp.resume {
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+
+		offset = uint64(info.Size())
+		if offset > size {
+			return fmt.Errorf("%w: local file %s is larger than %s", errLeaseCopyResume, target, src)
+		}
+	}
+
+	// drop anything past the offset, it is left from interrupted transfer
+	if err = file.Truncate(int64(offset)); err != nil {
+		return err
+	}
+
+	if _, err = file.Seek(int64(offset), io.SeekStart); err != nil {
+		return err
+	}
+
+	var bar *copyProgress
+	if cp.progress != nil {
+		bar = newCopyProgress(cp.progress, src, size)
+		bar.add(offset)
+		defer bar.done()
+	}
+
+	for offset < size {
+		length := size - offset
+		if length > cp.chunkSize {
+			length = cp.chunkSize
+		}
+
+		counter := &countingWriter{}
+		output := io.MultiWriter(file, counter)
+		if bar != nil {
+			output = io.MultiWriter(output, bar)
+		}
+
+		err = cp.gclient.LeaseCopy(cp.ctx, cp.lid, gwrest.LeaseCopyRequest{
+			Service:  cp.service,
+			PodIndex: cp.podIndex,
+			Op:       gwrest.LeaseCopyOpDownload,
+			Path:     src,
+			Raw:      true,
+			Offset:   offset,
+			Length:   length,
+		}, nil, output)
+
+		offset += counter.n
+
+		if err == nil && counter.n != length {
+			err = errLeaseCopyShortChunk
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: downloaded %d of %d bytes, rerun with --%s to continue", err, offset, size, FlagResume)
+		}
+	}
+
+	return nil
+}
+
+// writeTar archives src under the name, so it is extracted as name regardless of the local base name.
+// Skipped files are reported to stderr
+func writeTar(w io.Writer, src string, name string, stderr io.Writer) error {
+	tw := tar.NewWriter(w)
+
+	err := filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+
+		entry := path.Join(name, filepath.ToSlash(rel))
+
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			fmt.Fprintf(stderr, "skipping %s: not a regular file or directory\n", file)
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = entry
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+
+		if err = tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// readTar extracts archive of the remote path named prefix into the target.
+// Entries escaping the target and anything but regular files and directories are not extracted,
+// the latter are reported to stderr
+func readTar(r io.Reader, prefix string, target string, stderr io.Writer) error {
+	target = filepath.Clean(target)
+	tr := tar.NewReader(r)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean(hdr.Name)
+
+		var rel string
+		switch {
+		case prefix == ".":
+			rel = name
+		case name == prefix:
+			rel = ""
+		case strings.HasPrefix(name, prefix+"/"):
+			rel = strings.TrimPrefix(name, prefix+"/")
+		default:
+			return fmt.Errorf("unexpected archive entry %q", hdr.Name)
+		}
+
+		out := filepath.Join(target, filepath.FromSlash(rel))
+		if out != target && !strings.HasPrefix(out, target+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %q is outside of destination", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(out, hdr.FileInfo().Mode().Perm()|0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err = extractFile(tr, out, hdr.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		default:
+			fmt.Fprintf(stderr, "skipping %s: not a regular file or directory\n", hdr.Name)
+		}
+	}
+}
+
+func extractFile(r io.Reader, out string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(out, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type countingWriter struct {
+	n uint64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += uint64(len(p))
+	return len(p), nil
+}
+
+// copyProgress prints transferred amount at most once per progressInterval
+type copyProgress struct {
+	out     io.Writer
+	name    string
+	total   uint64
+	current uint64
+	printed time.Time
+}
+
+const progressInterval = 500 * time.Millisecond
+
+func newCopyProgress(out io.Writer, name string, total uint64) *copyProgress {
+	return &copyProgress{
+		out:   out,
+		name:  name,
+		total: total,
+	}
+}
+
+func (p *copyProgress) Write(data []byte) (int, error) {
+	p.add(uint64(len(data)))
+	return len(data), nil
+}
+
+func (p *copyProgress) add(n uint64) {
+	p.current += n
+
+	if time.Since(p.printed) >= progressInterval {
+		p.print()
+	}
+}
+
+func (p *copyProgress) print() {
+	p.printed = time.Now()
+
+	if p.total == 0 {
+		fmt.Fprintf(p.out, "\r%s: %s", p.name, formatCopySize(p.current))
+		return
+	}
+
+	fmt.Fprintf(p.out, "\r%s: %s / %s (%d%%)", p.name, formatCopySize(p.current), formatCopySize(p.total), p.current*100/p.total)
+}
+
+func (p *copyProgress) done() {
+	p.print()
+	fmt.Fprintln(p.out)
+}
+
+func formatCopySize(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+
+	div, exp := uint64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
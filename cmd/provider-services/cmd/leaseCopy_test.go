@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLeaseCopyTarget(t *testing.T) {
+	service, p, remote := parseLeaseCopyTarget("web:/var/lib/data")
+	require.True(t, remote)
+	require.Equal(t, "web", service)
+	require.Equal(t, "/var/lib/data", p)
+
+	_, p, remote = parseLeaseCopyTarget("./data:backup")
+	require.False(t, remote)
+	require.Equal(t, "./data:backup", p)
+
+	_, _, remote = parseLeaseCopyTarget(":/data")
+	require.False(t, remote)
+}
+
+func TestLeaseCopyTarRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "nested"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "nested", "file.bin"), []byte("binary\x00data"), 0600))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, writeTar(buf, src, "data", io.Discard))
+
+	dst := filepath.Join(t.TempDir(), "restored")
+	require.NoError(t, readTar(buf, "data", dst, io.Discard))
+
+	data, err := os.ReadFile(filepath.Join(dst, "nested", "file.bin"))
+	require.NoError(t, err)
+	require.Equal(t, "binary\x00data", string(data))
+
+	info, err := os.Stat(filepath.Join(dst, "nested", "file.bin"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestLeaseCopyTarReportsSkippedFiles(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "file"), []byte("data"), 0600))
+	require.NoError(t, os.Symlink("file", filepath.Join(src, "link")))
+
+	stderr := &bytes.Buffer{}
+	require.NoError(t, writeTar(&bytes.Buffer{}, src, "data", stderr))
+	require.Contains(t, stderr.String(), "skipping "+filepath.Join(src, "link"))
+}
+
+func TestLeaseCopyTarRejectsEscapingEntries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "data/../../evil", Typeflag: tar.TypeReg, Mode: 0644}))
+	require.NoError(t, tw.Close())
+
+	dst := t.TempDir()
+	require.Error(t, readTar(buf, "data", filepath.Join(dst, "out"), io.Discard))
+
+	_, err := os.Stat(filepath.Join(dst, "evil"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestFormatCopySize(t *testing.T) {
+	require.Equal(t, "512B", formatCopySize(512))
+	require.Equal(t, "1.5KiB", formatCopySize(1536))
+	require.Equal(t, "64.0MiB", formatCopySize(defaultCopyChunkSize))
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"sync"
+	"time"
 
 	sdkclient "github.com/cosmos/cosmos-sdk/client"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -16,6 +17,7 @@ import (
 	cmdcommon "github.com/akash-network/node/cmd/common"
 	cutils "github.com/akash-network/node/x/cert/utils"
 
+	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	gwrest "github.com/akash-network/provider/gateway/rest"
 )
 
@@ -35,6 +37,12 @@ func leaseLogsCmd() *cobra.Command {
 	cmd.Flags().BoolP(flagFollow, "f", false, "Specify if the logs should be streamed. Defaults to false")
 	cmd.Flags().Int64P(flagTail, "t", -1, "The number of lines from the end of the logs to show. Defaults to -1")
 	cmd.Flags().StringP(flagOutput, "o", outputText, "Output format text|json. Defaults to text")
+	cmd.Flags().Duration(flagSince, 0, "Only return logs newer than a relative duration like 5s, 2m, or 3h. Defaults to all logs")
+	cmd.Flags().String(flagSinceTime, "", "Only return logs after a specific date (RFC3339). Only one of since-time / since may be used")
+	cmd.Flags().Bool(flagPrevious, false, "Return logs of the previous instance of restarted containers")
+	cmd.Flags().Bool(flagTimestamps, false, "Include timestamps on each line in the log output")
+	cmd.Flags().Int64(flagLimitBytes, 0, "Maximum bytes of logs to return for every container. Defaults to no limit")
+	cmd.Flags().Int(FlagReplicaIndex, -1, "Only return logs of the replica with this index. Defaults to all replicas")
 
 	return cmd
 }
@@ -91,6 +99,18 @@ func doLeaseLogs(cmd *cobra.Command) error {
 		return errors.Errorf("tail flag supplied with invalid value. must be >= -1")
 	}
 
+	opts, err := leaseLogsOptionsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts.Services = svcs
+	opts.Follow = follow
+
+	if tailLines > -1 {
+		opts.TailLines = &tailLines
+	}
+
 	type result struct {
 		lid    mtypes.LeaseID
 		error  error
@@ -106,7 +126,7 @@ func doLeaseLogs(cmd *cobra.Command) error {
 		prov, _ := sdk.AccAddressFromBech32(lid.Provider)
 		gclient, err := gwrest.NewClient(akashclient.NewQueryClientFromCtx(cctx), prov, []tls.Certificate{cert})
 		if err == nil {
-			stream.stream, stream.error = gclient.LeaseLogs(ctx, lid, svcs, follow, tailLines)
+			stream.stream, stream.error = gclient.LeaseLogs(ctx, lid, opts)
 		} else {
 			stream.error = err
 		}
@@ -162,3 +182,75 @@ func doLeaseLogs(cmd *cobra.Command) error {
 
 	return nil
 }
+
+func leaseLogsOptionsFromFlags(cmd *cobra.Command) (cltypes.LeaseLogsOptions, error) {
+	opts := cltypes.LeaseLogsOptions{}
+
+	since, err := cmd.Flags().GetDuration(flagSince)
+	if err != nil {
+		return opts, err
+	}
+
+	sinceTime, err := cmd.Flags().GetString(flagSinceTime)
+	if err != nil {
+		return opts, err
+	}
+
+	if since < 0 {
+		return opts, errors.Errorf("since flag supplied with invalid value. must be positive")
+	}
+
+	if since > 0 && sinceTime != "" {
+		return opts, errors.Errorf("only one of since and since-time flags may be used")
+	}
+
+	if since > 0 {
+		seconds := int64((since + time.Second - 1) / time.Second)
+		opts.SinceSeconds = &seconds
+	}
+
+	if sinceTime != "" {
+		tm, err := time.Parse(time.RFC3339, sinceTime)
+		if err != nil {
+			return opts, errors.Wrap(err, "since-time flag supplied with invalid value")
+		}
+		opts.SinceTime = &tm
+	}
+
+	if opts.Previous, err = cmd.Flags().GetBool(flagPrevious); err != nil {
+		return opts, err
+	}
+
+	if opts.Timestamps, err = cmd.Flags().GetBool(flagTimestamps); err != nil {
+		return opts, err
+	}
+
+	limitBytes, err := cmd.Flags().GetInt64(flagLimitBytes)
+	if err != nil {
+		return opts, err
+	}
+
+	if limitBytes < 0 {
+		return opts, errors.Errorf("limit-bytes flag supplied with invalid value. must be >= 0")
+	}
+
+	if limitBytes > 0 {
+		opts.LimitBytes = &limitBytes
+	}
+
+	replica, err := cmd.Flags().GetInt(FlagReplicaIndex)
+	if err != nil {
+		return opts, err
+	}
+
+	if replica < -1 {
+		return opts, errors.Errorf("replica-index flag supplied with invalid value. must be >= -1")
+	}
+
+	if replica > -1 {
+		idx := uint(replica)
+		opts.Replica = &idx
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+
+	akashclient "github.com/akash-network/node/client"
+	cmdcommon "github.com/akash-network/node/cmd/common"
+	cutils "github.com/akash-network/node/x/cert/utils"
+	dcli "github.com/akash-network/node/x/deployment/client/cli"
+	mcli "github.com/akash-network/node/x/market/client/cli"
+
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+const (
+	flagInterval = "interval"
+
+	outputPrometheus = "prometheus"
+)
+
+var (
+	errLeaseMetricsInvalidOutput = errors.New("invalid output format, expected json|prometheus")
+	errLeaseMetricsFollowOutput  = errors.New("prometheus output can not be streamed")
+)
+
+func leaseMetricsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "lease-metrics",
+		Short:        "get resource usage of the lease",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doLeaseMetrics(cmd)
+		},
+	}
+
+	addLeaseFlags(cmd)
+
+	cmd.Flags().StringP(flagOutput, "o", outputJSON, "output format json|prometheus. default json")
+	cmd.Flags().BoolP(flagFollow, "f", false, "stream metrics until interrupted")
+	cmd.Flags().Duration(flagInterval, 10*time.Second, "interval between metrics when streaming")
+
+	return cmd
+}
+
+func doLeaseMetrics(cmd *cobra.Command) error {
+	output, err := cmd.Flags().GetString(flagOutput)
+	if err != nil {
+		return err
+	}
+
+	if output != outputJSON && output != outputPrometheus {
+		return fmt.Errorf("%w: %q", errLeaseMetricsInvalidOutput, output)
+	}
+
+	follow, err := cmd.Flags().GetBool(flagFollow)
+	if err != nil {
+		return err
+	}
+
+	if follow && output == outputPrometheus {
+		return errLeaseMetricsFollowOutput
+	}
+
+	interval, err := cmd.Flags().GetDuration(flagInterval)
+	if err != nil {
+		return err
+	}
+
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	bid, err := mcli.BidIDFromFlags(cmd.Flags(), dcli.WithOwner(cctx.FromAddress))
+	if err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(akashclient.NewQueryClientFromCtx(cctx), prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	if output == outputPrometheus {
+		data, err := gclient.LeaseMetricsPrometheus(cmd.Context(), bid.LeaseID())
+		if err != nil {
+			return showErrorToUser(err)
+		}
+
+		return cctx.PrintString(string(data))
+	}
+
+	if !follow {
+		result, err := gclient.LeaseMetrics(cmd.Context(), bid.LeaseID())
+		if err != nil {
+			return showErrorToUser(err)
+		}
+
+		return cmdcommon.PrintJSON(cctx, result)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGHUP)
+	defer stop()
+
+	stream, err := gclient.LeaseMetricsStream(ctx, bid.LeaseID(), interval)
+	if err != nil {
+		return showErrorToUser(err)
+	}
+
+	for metrics := range stream.Stream {
+		if err = cmdcommon.PrintJSON(cctx, metrics); err != nil {
+			return err
+		}
+	}
+
+	if msg := <-stream.OnClose; msg != "" {
+		return fmt.Errorf("metrics stream closed: %s", msg) // nolint: goerr113
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	akashclient "github.com/akash-network/node/client"
+	cutils "github.com/akash-network/node/x/cert/utils"
+	dcli "github.com/akash-network/node/x/deployment/client/cli"
+	mcli "github.com/akash-network/node/x/market/client/cli"
+
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+const (
+	FlagAddress = "address"
+)
+
+var (
+	errInvalidPortMapping = errors.New("port mapping must be in form [local port:]remote port")
+)
+
+type portMapping struct {
+	local  uint16
+	remote uint16
+}
+
+func leasePortForwardCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lease-port-forward <service> <[local port:]remote port>...",
+		Short: "forward local ports to tcp ports of the service",
+		Long: `forward local ports to tcp ports of the service. Port does not have to be exposed globally,
+it only has to be declared in expose section of the service.
+
+  lease-port-forward db 5432           listen on 5432 locally
+  lease-port-forward db 15432:5432     listen on 15432 locally
+  lease-port-forward db :5432          listen on random local port`,
+		Args:         cobra.MinimumNArgs(2),
+		SilenceUsage: true,
+		RunE:         doLeasePortForward,
+	}
+
+	addLeaseFlags(cmd)
+
+	cmd.Flags().Uint(FlagReplicaIndex, 0, "replica index to forward to")
+	cmd.Flags().String(FlagAddress, "127.0.0.1", "local address to listen on")
+
+	return cmd
+}
+
+func parsePortMapping(arg string) (portMapping, error) {
+	localStr, remoteStr := "", arg
+	if idx := strings.Index(arg, ":"); idx >= 0 {
+		localStr, remoteStr = arg[:idx], arg[idx+1:]
+	}
+
+	remote, err := strconv.ParseUint(remoteStr, 10, 16)
+	if err != nil || remote == 0 {
+		return portMapping{}, fmt.Errorf("%w: %q", errInvalidPortMapping, arg)
+	}
+
+	mapping := portMapping{
+		local:  uint16(remote),
+		remote: uint16(remote),
+	}
+
+	if strings.Contains(arg, ":") {
+		mapping.local = 0
+
+		if localStr != "" {
+			local, err := strconv.ParseUint(localStr, 10, 16)
+			if err != nil {
+				return portMapping{}, fmt.Errorf("%w: %q", errInvalidPortMapping, arg)
+			}
+			mapping.local = uint16(local)
+		}
+	}
+
+	return mapping, nil
+}
+
+func doLeasePortForward(cmd *cobra.Command, args []string) error {
+	service := args[0]
+
+	mappings := make([]portMapping, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		mapping, err := parsePortMapping(arg)
+		if err != nil {
+			return err
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	podIndex, err := cmd.Flags().GetUint(FlagReplicaIndex)
+	if err != nil {
+		return err
+	}
+
+	address, err := cmd.Flags().GetString(FlagAddress)
+	if err != nil {
+		return err
+	}
+
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	bidID, err := mcli.BidIDFromFlags(cmd.Flags(), dcli.WithOwner(cctx.FromAddress))
+	if err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(akashclient.NewQueryClientFromCtx(cctx), prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGHUP)
+	defer stop()
+
+	listeners := make([]net.Listener, 0, len(mappings))
+	defer func() {
+		for _, listener := range listeners {
+			_ = listener.Close()
+		}
+	}()
+
+	for _, mapping := range mappings {
+		listener, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(int(mapping.local))))
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, listener)
+
+		_ = cctx.PrintString(fmt.Sprintf("Forwarding from %s -> %d\n", listener.Addr(), mapping.remote))
+	}
+
+	wg := &sync.WaitGroup{}
+	for idx, listener := range listeners {
+		wg.Add(1)
+		go func(listener net.Listener, remote uint16) {
+			defer wg.Done()
+			acceptPortForward(ctx, cmd, gclient, bidID.LeaseID(), service, podIndex, listener, remote)
+		}(listener, mappings[idx].remote)
+	}
+
+	<-ctx.Done()
+
+	for _, listener := range listeners {
+		_ = listener.Close()
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+// acceptPortForward tunnels every accepted local connection through separate websocket until listener is closed
+func acceptPortForward(ctx context.Context, cmd *cobra.Command, gclient gwrest.Client, lid mtypes.LeaseID, service string, podIndex uint, listener net.Listener, remote uint16) {
+	conns := &sync.WaitGroup{}
+	defer conns.Wait()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				cmd.PrintErrf("accepting connection on %s: %v\n", listener.Addr(), err)
+			}
+			return
+		}
+
+		conns.Add(1)
+		go func() {
+			defer conns.Done()
+			defer func() {
+				_ = conn.Close()
+			}()
+
+			cmd.PrintErrf("Handling connection for %d\n", remote)
+
+			err := gclient.LeasePortForward(ctx, lid, service, podIndex, uint32(remote), conn, conn)
+			if err != nil && ctx.Err() == nil {
+				cmd.PrintErrf("forwarding connection for %d: %v\n", remote, err)
+			}
+		}()
+	}
+}
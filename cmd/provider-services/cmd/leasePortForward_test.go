@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortMapping(t *testing.T) {
+	mapping, err := parsePortMapping("5432")
+	require.NoError(t, err)
+	require.Equal(t, portMapping{local: 5432, remote: 5432}, mapping)
+
+	mapping, err = parsePortMapping("15432:5432")
+	require.NoError(t, err)
+	require.Equal(t, portMapping{local: 15432, remote: 5432}, mapping)
+
+	mapping, err = parsePortMapping(":5432")
+	require.NoError(t, err)
+	require.Equal(t, portMapping{local: 0, remote: 5432}, mapping)
+
+	for _, arg := range []string{"", "0", "5432:", "db:5432", "70000", "1:2:3"} {
+		_, err = parsePortMapping(arg)
+		require.ErrorIs(t, err, errInvalidPortMapping, arg)
+	}
+}
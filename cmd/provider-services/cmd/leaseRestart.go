@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+
+	akashclient "github.com/akash-network/node/client"
+	cutils "github.com/akash-network/node/x/cert/utils"
+	dcli "github.com/akash-network/node/x/deployment/client/cli"
+	mcli "github.com/akash-network/node/x/market/client/cli"
+
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+func leaseRestartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lease-restart <service>",
+		Short: "restart replicas of the service",
+		Long: `restart replicas of the service. All replicas are replaced one by one the same way
+they are on manifest update, unless single replica is selected with --replica-index`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         doLeaseRestart,
+	}
+
+	addLeaseFlags(cmd)
+
+	cmd.Flags().Uint(FlagReplicaIndex, 0, "restart only replica with this index")
+
+	return cmd
+}
+
+func doLeaseRestart(cmd *cobra.Command, args []string) error {
+	service := args[0]
+
+	var podIndex *uint
+	if cmd.Flags().Changed(FlagReplicaIndex) {
+		idx, err := cmd.Flags().GetUint(FlagReplicaIndex)
+		if err != nil {
+			return err
+		}
+		podIndex = &idx
+	}
+
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	bid, err := mcli.BidIDFromFlags(cmd.Flags(), dcli.WithOwner(cctx.FromAddress))
+	if err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(akashclient.NewQueryClientFromCtx(cctx), prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	if err = gclient.RestartService(cmd.Context(), bid.LeaseID(), service, podIndex); err != nil {
+		return showErrorToUser(err)
+	}
+
+	if podIndex != nil {
+		return cctx.PrintString(fmt.Sprintf("replica %d of service %q restarted\n", *podIndex, service))
+	}
+
+	return cctx.PrintString(fmt.Sprintf("service %q restarted\n", service))
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"crypto/tls"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	akashclient "github.com/akash-network/node/client"
+	cmdcommon "github.com/akash-network/node/cmd/common"
+	cutils "github.com/akash-network/node/x/cert/utils"
+	dcli "github.com/akash-network/node/x/deployment/client/cli"
+	mcli "github.com/akash-network/node/x/market/client/cli"
+
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+func leaseSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lease-snapshot",
+		Short: "manage snapshots of persistent volumes of the lease",
+	}
+
+	cmd.AddCommand(leaseSnapshotCreateCmd())
+	cmd.AddCommand(leaseSnapshotListCmd())
+	cmd.AddCommand(leaseSnapshotRestoreCmd())
+
+	return cmd
+}
+
+func leaseSnapshotCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "create",
+		Short:        "snapshot all persistent volumes of the service",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doLeaseSnapshot(cmd, func(cmd *cobra.Command, gclient gwrest.Client, lid mtypes.LeaseID, service string) (interface{}, error) {
+				return gclient.CreateSnapshot(cmd.Context(), lid, service)
+			})
+		},
+	}
+
+	addServiceFlags(cmd)
+	if err := cmd.MarkFlagRequired(FlagService); err != nil {
+		panic(err.Error())
+	}
+
+	return cmd
+}
+
+func leaseSnapshotListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "list volume snapshots of the lease, optionally filtered by service",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doLeaseSnapshot(cmd, func(cmd *cobra.Command, gclient gwrest.Client, lid mtypes.LeaseID, service string) (interface{}, error) {
+				return gclient.LeaseSnapshots(cmd.Context(), lid, service)
+			})
+		},
+	}
+
+	addServiceFlags(cmd)
+
+	return cmd
+}
+
+func leaseSnapshotRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "restore [snapshot]",
+		Short:        "replace persistent volumes of the service with the snapshot. Data written after snapshot is lost",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doLeaseSnapshot(cmd, func(cmd *cobra.Command, gclient gwrest.Client, lid mtypes.LeaseID, service string) (interface{}, error) {
+				return nil, gclient.RestoreSnapshot(cmd.Context(), lid, service, args[0])
+			})
+		},
+	}
+
+	addServiceFlags(cmd)
+	if err := cmd.MarkFlagRequired(FlagService); err != nil {
+		panic(err.Error())
+	}
+
+	return cmd
+}
+
+type leaseSnapshotFn func(cmd *cobra.Command, gclient gwrest.Client, lid mtypes.LeaseID, service string) (interface{}, error)
+
+func doLeaseSnapshot(cmd *cobra.Command, fn leaseSnapshotFn) error {
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	svcName, err := cmd.Flags().GetString(FlagService)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	bid, err := mcli.BidIDFromFlags(cmd.Flags(), dcli.WithOwner(cctx.FromAddress))
+	if err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(akashclient.NewQueryClientFromCtx(cctx), prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	result, err := fn(cmd, gclient, bid.LeaseID(), svcName)
+	if err != nil {
+		return showErrorToUser(err)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return cmdcommon.PrintJSON(cctx, result)
+}
@@ -0,0 +1,358 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	"github.com/akash-network/node/app"
+	"github.com/akash-network/node/sdl"
+
+	"github.com/akash-network/provider"
+	"github.com/akash-network/provider/bidengine"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+const (
+	flagPricingSDL           = "sdl"
+	flagPricingOrders        = "orders"
+	flagPricingOwner         = "owner"
+	flagPricingClusterStatus = "cluster-status"
+)
+
+var (
+	errPricingNoGroups          = errors.New("no group specs to simulate, provide --sdl and/or --orders")
+	errPricingNoClusterStatus   = errors.New("cluster status is not available, provide --cluster-status")
+	errPricingInvalidOutputType = errors.New("invalid output format")
+)
+
+// bidPricingFlags lists flags shared by all commands constructing bid pricing strategy
+var bidPricingFlags = []string{
+	FlagBidPricingStrategy,
+	FlagBidPriceCPUScale,
+	FlagBidPriceMemoryScale,
+	FlagBidPriceGPUScale,
+	FlagBidPriceStorageScale,
+	FlagBidPriceEndpointScale,
+	FlagBidPriceIPScale,
+	FlagBidPriceScriptPath,
+	FlagBidPriceScriptProcessLimit,
+	FlagBidPriceScriptTimeout,
+}
+
+func addBidPricingFlags(flags *pflag.FlagSet) {
+	flags.String(FlagBidPricingStrategy, "scale", "Pricing strategy to use")
+	flags.String(FlagBidPriceCPUScale, "0", "cpu pricing scale in uakt per millicpu")
+	flags.String(FlagBidPriceMemoryScale, "0", "memory pricing scale in uakt per megabyte")
+	flags.String(FlagBidPriceGPUScale, "0", "gpu pricing scale in uakt per unit. accepts <vendor>/<model>[/<ram>]=<scale> pairs separated by comma, value without key applies to any gpu")
+	flags.String(FlagBidPriceStorageScale, "0", "storage pricing scale in uakt per megabyte")
+	flags.String(FlagBidPriceEndpointScale, "0", "endpoint pricing scale in uakt")
+	flags.String(FlagBidPriceIPScale, "0", "leased ip pricing scale in uakt")
+	flags.String(FlagBidPriceScriptPath, "", "path to script to run for computing bid price")
+	flags.Uint(FlagBidPriceScriptProcessLimit, 32, "limit to the number of scripts run concurrently for bid pricing")
+	flags.Duration(FlagBidPriceScriptTimeout, time.Second*10, "execution timelimit for bid pricing as a duration")
+}
+
+func bindBidPricingFlags(flags *pflag.FlagSet) error {
+	for _, name := range bidPricingFlags {
+		if err := viper.BindPFlag(name, flags.Lookup(name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func pricingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pricing",
+		Short: "Bid pricing tools",
+	}
+
+	cmd.AddCommand(pricingSimulateCmd())
+
+	return cmd
+}
+
+func pricingSimulateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "simulate",
+		Args:         cobra.NoArgs,
+		Short:        "Compute bid price for group specs with configured pricing strategy without connecting to the network",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			switch cmd.Flag(flagOutput).Value.String() {
+			case outputText, outputJSON, outputYAML:
+			default:
+				return errors.Wrapf(errPricingInvalidOutputType, "%q, expected text|json|yaml", cmd.Flag(flagOutput).Value.String())
+			}
+
+			// bind at execution, so run command bindings stay intact
+			return bindBidPricingFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return doPricingSimulate(cmd)
+		},
+	}
+
+	addBidPricingFlags(cmd.Flags())
+
+	cmd.Flags().StringSlice(flagPricingSDL, nil, "path to SDL file to read group specs from. may be repeated")
+	cmd.Flags().StringSlice(flagPricingOrders, nil, "path to JSON dump of orders as printed by \"query market orders -o json\". may be repeated")
+	cmd.Flags().String(flagPricingOwner, "", "owner address passed to pricing strategy for group specs read from SDL")
+	cmd.Flags().String(FlagProviderConfig, "", "provider configuration file path to read pricing pipeline from")
+	cmd.Flags().String(flagPricingClusterStatus, "", "path to JSON output of \"status\" command used by utilization based pricing modifiers")
+	cmd.Flags().StringP(flagOutput, "o", outputText, "output format text|json|yaml. default text")
+
+	return cmd
+}
+
+// pricingSimulation is the group spec being priced along with where it came from
+type pricingSimulation struct {
+	source string
+	owner  string
+	gspec  *dtypes.GroupSpec
+}
+
+type pricingSimulationResult struct {
+	Source   string       `json:"source" yaml:"source"`
+	Owner    string       `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Group    string       `json:"group" yaml:"group"`
+	Price    *sdk.DecCoin `json:"price,omitempty" yaml:"price,omitempty"`
+	MaxPrice sdk.DecCoin  `json:"max_price" yaml:"max_price"`
+	Bid      bool         `json:"bid" yaml:"bid"`
+	Reason   string       `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Message  string       `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// staticClusterStatus serves cluster status loaded from the file
+type staticClusterStatus struct {
+	status *ctypes.Status
+}
+
+var _ bidengine.ClusterStatusClient = (*staticClusterStatus)(nil)
+
+func (s staticClusterStatus) Status(_ context.Context) (*ctypes.Status, error) {
+	if s.status == nil {
+		return nil, errPricingNoClusterStatus
+	}
+
+	return s.status, nil
+}
+
+func readClusterStatus(path string) (staticClusterStatus, error) {
+	if path == "" {
+		return staticClusterStatus{}, nil
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return staticClusterStatus{}, err
+	}
+
+	var status provider.Status
+	if err = json.Unmarshal(buf, &status); err != nil {
+		return staticClusterStatus{}, err
+	}
+
+	return staticClusterStatus{status: status.Cluster}, nil
+}
+
+func readSDLGroups(path string, owner string) ([]pricingSimulation, error) {
+	obj, err := sdl.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := obj.DeploymentGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]pricingSimulation, 0, len(groups))
+	for _, gspec := range groups {
+		res = append(res, pricingSimulation{
+			source: path,
+			owner:  owner,
+			gspec:  gspec,
+		})
+	}
+
+	return res, nil
+}
+
+func parseOrderGroups(source string, buf []byte) ([]pricingSimulation, error) {
+	var orders mtypes.QueryOrdersResponse
+
+	if err := app.MakeEncodingConfig().Marshaler.UnmarshalJSON(buf, &orders); err != nil {
+		return nil, err
+	}
+
+	res := make([]pricingSimulation, 0, len(orders.Orders))
+	for i := range orders.Orders {
+		order := &orders.Orders[i]
+		res = append(res, pricingSimulation{
+			source: fmt.Sprintf("%s:%s", source, order.OrderID),
+			owner:  order.OrderID.Owner,
+			gspec:  &order.Spec,
+		})
+	}
+
+	return res, nil
+}
+
+func readOrderGroups(path string) ([]pricingSimulation, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseOrderGroups(path, buf)
+}
+
+// simulateBid prices the group spec and checks the price the same way bid engine does before bidding
+func simulateBid(ctx context.Context, pricing bidengine.BidPricingStrategy, sim pricingSimulation) pricingSimulationResult {
+	res := pricingSimulationResult{
+		Source:   sim.source,
+		Owner:    sim.owner,
+		Group:    sim.gspec.Name,
+		MaxPrice: sim.gspec.Price(),
+	}
+
+	price, err := pricing.CalculatePrice(ctx, bidengine.Request{
+		Owner: sim.owner,
+		GSpec: sim.gspec,
+	})
+	if err != nil {
+		res.Reason = bidengine.ReasonPricingFailed
+		res.Message = err.Error()
+		return res
+	}
+
+	res.Price = &price
+
+	switch {
+	case res.MaxPrice.GetDenom() != price.GetDenom():
+		res.Reason = bidengine.ReasonUnsupportedDenom
+		res.Message = fmt.Sprintf("calculated price denom %q does not match max price denom %q", price.GetDenom(), res.MaxPrice.GetDenom())
+	case res.MaxPrice.IsLT(price):
+		res.Reason = bidengine.ReasonPriceTooHigh
+		res.Message = fmt.Sprintf("calculated price %s exceeds max price %s", price.String(), res.MaxPrice.String())
+	default:
+		res.Bid = true
+	}
+
+	return res
+}
+
+func doPricingSimulate(cmd *cobra.Command) error {
+	sims := make([]pricingSimulation, 0)
+
+	sdlPaths, err := cmd.Flags().GetStringSlice(flagPricingSDL)
+	if err != nil {
+		return err
+	}
+
+	owner, err := cmd.Flags().GetString(flagPricingOwner)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range sdlPaths {
+		groups, err := readSDLGroups(path, owner)
+		if err != nil {
+			return errors.Wrapf(err, "read SDL %q", path)
+		}
+		sims = append(sims, groups...)
+	}
+
+	orderPaths, err := cmd.Flags().GetStringSlice(flagPricingOrders)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range orderPaths {
+		groups, err := readOrderGroups(path)
+		if err != nil {
+			return errors.Wrapf(err, "read orders %q", path)
+		}
+		sims = append(sims, groups...)
+	}
+
+	if len(sims) == 0 {
+		return errPricingNoGroups
+	}
+
+	pricing, err := createBidPricingStrategy(viper.GetString(FlagBidPricingStrategy))
+	if err != nil {
+		return err
+	}
+
+	if path := cmd.Flag(FlagProviderConfig).Value.String(); path != "" {
+		fConf, err := readProviderConfigPath(path)
+		if err != nil {
+			return err
+		}
+
+		status, err := readClusterStatus(cmd.Flag(flagPricingClusterStatus).Value.String())
+		if err != nil {
+			return errors.Wrap(err, "read cluster status")
+		}
+
+		if pricing, err = bidengine.MakePricingPipelineFromConfig(pricing, fConf.Pricing, status); err != nil {
+			return err
+		}
+	}
+
+	results := make([]pricingSimulationResult, 0, len(sims))
+	for _, sim := range sims {
+		results = append(results, simulateBid(cmd.Context(), pricing, sim))
+	}
+
+	buf := &bytes.Buffer{}
+
+	switch cmd.Flag(flagOutput).Value.String() {
+	case outputText:
+		for _, res := range results {
+			_, _ = fmt.Fprintf(buf, "group: %s\n\tsource:    %s\n", res.Group, res.Source)
+			if res.Owner != "" {
+				_, _ = fmt.Fprintf(buf, "\towner:     %s\n", res.Owner)
+			}
+			if res.Price != nil {
+				_, _ = fmt.Fprintf(buf, "\tprice:     %s\n", res.Price)
+			}
+			_, _ = fmt.Fprintf(buf, "\tmax-price: %s\n\tbid:       %t\n", res.MaxPrice.String(), res.Bid)
+			if res.Reason != "" {
+				_, _ = fmt.Fprintf(buf, "\treason:    %s\n", res.Reason)
+			}
+			if res.Message != "" {
+				_, _ = fmt.Fprintf(buf, "\tmessage:   %s\n", res.Message)
+			}
+		}
+	case outputJSON:
+		err = json.NewEncoder(buf).Encode(results)
+	case outputYAML:
+		err = yaml.NewEncoder(buf).Encode(results)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(cmd.OutOrStdout(), buf.String())
+
+	return err
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	"github.com/akash-network/node/app"
+	"github.com/akash-network/node/testutil"
+
+	"github.com/akash-network/provider/bidengine"
+)
+
+type fixedPricing sdk.DecCoin
+
+func (fp fixedPricing) CalculatePrice(_ context.Context, _ bidengine.Request) (sdk.DecCoin, error) {
+	return sdk.DecCoin(fp), nil
+}
+
+func Test_PricingSimulateOrders(t *testing.T) {
+	order := mtypes.Order{
+		OrderID: testutil.OrderID(t),
+		State:   mtypes.OrderOpen,
+		Spec:    testutil.GroupSpec(t),
+	}
+
+	buf, err := app.MakeEncodingConfig().Marshaler.MarshalJSON(&mtypes.QueryOrdersResponse{
+		Orders: mtypes.Orders{order},
+	})
+	require.NoError(t, err)
+
+	sims, err := parseOrderGroups("orders.json", buf)
+	require.NoError(t, err)
+	require.Len(t, sims, 1)
+	require.Equal(t, order.OrderID.Owner, sims[0].owner)
+	require.Equal(t, order.Spec.Name, sims[0].gspec.Name)
+
+	maxPrice := order.Spec.Price()
+
+	res := simulateBid(context.Background(), fixedPricing(maxPrice), sims[0])
+	require.True(t, res.Bid)
+	require.Empty(t, res.Reason)
+	require.Equal(t, maxPrice, *res.Price)
+
+	res = simulateBid(context.Background(), fixedPricing(sdk.NewDecCoinFromDec(maxPrice.Denom, maxPrice.Amount.MulInt64(2))), sims[0])
+	require.False(t, res.Bid)
+	require.Equal(t, bidengine.ReasonPriceTooHigh, res.Reason)
+
+	res = simulateBid(context.Background(), fixedPricing(sdk.NewDecCoinFromDec("foo", maxPrice.Amount)), sims[0])
+	require.False(t, res.Bid)
+	require.Equal(t, bidengine.ReasonUnsupportedDenom, res.Reason)
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/log"
+	"gopkg.in/yaml.v3"
+
+	"github.com/akash-network/provider/bidengine"
+	"github.com/akash-network/provider/cluster/kube/builder"
+	"github.com/akash-network/provider/manifest"
+)
+
+// providerFileConfig contains sections of the provider config file which are not part of the
+// on-chain provider configuration and are consumed by provider-services only
+type providerFileConfig struct {
+	Pricing bidengine.PricingPipelineConfig `json:"pricing" yaml:"pricing"`
+	Tenants bidengine.TenantPolicyConfig    `json:"tenants" yaml:"tenants"`
+	Egress  builder.EgressPolicyConfig      `json:"egress" yaml:"egress"`
+	Images  manifest.ImagePolicyConfig      `json:"images" yaml:"images"`
+}
+
+func parseProviderConfig(buf []byte) (providerFileConfig, error) {
+	var val providerFileConfig
+	if err := yaml.Unmarshal(buf, &val); err != nil {
+		return providerFileConfig{}, err
+	}
+
+	return val, nil
+}
+
+func readProviderConfigPath(path string) (providerFileConfig, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return providerFileConfig{}, err
+	}
+
+	return parseProviderConfig(buf)
+}
+
+// watchProviderConfig re-reads provider config file every period and calls onChange
+// each time file content changes. It returns once ctx is done
+func watchProviderConfig(ctx context.Context, log log.Logger, path string, period time.Duration, onChange func(providerFileConfig)) {
+	log = log.With("cmp", "provider-config-watcher", "path", path)
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		log.Error("unable to read provider config", "err", err)
+	}
+
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			log.Error("unable to read provider config", "err", err)
+			continue
+		}
+
+		if bytes.Equal(buf, current) {
+			continue
+		}
+
+		fConf, err := parseProviderConfig(buf)
+		if err != nil {
+			log.Error("unable to parse provider config", "err", err)
+			continue
+		}
+
+		current = buf
+
+		log.Info("provider config changed")
+		onChange(fConf)
+	}
+}
@@ -46,8 +46,15 @@ func NewRootCmd() *cobra.Command {
 	cmd.AddCommand(leaseEventsCmd())
 	cmd.AddCommand(leaseLogsCmd())
 	cmd.AddCommand(serviceStatusCmd())
+	cmd.AddCommand(leaseSnapshotCmd())
+	cmd.AddCommand(bidDecisionsCmd())
+	cmd.AddCommand(pricingCmd())
 	cmd.AddCommand(RunCmd())
 	cmd.AddCommand(LeaseShellCmd())
+	cmd.AddCommand(leaseCopyCmd())
+	cmd.AddCommand(leasePortForwardCmd())
+	cmd.AddCommand(leaseMetricsCmd())
+	cmd.AddCommand(leaseRestartCmd())
 	cmd.AddCommand(hostnameoperator.Cmd())
 	cmd.AddCommand(ipoperator.Cmd())
 	cmd.AddCommand(MigrateHostnamesCmd())
@@ -16,6 +16,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/tendermint/tendermint/libs/log"
 
@@ -44,9 +46,11 @@ import (
 	"github.com/akash-network/provider/cluster/kube/builder"
 	"github.com/akash-network/provider/cluster/kube/clientcommon"
 	"github.com/akash-network/provider/cluster/operatorclients"
+	"github.com/akash-network/provider/cluster/util"
 	providerflags "github.com/akash-network/provider/cmd/provider-services/cmd/flags"
 	cmdutil "github.com/akash-network/provider/cmd/provider-services/cmd/util"
 	gwrest "github.com/akash-network/provider/gateway/rest"
+	"github.com/akash-network/provider/manifest"
 	"github.com/akash-network/provider/operator/waiter"
 	"github.com/akash-network/provider/session"
 )
@@ -60,6 +64,7 @@ const (
 	FlagBidPricingStrategy               = "bid-price-strategy"
 	FlagBidPriceCPUScale                 = "bid-price-cpu-scale"
 	FlagBidPriceMemoryScale              = "bid-price-memory-scale"
+	FlagBidPriceGPUScale                 = "bid-price-gpu-scale"
 	FlagBidPriceStorageScale             = "bid-price-storage-scale"
 	FlagBidPriceEndpointScale            = "bid-price-endpoint-scale"
 	FlagBidPriceScriptPath               = "bid-price-script-path"
@@ -75,6 +80,7 @@ const (
 	FlagDeploymentIngressDomain          = "deployment-ingress-domain"
 	FlagDeploymentIngressExposeLBHosts   = "deployment-ingress-expose-lb-hosts"
 	FlagDeploymentNetworkPoliciesEnabled = "deployment-network-policies-enabled"
+	FlagDeploymentResourceQuotasEnabled  = "deployment-resource-quotas-enabled"
 	FlagDockerImagePullSecretsName       = "docker-image-pull-secrets-name" // nolint: gosec
 	FlagOvercommitPercentMemory          = "overcommit-pct-mem"
 	FlagOvercommitPercentCPU             = "overcommit-pct-cpu"
@@ -82,13 +88,39 @@ const (
 	FlagDeploymentBlockedHostnames       = "deployment-blocked-hostnames"
 	FlagAuthPem                          = "auth-pem"
 	FlagDeploymentRuntimeClass           = "deployment-runtime-class"
+	FlagDeploymentProbeDefaults          = "deployment-probe-defaults"
+	FlagDeploymentProbeExec              = "deployment-probe-exec"
+	FlagDeploymentProbeMaxInitialDelay   = "deployment-probe-max-initial-delay"
+	FlagDeploymentProbeMinPeriod         = "deployment-probe-min-period"
+	FlagDeploymentProbeMaxTimeout        = "deployment-probe-max-timeout"
+	FlagDeploymentProbeMaxFailures       = "deployment-probe-max-failure-threshold"
+	FlagDeploymentRolloutMaxSurge        = "deployment-rollout-max-surge"
+	FlagDeploymentRolloutMaxUnavailable  = "deployment-rollout-max-unavailable"
+	FlagDeploymentRolloutDeadline        = "deployment-rollout-progress-deadline"
+	FlagDeploymentPodSecurityLevel       = "deployment-pod-security-level"
+	FlagDeploymentPodSecurityAudit       = "deployment-pod-security-audit"
+	FlagDeploymentPodSecurityNonRoot     = "deployment-pod-security-run-as-non-root"
+	FlagDeploymentPodSecuritySeccomp     = "deployment-pod-security-seccomp"
+	FlagDeploymentPodSecurityAppArmor    = "deployment-pod-security-apparmor"
+	FlagDeploymentPodSecurityDropCaps    = "deployment-pod-security-drop-capabilities"
+	FlagDeploymentPodSecurityReadOnlyFS  = "deployment-pod-security-read-only-root-fs"
+	FlagDeploymentPodSecuritySysctls     = "deployment-pod-security-sysctls"
+	FlagDeploymentStaleVolumeRetention   = "deployment-stale-volume-retention"
+	FlagDeploymentSnapshotClass          = "deployment-snapshot-class"
+	FlagDeploymentSnapshotQuota          = "deployment-snapshot-quota"
+	FlagDeploymentSnapshotRetention      = "deployment-snapshot-retention"
+	FlagClusterOrphanSweepPeriod         = "cluster-orphan-sweep-period"
+	FlagClusterOrphanSweepMinAge         = "cluster-orphan-sweep-min-age"
 	FlagBidTimeout                       = "bid-timeout"
+	FlagBidDecisionLogSize               = "bid-decision-log-size"
+	FlagBidMaxInFlightOrders             = "bid-max-in-flight-orders"
 	FlagManifestTimeout                  = "manifest-timeout"
 	FlagMetricsListener                  = "metrics-listener"
 	FlagWithdrawalPeriod                 = "withdrawal-period"
 	FlagLeaseFundsMonitorInterval        = "lease-funds-monitor-interval"
 	FlagMinimumBalance                   = "minimum-balance"
 	FlagProviderConfig                   = "provider-config"
+	FlagProviderConfigReloadPeriod       = "provider-config-reload-period"
 	FlagCachedResultMaxAge               = "cached-result-max-age"
 	FlagRPCQueryTimeout                  = "rpc-query-timeout"
 	FlagBidPriceIPScale                  = "bid-price-ip-scale"
@@ -156,48 +188,8 @@ func RunCmd() *cobra.Command {
 		return nil
 	}
 
-	cmd.Flags().String(FlagBidPricingStrategy, "scale", "Pricing strategy to use")
-	if err := viper.BindPFlag(FlagBidPricingStrategy, cmd.Flags().Lookup(FlagBidPricingStrategy)); err != nil {
-		return nil
-	}
-
-	cmd.Flags().String(FlagBidPriceCPUScale, "0", "cpu pricing scale in uakt per millicpu")
-	if err := viper.BindPFlag(FlagBidPriceCPUScale, cmd.Flags().Lookup(FlagBidPriceCPUScale)); err != nil {
-		return nil
-	}
-
-	cmd.Flags().String(FlagBidPriceMemoryScale, "0", "memory pricing scale in uakt per megabyte")
-	if err := viper.BindPFlag(FlagBidPriceMemoryScale, cmd.Flags().Lookup(FlagBidPriceMemoryScale)); err != nil {
-		return nil
-	}
-
-	cmd.Flags().String(FlagBidPriceStorageScale, "0", "storage pricing scale in uakt per megabyte")
-	if err := viper.BindPFlag(FlagBidPriceStorageScale, cmd.Flags().Lookup(FlagBidPriceStorageScale)); err != nil {
-		return nil
-	}
-
-	cmd.Flags().String(FlagBidPriceEndpointScale, "0", "endpoint pricing scale in uakt")
-	if err := viper.BindPFlag(FlagBidPriceEndpointScale, cmd.Flags().Lookup(FlagBidPriceEndpointScale)); err != nil {
-		return nil
-	}
-
-	cmd.Flags().String(FlagBidPriceIPScale, "0", "leased ip pricing scale in uakt")
-	if err := viper.BindPFlag(FlagBidPriceIPScale, cmd.Flags().Lookup(FlagBidPriceIPScale)); err != nil {
-		return nil
-	}
-
-	cmd.Flags().String(FlagBidPriceScriptPath, "", "path to script to run for computing bid price")
-	if err := viper.BindPFlag(FlagBidPriceScriptPath, cmd.Flags().Lookup(FlagBidPriceScriptPath)); err != nil {
-		return nil
-	}
-
-	cmd.Flags().Uint(FlagBidPriceScriptProcessLimit, 32, "limit to the number of scripts run concurrently for bid pricing")
-	if err := viper.BindPFlag(FlagBidPriceScriptProcessLimit, cmd.Flags().Lookup(FlagBidPriceScriptProcessLimit)); err != nil {
-		return nil
-	}
-
-	cmd.Flags().Duration(FlagBidPriceScriptTimeout, time.Second*10, "execution timelimit for bid pricing as a duration")
-	if err := viper.BindPFlag(FlagBidPriceScriptTimeout, cmd.Flags().Lookup(FlagBidPriceScriptTimeout)); err != nil {
+	addBidPricingFlags(cmd.Flags())
+	if err := bindBidPricingFlags(cmd.Flags()); err != nil {
 		return nil
 	}
 
@@ -251,6 +243,11 @@ func RunCmd() *cobra.Command {
 		return nil
 	}
 
+	cmd.Flags().Bool(FlagDeploymentResourceQuotasEnabled, true, "Cap lease namespaces at lease resources with resource quota and limit range")
+	if err := viper.BindPFlag(FlagDeploymentResourceQuotasEnabled, cmd.Flags().Lookup(FlagDeploymentResourceQuotasEnabled)); err != nil {
+		return nil
+	}
+
 	cmd.Flags().String(FlagDockerImagePullSecretsName, "", "Name of the local image pull secret configured with kubectl")
 	if err := viper.BindPFlag(FlagDockerImagePullSecretsName, cmd.Flags().Lookup(FlagDockerImagePullSecretsName)); err != nil {
 		return nil
@@ -287,11 +284,106 @@ func RunCmd() *cobra.Command {
 		return nil
 	}
 
+	cmd.Flags().Bool(FlagDeploymentProbeDefaults, false, "generate tcp liveness and readiness probes on the first exposed port for services without probes")
+	if err := viper.BindPFlag(FlagDeploymentProbeDefaults, cmd.Flags().Lookup(FlagDeploymentProbeDefaults)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Bool(FlagDeploymentProbeExec, true, "allow exec health probes in tenant containers")
+	if err := viper.BindPFlag(FlagDeploymentProbeExec, cmd.Flags().Lookup(FlagDeploymentProbeExec)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Duration(FlagDeploymentProbeMaxInitialDelay, 5*time.Minute, "maximum initial delay of health probes. 0 for no limit")
+	if err := viper.BindPFlag(FlagDeploymentProbeMaxInitialDelay, cmd.Flags().Lookup(FlagDeploymentProbeMaxInitialDelay)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Duration(FlagDeploymentProbeMinPeriod, 5*time.Second, "minimum period of health probes. 0 for no limit")
+	if err := viper.BindPFlag(FlagDeploymentProbeMinPeriod, cmd.Flags().Lookup(FlagDeploymentProbeMinPeriod)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Duration(FlagDeploymentProbeMaxTimeout, 30*time.Second, "maximum timeout of health probes. 0 for no limit")
+	if err := viper.BindPFlag(FlagDeploymentProbeMaxTimeout, cmd.Flags().Lookup(FlagDeploymentProbeMaxTimeout)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Int32(FlagDeploymentProbeMaxFailures, 30, "maximum failure threshold of health probes. 0 for no limit")
+	if err := viper.BindPFlag(FlagDeploymentProbeMaxFailures, cmd.Flags().Lookup(FlagDeploymentProbeMaxFailures)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().String(FlagDeploymentRolloutMaxSurge, "25%", "number or percentage of pods created above desired count during deployment update")
+	if err := viper.BindPFlag(FlagDeploymentRolloutMaxSurge, cmd.Flags().Lookup(FlagDeploymentRolloutMaxSurge)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().String(FlagDeploymentRolloutMaxUnavailable, "25%", "number or percentage of pods which may be unavailable during deployment update")
+	if err := viper.BindPFlag(FlagDeploymentRolloutMaxUnavailable, cmd.Flags().Lookup(FlagDeploymentRolloutMaxUnavailable)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Duration(FlagDeploymentRolloutDeadline, 10*time.Minute, "time after which stalled deployment update is reported as failed")
+	if err := viper.BindPFlag(FlagDeploymentRolloutDeadline, cmd.Flags().Lookup(FlagDeploymentRolloutDeadline)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().String(FlagDeploymentPodSecurityLevel, "", "pod security standard of lease namespaces: privileged, baseline or restricted. empty leaves namespaces unlabeled")
+	if err := viper.BindPFlag(FlagDeploymentPodSecurityLevel, cmd.Flags().Lookup(FlagDeploymentPodSecurityLevel)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Bool(FlagDeploymentPodSecurityAudit, false, "only report violations of pod security standard instead of rejecting pods")
+	if err := viper.BindPFlag(FlagDeploymentPodSecurityAudit, cmd.Flags().Lookup(FlagDeploymentPodSecurityAudit)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Bool(FlagDeploymentPodSecurityNonRoot, false, "reject tenant containers running as root")
+	if err := viper.BindPFlag(FlagDeploymentPodSecurityNonRoot, cmd.Flags().Lookup(FlagDeploymentPodSecurityNonRoot)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Bool(FlagDeploymentPodSecuritySeccomp, true, "run tenant containers with runtime default seccomp profile")
+	if err := viper.BindPFlag(FlagDeploymentPodSecuritySeccomp, cmd.Flags().Lookup(FlagDeploymentPodSecuritySeccomp)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Bool(FlagDeploymentPodSecurityAppArmor, false, "run tenant containers with runtime default AppArmor profile. requires AppArmor on every node")
+	if err := viper.BindPFlag(FlagDeploymentPodSecurityAppArmor, cmd.Flags().Lookup(FlagDeploymentPodSecurityAppArmor)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().StringSlice(FlagDeploymentPodSecurityDropCaps, nil, "capabilities dropped from tenant containers, e.g. ALL")
+	if err := viper.BindPFlag(FlagDeploymentPodSecurityDropCaps, cmd.Flags().Lookup(FlagDeploymentPodSecurityDropCaps)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Bool(FlagDeploymentPodSecurityReadOnlyFS, false, "mount root filesystem of tenant containers as read only")
+	if err := viper.BindPFlag(FlagDeploymentPodSecurityReadOnlyFS, cmd.Flags().Lookup(FlagDeploymentPodSecurityReadOnlyFS)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().StringSlice(FlagDeploymentPodSecuritySysctls, nil, "sysctls set on tenant pods as name=value")
+	if err := viper.BindPFlag(FlagDeploymentPodSecuritySysctls, cmd.Flags().Lookup(FlagDeploymentPodSecuritySysctls)); err != nil {
+		return nil
+	}
+
 	cmd.Flags().Duration(FlagBidTimeout, 5*time.Minute, "time after which bids are cancelled if no lease is created")
 	if err := viper.BindPFlag(FlagBidTimeout, cmd.Flags().Lookup(FlagBidTimeout)); err != nil {
 		return nil
 	}
 
+	cmd.Flags().Int(FlagBidDecisionLogSize, bidengine.DefaultDecisionLogSize, "amount of most recent bid decisions kept for inspection")
+	if err := viper.BindPFlag(FlagBidDecisionLogSize, cmd.Flags().Lookup(FlagBidDecisionLogSize)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Int(FlagBidMaxInFlightOrders, 16, "maximum number of orders reserving resources and bidding at the same time, others are queued by priority. 0 is unlimited")
+	if err := viper.BindPFlag(FlagBidMaxInFlightOrders, cmd.Flags().Lookup(FlagBidMaxInFlightOrders)); err != nil {
+		return nil
+	}
+
 	cmd.Flags().Duration(FlagManifestTimeout, 5*time.Minute, "time after which bids are cancelled if no manifest is received")
 	if err := viper.BindPFlag(FlagManifestTimeout, cmd.Flags().Lookup(FlagManifestTimeout)); err != nil {
 		return nil
@@ -322,11 +414,46 @@ func RunCmd() *cobra.Command {
 		return nil
 	}
 
+	cmd.Flags().Duration(FlagProviderConfigReloadPeriod, time.Minute, "period of checking provider configuration file for changes, 0 disables reload")
+	if err := viper.BindPFlag(FlagProviderConfigReloadPeriod, cmd.Flags().Lookup(FlagProviderConfigReloadPeriod)); err != nil {
+		return nil
+	}
+
 	cmd.Flags().Duration(FlagRPCQueryTimeout, time.Minute, "timeout for requests made to the RPC node")
 	if err := viper.BindPFlag(FlagRPCQueryTimeout, cmd.Flags().Lookup(FlagRPCQueryTimeout)); err != nil {
 		return nil
 	}
 
+	cmd.Flags().Duration(FlagDeploymentStaleVolumeRetention, time.Hour, "time volumes of services removed from manifest are kept before deletion. 0 deletes them right away")
+	if err := viper.BindPFlag(FlagDeploymentStaleVolumeRetention, cmd.Flags().Lookup(FlagDeploymentStaleVolumeRetention)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().String(FlagDeploymentSnapshotClass, "", "VolumeSnapshotClass used for snapshots of persistent volumes. cluster default is used when empty")
+	if err := viper.BindPFlag(FlagDeploymentSnapshotClass, cmd.Flags().Lookup(FlagDeploymentSnapshotClass)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Uint(FlagDeploymentSnapshotQuota, 5, "maximum number of volume snapshots kept per lease. 0 disables snapshots")
+	if err := viper.BindPFlag(FlagDeploymentSnapshotQuota, cmd.Flags().Lookup(FlagDeploymentSnapshotQuota)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Duration(FlagDeploymentSnapshotRetention, 7*24*time.Hour, "time volume snapshots are kept. 0 keeps them until lease is closed")
+	if err := viper.BindPFlag(FlagDeploymentSnapshotRetention, cmd.Flags().Lookup(FlagDeploymentSnapshotRetention)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Duration(FlagClusterOrphanSweepPeriod, 15*time.Minute, "how often objects of leases which are no longer active are removed from the cluster. 0 disables sweeping")
+	if err := viper.BindPFlag(FlagClusterOrphanSweepPeriod, cmd.Flags().Lookup(FlagClusterOrphanSweepPeriod)); err != nil {
+		return nil
+	}
+
+	cmd.Flags().Duration(FlagClusterOrphanSweepMinAge, 10*time.Minute, "minimum age of objects removed by orphan sweeper")
+	if err := viper.BindPFlag(FlagClusterOrphanSweepMinAge, cmd.Flags().Lookup(FlagClusterOrphanSweepMinAge)); err != nil {
+		return nil
+	}
+
 	cmd.Flags().Duration(FlagCachedResultMaxAge, 5*time.Second, "max. cache age for results from the RPC node")
 	if err := viper.BindPFlag(FlagCachedResultMaxAge, cmd.Flags().Lookup(FlagCachedResultMaxAge)); err != nil {
 		return nil
@@ -357,12 +484,14 @@ const (
 	bidPricingStrategyScale       = "scale"
 	bidPricingStrategyRandomRange = "randomRange"
 	bidPricingStrategyShellScript = "shellScript"
+	bidPricingStrategyJSScript    = "jsScript"
 )
 
 var allowedBidPricingStrategies = [...]string{
 	bidPricingStrategyScale,
 	bidPricingStrategyRandomRange,
 	bidPricingStrategyShellScript,
+	bidPricingStrategyJSScript,
 }
 
 var errNoSuchBidPricingStrategy = fmt.Errorf("No such bid pricing strategy. Allowed: %v", allowedBidPricingStrategies)
@@ -392,6 +521,26 @@ func createBidPricingStrategy(strategy string) (bidengine.BidPricingStrategy, er
 		if err != nil {
 			return nil, err
 		}
+		gpuScale := make(bidengine.GPU)
+
+		gpuScales := strings.Split(viper.GetString(FlagBidPriceGPUScale), ",")
+		for _, scalePair := range gpuScales {
+			vals := strings.Split(scalePair, "=")
+
+			name := bidengine.GPUWildcard
+			scaleVal := vals[0]
+
+			if len(vals) == 2 {
+				name = vals[0]
+				scaleVal = vals[1]
+			}
+
+			gpuScale[name], err = strToBidPriceScale(scaleVal)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		storageScale := make(bidengine.Storage)
 
 		storageScales := strings.Split(viper.GetString(FlagBidPriceStorageScale), ",")
@@ -422,7 +571,7 @@ func createBidPricingStrategy(strategy string) (bidengine.BidPricingStrategy, er
 			return nil, err
 		}
 
-		return bidengine.MakeScalePricing(cpuScale, memoryScale, storageScale, endpointScale, ipScale)
+		return bidengine.MakeScalePricing(cpuScale, memoryScale, gpuScale, storageScale, endpointScale, ipScale)
 	}
 
 	if strategy == bidPricingStrategyRandomRange {
@@ -436,6 +585,13 @@ func createBidPricingStrategy(strategy string) (bidengine.BidPricingStrategy, er
 		return bidengine.MakeShellScriptPricing(scriptPath, processLimit, runtimeLimit)
 	}
 
+	if strategy == bidPricingStrategyJSScript {
+		scriptPath := viper.GetString(FlagBidPriceScriptPath)
+		processLimit := viper.GetUint(FlagBidPriceScriptProcessLimit)
+		runtimeLimit := viper.GetDuration(FlagBidPriceScriptTimeout)
+		return bidengine.MakeJSScriptPricing(scriptPath, processLimit, runtimeLimit)
+	}
+
 	return nil, errNoSuchBidPricingStrategy
 }
 
@@ -450,6 +606,7 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 	deploymentIngressStaticHosts := viper.GetBool(FlagDeploymentIngressStaticHosts)
 	deploymentIngressDomain := viper.GetString(FlagDeploymentIngressDomain)
 	deploymentNetworkPoliciesEnabled := viper.GetBool(FlagDeploymentNetworkPoliciesEnabled)
+	deploymentResourceQuotasEnabled := viper.GetBool(FlagDeploymentResourceQuotasEnabled)
 	dockerImagePullSecretsName := viper.GetString(FlagDockerImagePullSecretsName)
 	strategy := viper.GetString(FlagBidPricingStrategy)
 	deploymentIngressExposeLBHosts := viper.GetBool(FlagDeploymentIngressExposeLBHosts)
@@ -463,9 +620,12 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 	kubeConfigPath := viper.GetString(providerflags.FlagKubeConfig)
 	deploymentRuntimeClass := viper.GetString(FlagDeploymentRuntimeClass)
 	bidTimeout := viper.GetDuration(FlagBidTimeout)
+	bidDecisionLogSize := viper.GetInt(FlagBidDecisionLogSize)
+	bidMaxInFlightOrders := viper.GetInt(FlagBidMaxInFlightOrders)
 	manifestTimeout := viper.GetDuration(FlagManifestTimeout)
 	metricsListener := viper.GetString(FlagMetricsListener)
 	providerConfig := viper.GetString(FlagProviderConfig)
+	providerConfigReloadPeriod := viper.GetDuration(FlagProviderConfigReloadPeriod)
 	cachedResultMaxAge := viper.GetDuration(FlagCachedResultMaxAge)
 	rpcQueryTimeout := viper.GetDuration(FlagRPCQueryTimeout)
 	enableIPOperator := viper.GetBool(FlagEnableIPOperator)
@@ -565,12 +725,26 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 
 	pinfo := &res.Provider
 
+	var fConf providerFileConfig
+	if len(providerConfig) != 0 {
+		if fConf, err = readProviderConfigPath(providerConfig); err != nil {
+			return err
+		}
+	}
+
+	egressPolicy, err := builder.NewEgressPolicy(fConf.Egress)
+	if err != nil {
+		return err
+	}
+
 	// k8s client creation
 	kubeSettings := builder.NewDefaultSettings()
 	kubeSettings.DeploymentIngressDomain = deploymentIngressDomain
 	kubeSettings.DeploymentIngressExposeLBHosts = deploymentIngressExposeLBHosts
 	kubeSettings.DeploymentIngressStaticHosts = deploymentIngressStaticHosts
 	kubeSettings.NetworkPoliciesEnabled = deploymentNetworkPoliciesEnabled
+	kubeSettings.EgressPolicy = egressPolicy
+	kubeSettings.ResourceQuotasEnabled = deploymentResourceQuotasEnabled
 	kubeSettings.ClusterPublicHostname = clusterPublicHostname
 	kubeSettings.CPUCommitLevel = overcommitPercentCPU
 	kubeSettings.GPUCommitLevel = overcommitPercentGPU
@@ -578,6 +752,51 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 	kubeSettings.StorageCommitLevel = overcommitPercentStorage
 	kubeSettings.DeploymentRuntimeClass = deploymentRuntimeClass
 	kubeSettings.DockerImagePullSecretsName = strings.TrimSpace(dockerImagePullSecretsName)
+	kubeSettings.Probes = builder.ProbeSettings{
+		DefaultsEnabled:     viper.GetBool(FlagDeploymentProbeDefaults),
+		ExecEnabled:         viper.GetBool(FlagDeploymentProbeExec),
+		MaxInitialDelay:     viper.GetDuration(FlagDeploymentProbeMaxInitialDelay),
+		MinPeriod:           viper.GetDuration(FlagDeploymentProbeMinPeriod),
+		MaxTimeout:          viper.GetDuration(FlagDeploymentProbeMaxTimeout),
+		MaxFailureThreshold: viper.GetInt32(FlagDeploymentProbeMaxFailures),
+	}
+
+	rolloutMaxSurge := intstr.Parse(viper.GetString(FlagDeploymentRolloutMaxSurge))
+	rolloutMaxUnavailable := intstr.Parse(viper.GetString(FlagDeploymentRolloutMaxUnavailable))
+	kubeSettings.Rollout = builder.RolloutSettings{
+		MaxSurge:         &rolloutMaxSurge,
+		MaxUnavailable:   &rolloutMaxUnavailable,
+		ProgressDeadline: viper.GetDuration(FlagDeploymentRolloutDeadline),
+	}
+
+	sysctls, err := builder.ParseSysctls(viper.GetStringSlice(FlagDeploymentPodSecuritySysctls))
+	if err != nil {
+		return err
+	}
+
+	var dropCapabilities []corev1.Capability
+	for _, capability := range viper.GetStringSlice(FlagDeploymentPodSecurityDropCaps) {
+		dropCapabilities = append(dropCapabilities, corev1.Capability(strings.ToUpper(capability)))
+	}
+
+	kubeSettings.PodSecurity = builder.PodSecuritySettings{
+		Level:                  builder.PodSecurityLevel(viper.GetString(FlagDeploymentPodSecurityLevel)),
+		Audit:                  viper.GetBool(FlagDeploymentPodSecurityAudit),
+		RunAsNonRoot:           viper.GetBool(FlagDeploymentPodSecurityNonRoot),
+		SeccompRuntimeDefault:  viper.GetBool(FlagDeploymentPodSecuritySeccomp),
+		AppArmorRuntimeDefault: viper.GetBool(FlagDeploymentPodSecurityAppArmor),
+		DropCapabilities:       dropCapabilities,
+		ReadOnlyRootFilesystem: viper.GetBool(FlagDeploymentPodSecurityReadOnlyFS),
+		Sysctls:                sysctls,
+	}
+
+	kubeSettings.StaleVolumeRetention = viper.GetDuration(FlagDeploymentStaleVolumeRetention)
+
+	kubeSettings.Snapshots = builder.SnapshotSettings{
+		Class:     viper.GetString(FlagDeploymentSnapshotClass),
+		Quota:     viper.GetUint(FlagDeploymentSnapshotQuota),
+		Retention: viper.GetDuration(FlagDeploymentSnapshotRetention),
+	}
 
 	if err := builder.ValidateSettings(kubeSettings); err != nil {
 		return err
@@ -622,6 +841,8 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 	config.DeploymentIngressStaticHosts = deploymentIngressStaticHosts
 	config.DeploymentIngressDomain = deploymentIngressDomain
 	config.BidTimeout = bidTimeout
+	config.BidDecisionLogSize = bidDecisionLogSize
+	config.BidMaxInFlightOrders = bidMaxInFlightOrders
 	config.ManifestTimeout = manifestTimeout
 
 	if len(providerConfig) != 0 {
@@ -633,6 +854,59 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 		if err = config.Attributes.Validate(); err != nil {
 			return err
 		}
+
+		config.BidPricingPipeline = fConf.Pricing
+
+		if config.TenantPolicy, err = bidengine.NewTenantPolicy(fConf.Tenants); err != nil {
+			return err
+		}
+
+		if config.ImagePolicy, err = manifest.NewImagePolicy(fConf.Images); err != nil {
+			return err
+		}
+
+		if providerConfigReloadPeriod > 0 {
+			tenantPolicy := config.TenantPolicy
+			imagePolicy := config.ImagePolicy
+			group.Go(func() error {
+				watchProviderConfig(ctx, logger, providerConfig, providerConfigReloadPeriod, func(fConf providerFileConfig) {
+					if err := tenantPolicy.Update(fConf.Tenants); err != nil {
+						logger.Error("unable to apply tenant policy", "err", err)
+					} else {
+						logger.Info("tenant policy reloaded")
+					}
+
+					if err := imagePolicy.Update(fConf.Images); err != nil {
+						logger.Error("unable to apply image policy", "err", err)
+					} else {
+						logger.Info("image policy reloaded")
+					}
+
+					changed, err := egressPolicy.Update(fConf.Egress)
+					if err != nil {
+						logger.Error("unable to apply egress policy", "err", err)
+						return
+					}
+
+					// leases are walked only when network policies would actually differ
+					if !changed {
+						return
+					}
+
+					kclient, valid := cclient.(kube.Client)
+					if !valid || !deploymentNetworkPoliciesEnabled {
+						return
+					}
+
+					if err := kclient.ReapplyNetworkPolicies(util.ApplyToContext(ctx, clusterSettings)); err != nil {
+						logger.Error("unable to reapply network policies", "err", err)
+						return
+					}
+					logger.Info("egress policy reloaded")
+				})
+				return nil
+			})
+		}
 	}
 
 	config.BalanceCheckerCfg = provider.BalanceCheckerConfig{
@@ -650,6 +924,8 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 	config.BidDeposit = bidDeposit
 	config.RPCQueryTimeout = rpcQueryTimeout
 	config.CachedResultMaxAge = cachedResultMaxAge
+	config.OrphanSweepPeriod = viper.GetDuration(FlagClusterOrphanSweepPeriod)
+	config.OrphanSweepMinAge = viper.GetDuration(FlagClusterOrphanSweepMinAge)
 
 	// This value can be nil, the operator is not mandatory
 	var ipOperatorClient operatorclients.IPOperatorClient
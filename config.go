@@ -10,6 +10,7 @@ import (
 	types "github.com/akash-network/akash-api/go/node/types/v1beta3"
 
 	"github.com/akash-network/provider/bidengine"
+	"github.com/akash-network/provider/manifest"
 )
 
 type Config struct {
@@ -19,6 +20,7 @@ type Config struct {
 	InventoryResourcePollPeriod     time.Duration
 	InventoryResourceDebugFrequency uint
 	BidPricingStrategy              bidengine.BidPricingStrategy
+	BidPricingPipeline              bidengine.PricingPipelineConfig
 	BidDeposit                      sdk.Coin
 	CPUCommitLevel                  float64
 	MemoryCommitLevel               float64
@@ -26,7 +28,11 @@ type Config struct {
 	MaxGroupVolumes                 int
 	BlockedHostnames                []string
 	BidTimeout                      time.Duration
+	BidDecisionLogSize              int
+	BidMaxInFlightOrders            int
+	TenantPolicy                    *bidengine.TenantPolicy
 	ManifestTimeout                 time.Duration
+	ImagePolicy                     *manifest.ImagePolicy
 	BalanceCheckerCfg               BalanceCheckerConfig
 	Attributes                      types.Attributes
 	DeploymentIngressStaticHosts    bool
@@ -34,6 +40,8 @@ type Config struct {
 	ClusterSettings                 map[interface{}]interface{}
 	RPCQueryTimeout                 time.Duration
 	CachedResultMaxAge              time.Duration
+	OrphanSweepPeriod               time.Duration
+	OrphanSweepMinAge               time.Duration
 }
 
 func NewDefaultConfig() Config {
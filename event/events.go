@@ -6,6 +6,8 @@ import (
 	mani "github.com/akash-network/akash-api/go/manifest/v2beta2"
 	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 )
 
 // LeaseWon is the data structure that includes leaseID, group and price
@@ -20,6 +22,7 @@ type LeaseWon struct {
 type ManifestReceived struct {
 	LeaseID    mtypes.LeaseID
 	Manifest   *mani.Manifest
+	Probes     crd.ManifestProbes
 	Deployment *dtypes.QueryDeploymentResponse
 	Group      *dtypes.Group
 }
@@ -35,6 +38,11 @@ func (ev ManifestReceived) ManifestGroup() *mani.Group {
 	return nil
 }
 
+// GroupProbes returns health probes of the group services if present in manifest or nil
+func (ev ManifestReceived) GroupProbes() []*crd.ManifestServiceProbes {
+	return ev.Probes[ev.Group.GroupSpec.Name]
+}
+
 // ClusterDeploymentStatus represents status of the cluster deployment
 type ClusterDeploymentStatus string
 
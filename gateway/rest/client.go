@@ -33,6 +33,7 @@ import (
 	cutils "github.com/akash-network/node/x/cert/utils"
 
 	"github.com/akash-network/provider"
+	"github.com/akash-network/provider/bidengine"
 	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
 )
 
@@ -45,19 +46,30 @@ const (
 type Client interface {
 	Status(ctx context.Context) (*provider.Status, error)
 	Validate(ctx context.Context, gspec dtypes.GroupSpec) (provider.ValidateGroupSpecResult, error)
+	BidDecisions(ctx context.Context, filter bidengine.DecisionFilter) ([]bidengine.BidDecision, error)
 	SubmitManifest(ctx context.Context, dseq uint64, mani manifest.Manifest) error
 	LeaseStatus(ctx context.Context, id mtypes.LeaseID) (LeaseStatus, error)
 	LeaseEvents(ctx context.Context, id mtypes.LeaseID, services string, follow bool) (*LeaseKubeEvents, error)
-	LeaseLogs(ctx context.Context, id mtypes.LeaseID, services string, follow bool, tailLines int64) (*ServiceLogs, error)
+	LeaseLogs(ctx context.Context, id mtypes.LeaseID, opts cltypes.LeaseLogsOptions) (*ServiceLogs, error)
 	ServiceStatus(ctx context.Context, id mtypes.LeaseID, service string) (*cltypes.ServiceStatus, error)
+	LeaseMetrics(ctx context.Context, id mtypes.LeaseID) (*cltypes.LeaseMetrics, error)
+	LeaseMetricsPrometheus(ctx context.Context, id mtypes.LeaseID) ([]byte, error)
+	LeaseMetricsStream(ctx context.Context, id mtypes.LeaseID, interval time.Duration) (*LeaseMetricsStream, error)
 	LeaseShell(ctx context.Context, id mtypes.LeaseID, service string, podIndex uint, cmd []string,
 		stdin io.ReadCloser,
 		stdout io.Writer,
 		stderr io.Writer,
 		tty bool,
 		tsq <-chan remotecommand.TerminalSize) error
+	LeaseCopy(ctx context.Context, id mtypes.LeaseID, req LeaseCopyRequest, input io.ReadCloser, output io.Writer) error
+	LeaseFileSize(ctx context.Context, id mtypes.LeaseID, service string, podIndex uint, path string) (uint64, error)
+	LeasePortForward(ctx context.Context, id mtypes.LeaseID, service string, podIndex uint, port uint32, input io.Reader, output io.Writer) error
 	MigrateHostnames(ctx context.Context, hostnames []string, dseq uint64, gseq uint32) error
 	MigrateEndpoints(ctx context.Context, endpoints []string, dseq uint64, gseq uint32) error
+	LeaseSnapshots(ctx context.Context, id mtypes.LeaseID, service string) ([]cltypes.VolumeSnapshot, error)
+	CreateSnapshot(ctx context.Context, id mtypes.LeaseID, service string) ([]cltypes.VolumeSnapshot, error)
+	RestoreSnapshot(ctx context.Context, id mtypes.LeaseID, service string, name string) error
+	RestartService(ctx context.Context, id mtypes.LeaseID, service string, podIndex *uint) error
 }
 
 type JwtClient interface {
@@ -417,6 +429,40 @@ func (c *client) Validate(ctx context.Context, gspec dtypes.GroupSpec) (provider
 	return obj, nil
 }
 
+func (c *client) BidDecisions(ctx context.Context, filter bidengine.DecisionFilter) ([]bidengine.BidDecision, error) {
+	endpoint, err := url.Parse(c.host.String() + "/" + bidDecisionsPath())
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+
+	if filter.Owner != "" {
+		query.Set("owner", filter.Owner)
+	}
+
+	if filter.DSeq != 0 {
+		query.Set("dseq", strconv.FormatUint(filter.DSeq, 10))
+	}
+
+	if filter.GSeq != 0 {
+		query.Set("gseq", strconv.FormatUint(uint64(filter.GSeq), 10))
+	}
+
+	if filter.OSeq != 0 {
+		query.Set("oseq", strconv.FormatUint(uint64(filter.OSeq), 10))
+	}
+
+	endpoint.RawQuery = query.Encode()
+
+	var obj []bidengine.BidDecision
+	if err := c.getStatus(ctx, endpoint.String(), &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
 func (c *client) SubmitManifest(ctx context.Context, dseq uint64, mani manifest.Manifest) error {
 	uri, err := makeURI(c.host, submitManifestPath(dseq))
 	if err != nil {
@@ -655,6 +701,99 @@ func (c *client) ServiceStatus(ctx context.Context, id mtypes.LeaseID, service s
 	return &obj, nil
 }
 
+func (c *client) LeaseSnapshots(ctx context.Context, id mtypes.LeaseID, service string) ([]cltypes.VolumeSnapshot, error) {
+	endpoint, err := url.Parse(c.host.String() + "/" + leaseSnapshotsPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if service != "" {
+		query := url.Values{}
+		query.Set("service", service)
+		endpoint.RawQuery = query.Encode()
+	}
+
+	var obj []cltypes.VolumeSnapshot
+	if err := c.getStatus(ctx, endpoint.String(), &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func (c *client) CreateSnapshot(ctx context.Context, id mtypes.LeaseID, service string) ([]cltypes.VolumeSnapshot, error) {
+	uri, err := makeURI(c.host, serviceSnapshotsPath(id, service))
+	if err != nil {
+		return nil, err
+	}
+
+	var obj []cltypes.VolumeSnapshot
+	if err := c.post(ctx, uri, &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func (c *client) RestoreSnapshot(ctx context.Context, id mtypes.LeaseID, service string, name string) error {
+	uri, err := makeURI(c.host, restoreSnapshotPath(id, service, name))
+	if err != nil {
+		return err
+	}
+
+	return c.post(ctx, uri, nil)
+}
+
+// RestartService restarts all replicas of the service, or only the one at podIndex when it is not nil
+func (c *client) RestartService(ctx context.Context, id mtypes.LeaseID, service string, podIndex *uint) error {
+	endpoint, err := url.Parse(c.host.String() + "/" + serviceRestartPath(id, service))
+	if err != nil {
+		return err
+	}
+
+	if podIndex != nil {
+		query := url.Values{}
+		query.Set("podIndex", strconv.FormatUint(uint64(*podIndex), 10))
+		endpoint.RawQuery = query.Encode()
+	}
+
+	return c.post(ctx, endpoint.String(), nil)
+}
+
+// post sends request without body and decodes response into obj unless it is nil
+func (c *client) post(ctx context.Context, uri string, obj interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+
+	resp, err := c.hclient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	_, err = io.Copy(buf, resp.Body)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if err != nil {
+		return err
+	}
+
+	if err = createClientResponseErrorIfNotOK(resp, buf); err != nil {
+		return err
+	}
+
+	if obj == nil {
+		return nil
+	}
+
+	return json.NewDecoder(buf).Decode(obj)
+}
+
 func (c *client) getStatus(ctx context.Context, uri string, obj interface{}) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", uri, nil)
 	if err != nil {
@@ -708,11 +847,7 @@ func makeURI(uri *url.URL, path string) (string, error) {
 	return endpoint.String(), nil
 }
 
-func (c *client) LeaseLogs(ctx context.Context,
-	id mtypes.LeaseID,
-	services string,
-	follow bool,
-	tailLines int64) (*ServiceLogs, error) {
+func (c *client) LeaseLogs(ctx context.Context, id mtypes.LeaseID, opts cltypes.LeaseLogsOptions) (*ServiceLogs, error) {
 
 	endpoint, err := url.Parse(c.host.String() + "/" + serviceLogsPath(id))
 	if err != nil {
@@ -726,13 +861,7 @@ func (c *client) LeaseLogs(ctx context.Context,
 		return nil, errors.Errorf("invalid uri scheme \"%s\"", endpoint.Scheme)
 	}
 
-	query := url.Values{}
-
-	query.Set("follow", strconv.FormatBool(follow))
-
-	if services != "" {
-		query.Set("services", services)
-	}
+	query := logsQuery(opts)
 
 	endpoint.RawQuery = query.Encode()
 
@@ -814,3 +943,43 @@ func parseCloseMessage(msg string) string {
 
 	return ""
 }
+
+func logsQuery(opts cltypes.LeaseLogsOptions) url.Values {
+	query := url.Values{}
+
+	query.Set("follow", strconv.FormatBool(opts.Follow))
+
+	if opts.Services != "" {
+		query.Set("service", opts.Services)
+	}
+
+	if opts.TailLines != nil {
+		query.Set("tail", strconv.FormatInt(*opts.TailLines, 10))
+	}
+
+	if opts.SinceSeconds != nil {
+		query.Set("since", (time.Duration(*opts.SinceSeconds) * time.Second).String())
+	}
+
+	if opts.SinceTime != nil {
+		query.Set("since_time", opts.SinceTime.Format(time.RFC3339))
+	}
+
+	if opts.Previous {
+		query.Set("previous", "true")
+	}
+
+	if opts.Timestamps {
+		query.Set("timestamps", "true")
+	}
+
+	if opts.LimitBytes != nil {
+		query.Set("limit_bytes", strconv.FormatInt(*opts.LimitBytes, 10))
+	}
+
+	if opts.Replica != nil {
+		query.Set("replica", strconv.FormatUint(uint64(*opts.Replica), 10))
+	}
+
+	return query
+}
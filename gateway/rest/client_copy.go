@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+)
+
+// LeaseCopyOp is operation performed by lease copy endpoint
+type LeaseCopyOp string
+
+const (
+	// LeaseCopyOpUpload writes input into the container. Tar archive is extracted into the directory at Path,
+	// raw data is written to the file at Path starting at Offset
+	LeaseCopyOpUpload LeaseCopyOp = "upload"
+	// LeaseCopyOpDownload reads from the container. Path is sent as tar archive,
+	// or raw contents of the file at Path starting at Offset if Raw is set
+	LeaseCopyOpDownload LeaseCopyOp = "download"
+	// LeaseCopyOpStat prints size of the regular file at Path, fails for anything else
+	LeaseCopyOpStat LeaseCopyOp = "stat"
+)
+
+var (
+	errLeaseCopy = errors.New("lease copy failed")
+)
+
+// LeaseCopyRequest describes single transfer between the client and container of the lease
+type LeaseCopyRequest struct {
+	Service  string
+	PodIndex uint
+	Op       LeaseCopyOp
+	Path     string
+	// Raw transfers contents of single file instead of tar archive
+	Raw    bool
+	Offset uint64
+	// Length limits raw download, 0 reads until the end of file
+	Length uint64
+}
+
+func (r LeaseCopyRequest) query() url.Values {
+	query := url.Values{}
+	query.Set("service", r.Service)
+	query.Set("podIndex", fmt.Sprintf("%d", r.PodIndex))
+	query.Set("op", string(r.Op))
+	query.Set("path", r.Path)
+
+	if r.Raw {
+		query.Set("raw", "1")
+		query.Set("offset", strconv.FormatUint(r.Offset, 10))
+		query.Set("length", strconv.FormatUint(r.Length, 10))
+	}
+
+	return query
+}
+
+func (c *client) LeaseCopy(ctx context.Context, lID mtypes.LeaseID, req LeaseCopyRequest, input io.ReadCloser, output io.Writer) error {
+	if output == nil {
+		output = io.Discard
+	}
+
+	stderr := &bytes.Buffer{}
+
+	err := c.leaseExec(ctx, leaseCopyPath(lID), req.query(), input, true, output, stderr, false, nil)
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (c *client) LeaseFileSize(ctx context.Context, lID mtypes.LeaseID, service string, podIndex uint, path string) (uint64, error) {
+	buf := &bytes.Buffer{}
+
+	err := c.LeaseCopy(ctx, lID, LeaseCopyRequest{
+		Service:  service,
+		PodIndex: podIndex,
+		Op:       LeaseCopyOpStat,
+		Path:     path,
+	}, nil, buf)
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := strconv.ParseUint(strings.TrimSpace(buf.String()), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: unexpected file size %q", errLeaseCopy, buf.String())
+	}
+
+	return size, nil
+}
@@ -0,0 +1,178 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+
+	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+type LeaseMetricsStream struct {
+	Stream  <-chan cltypes.LeaseMetrics
+	OnClose <-chan string
+}
+
+func (c *client) LeaseMetrics(ctx context.Context, id mtypes.LeaseID) (*cltypes.LeaseMetrics, error) {
+	uri, err := makeURI(c.host, leaseMetricsPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var obj cltypes.LeaseMetrics
+	if err := c.getStatus(ctx, uri, &obj); err != nil {
+		return nil, err
+	}
+
+	return &obj, nil
+}
+
+// LeaseMetricsPrometheus returns lease metrics in prometheus text exposition format
+func (c *client) LeaseMetricsPrometheus(ctx context.Context, id mtypes.LeaseID) ([]byte, error) {
+	endpoint, err := url.Parse(c.host.String() + "/" + leaseMetricsPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("format", metricsFormatPrometheus)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.hclient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	_, err = io.Copy(buf, resp.Body)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = createClientResponseErrorIfNotOK(resp, buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// LeaseMetricsStream receives lease metrics from the provider every interval until ctx is done
+func (c *client) LeaseMetricsStream(ctx context.Context, id mtypes.LeaseID, interval time.Duration) (*LeaseMetricsStream, error) {
+	endpoint, err := url.Parse(c.host.String() + "/" + leaseMetricsPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	switch endpoint.Scheme {
+	case schemeWSS, schemeHTTPS:
+		endpoint.Scheme = schemeWSS
+	default:
+		return nil, fmt.Errorf("invalid uri scheme %q", endpoint.Scheme) // nolint: goerr113
+	}
+
+	if interval > 0 {
+		query := url.Values{}
+		query.Set("interval", interval.String())
+		endpoint.RawQuery = query.Encode()
+	}
+
+	conn, response, err := c.wsclient.DialContext(ctx, endpoint.String(), nil)
+	if err != nil {
+		if errors.Is(err, websocket.ErrBadHandshake) {
+			buf := &bytes.Buffer{}
+			_, _ = io.Copy(buf, response.Body)
+
+			return nil, ClientResponseError{
+				Status:  response.StatusCode,
+				Message: buf.String(),
+			}
+		}
+
+		return nil, err
+	}
+
+	streamch := make(chan cltypes.LeaseMetrics)
+	onclose := make(chan string, 1)
+	metrics := &LeaseMetricsStream{
+		Stream:  streamch,
+		OnClose: onclose,
+	}
+
+	if err = conn.SetReadDeadline(time.Now().Add(pingWait)); err != nil {
+		return nil, err
+	}
+
+	conn.SetPingHandler(func(string) error {
+		err := conn.WriteControl(websocket.PongMessage, nil, time.Now().Add(time.Second))
+		if err != nil {
+			return err
+		}
+
+		return conn.SetReadDeadline(time.Now().Add(pingWait))
+	})
+
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	go func(conn *websocket.Conn) {
+		defer func() {
+			close(streamch)
+			close(onclose)
+			_ = conn.Close()
+		}()
+
+		for {
+			mType, msg, e := conn.ReadMessage()
+			if e != nil {
+				var cerr *websocket.CloseError
+				switch {
+				case ctx.Err() != nil:
+				case errors.As(e, &cerr):
+					onclose <- cerr.Text
+				default:
+					onclose <- e.Error()
+				}
+				return
+			}
+
+			if mType != websocket.TextMessage {
+				continue
+			}
+
+			var obj cltypes.LeaseMetrics
+			if e = json.Unmarshal(msg, &obj); e != nil {
+				onclose <- e.Error()
+				return
+			}
+
+			select {
+			case streamch <- obj:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}(conn)
+
+	return metrics, nil
+}
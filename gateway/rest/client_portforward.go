@@ -0,0 +1,139 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	"github.com/akash-network/node/util/wsutil"
+)
+
+var (
+	errLeasePortForward              = errors.New("lease port forward failed")
+	errLeasePortForwardProtocol      = fmt.Errorf("%w: unexpected message", errLeasePortForward)
+	ErrLeasePortForwardProviderError = fmt.Errorf("%w: the provider encountered an unknown error", errLeasePortForward)
+)
+
+const leasePortForwardBufferSize = 32 * 1024
+
+func (c *client) LeasePortForward(ctx context.Context, lID mtypes.LeaseID, service string, podIndex uint, port uint32, input io.Reader, output io.Writer) error {
+	endpoint, err := url.Parse(c.host.String() + "/" + servicePortForwardPath(lID, service))
+	if err != nil {
+		return err
+	}
+
+	switch endpoint.Scheme {
+	case schemeWSS, schemeHTTPS:
+		endpoint.Scheme = schemeWSS
+	default:
+		return fmt.Errorf("%w: invalid uri scheme %q", errLeasePortForward, endpoint.Scheme)
+	}
+
+	query := url.Values{}
+	query.Set("podIndex", strconv.FormatUint(uint64(podIndex), 10))
+	query.Set("port", strconv.FormatUint(uint64(port), 10))
+
+	endpoint.RawQuery = query.Encode()
+
+	subctx, subcancel := context.WithCancel(ctx)
+	defer subcancel()
+
+	conn, response, err := c.wsclient.DialContext(subctx, endpoint.String(), nil)
+	if err != nil {
+		if errors.Is(err, websocket.ErrBadHandshake) {
+			buf := &bytes.Buffer{}
+			_, _ = io.Copy(buf, response.Body)
+
+			return ClientResponseError{
+				Status:  response.StatusCode,
+				Message: buf.String(),
+			}
+		}
+
+		return err
+	}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-subctx.Done()
+		_ = conn.Close()
+	}()
+
+	l := &sync.Mutex{}
+	data := wsutil.NewWsWriterWrapper(conn, LeasePortForwardCodeData, l)
+	eof := wsutil.NewWsWriterWrapper(conn, LeasePortForwardCodeEOF, l)
+
+	// This goroutine is orphaned until input returns from read, same as stdin of lease shell
+	go func() {
+		buf := make([]byte, leasePortForwardBufferSize)
+		for {
+			n, err := input.Read(buf)
+			if n > 0 {
+				if _, werr := data.Write(buf[:n]); werr != nil {
+					subcancel()
+					return
+				}
+			}
+
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					_, _ = eof.Write([]byte{})
+				} else {
+					subcancel()
+				}
+				return
+			}
+		}
+	}()
+
+	err = nil
+
+loop:
+	for {
+		messageType, msg, rerr := conn.ReadMessage()
+		if rerr != nil {
+			// connection closed by cancelled context is not an error
+			if subctx.Err() == nil {
+				err = rerr
+			}
+			break
+		}
+
+		if messageType != websocket.BinaryMessage || len(msg) == 0 {
+			continue
+		}
+
+		switch msg[0] {
+		case LeasePortForwardCodeData:
+			if _, err = output.Write(msg[1:]); err != nil {
+				break loop
+			}
+		case LeasePortForwardCodeEOF:
+			break loop
+		case LeasePortForwardCodeFailure:
+			err = ErrLeasePortForwardProviderError
+			if len(msg) > 1 {
+				err = fmt.Errorf("%w: %s", errLeasePortForward, msg[1:])
+			}
+			break loop
+		default:
+			err = fmt.Errorf("%w %d", errLeasePortForwardProtocol, msg[0])
+			break loop
+		}
+	}
+
+	subcancel()
+	wg.Wait()
+
+	return err
+}
@@ -30,18 +30,6 @@ func (c *client) LeaseShell(ctx context.Context, lID mtypes.LeaseID, service str
 	tty bool,
 	terminalResize <-chan remotecommand.TerminalSize) error {
 
-	endpoint, err := url.Parse(c.host.String() + "/" + leaseShellPath(lID))
-	if err != nil {
-		return err
-	}
-
-	switch endpoint.Scheme {
-	case schemeWSS, schemeHTTPS:
-		endpoint.Scheme = schemeWSS
-	default:
-		return fmt.Errorf("%w: invalid uri scheme %q", errLeaseShell, endpoint.Scheme)
-	}
-
 	query := url.Values{}
 	query.Set("service", service)
 	query.Set("podIndex", fmt.Sprintf("%d", podIndex))
@@ -61,6 +49,32 @@ func (c *client) LeaseShell(ctx context.Context, lID mtypes.LeaseID, service str
 		query.Set(fmt.Sprintf("cmd%d", i), v)
 	}
 
+	// providers predating lease copy do not know end of stdin message, so it is not sent for shell
+	return c.leaseExec(ctx, leaseShellPath(lID), query, stdin, false, stdout, stderr, tty, terminalResize)
+}
+
+// leaseExec connects to exec websocket endpoint of the provider and streams stdio of the remote process.
+// When stdinEOF is set, remote process is notified once stdin is exhausted
+func (c *client) leaseExec(ctx context.Context, path string, query url.Values,
+	stdin io.ReadCloser,
+	stdinEOF bool,
+	stdout io.Writer,
+	stderr io.Writer,
+	tty bool,
+	terminalResize <-chan remotecommand.TerminalSize) error {
+
+	endpoint, err := url.Parse(c.host.String() + "/" + path)
+	if err != nil {
+		return err
+	}
+
+	switch endpoint.Scheme {
+	case schemeWSS, schemeHTTPS:
+		endpoint.Scheme = schemeWSS
+	default:
+		return fmt.Errorf("%w: invalid uri scheme %q", errLeaseShell, endpoint.Scheme)
+	}
+
 	endpoint.RawQuery = query.Encode()
 	subctx, subcancel := context.WithCancel(ctx)
 	conn, response, err := c.wsclient.DialContext(subctx, endpoint.String(), nil)
@@ -104,9 +118,13 @@ func (c *client) LeaseShell(ctx context.Context, lID mtypes.LeaseID, service str
 
 	if stdin != nil {
 		stdinWriter := wsutil.NewWsWriterWrapper(conn, LeaseShellCodeStdin, l)
+		var eofWriter io.Writer
+		if stdinEOF {
+			eofWriter = wsutil.NewWsWriterWrapper(conn, LeaseShellCodeStdinEOF, l)
+		}
 		// This goroutine is orphaned. There is no universal way to cancel a read from stdin
 		// at this time
-		go handleStdin(subctx, stdin, stdinWriter, saveError)
+		go handleStdin(subctx, stdin, stdinWriter, eofWriter, saveError)
 	}
 
 	if tty && terminalResize != nil {
@@ -200,25 +218,36 @@ func processRemoteError(input io.Reader) error {
 	return nil
 }
 
-func handleStdin(ctx context.Context, input io.Reader, output io.Writer, saveError func(string, error)) {
+func handleStdin(ctx context.Context, input io.Reader, output io.Writer, eof io.Writer, saveError func(string, error)) {
 	data := make([]byte, 4096)
 
 	for {
 		n, err := input.Read(data)
-		if err != nil {
-			saveError("reading from stdin", err)
-			return
+		if n > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			if _, werr := output.Write(data[0:n]); werr != nil {
+				saveError("writing stdin data to remote", werr)
+				return
+			}
 		}
 
-		select {
-		case <-ctx.Done():
+		if errors.Is(err, io.EOF) {
+			// let remote process know there is no more input
+			if eof != nil {
+				if _, err = eof.Write([]byte{}); err != nil {
+					saveError("sending end of stdin to remote", err)
+				}
+			}
 			return
-		default:
 		}
 
-		_, err = output.Write(data[0:n])
 		if err != nil {
-			saveError("writing stdin data to remote", err)
+			saveError("reading from stdin", err)
 			return
 		}
 	}
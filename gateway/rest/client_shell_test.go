@@ -37,14 +37,29 @@ func TestHandleStdinCopiesData(t *testing.T) {
 	reader := strings.NewReader(testMsg)
 	writer := &bytes.Buffer{}
 	ctx, cancel := context.WithCancel(context.Background())
+	eof := &bytes.Buffer{}
 	saveError := func(string, error) {}
 
-	handleStdin(ctx, reader, writer, saveError)
+	handleStdin(ctx, reader, writer, eof, saveError)
 	cancel()
 
 	require.Equal(t, writer.String(), testMsg)
 }
 
+func TestHandleStdinWithoutEOF(t *testing.T) {
+	const testMsg = "testing"
+	reader := strings.NewReader(testMsg)
+	writer := &bytes.Buffer{}
+	errs := 0
+	saveError := func(string, error) { errs++ }
+
+	// shell sessions do not notify end of stdin
+	handleStdin(context.Background(), reader, writer, nil, saveError)
+
+	require.Equal(t, testMsg, writer.String())
+	require.Zero(t, errs)
+}
+
 func TestHandleStdinHalts(t *testing.T) {
 	const testMsg = "testing"
 	reader := strings.NewReader(testMsg)
@@ -54,7 +69,7 @@ func TestHandleStdinHalts(t *testing.T) {
 
 	cancel()
 	// Context is closed, so this just returns
-	handleStdin(ctx, reader, pipeOut, saveError)
+	handleStdin(ctx, reader, pipeOut, &bytes.Buffer{}, saveError)
 
 	require.NoError(t, pipeOut.Close())
 	data, err := io.ReadAll(pipeIn)
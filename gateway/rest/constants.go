@@ -7,4 +7,9 @@ const (
 	LeaseShellCodeFailure        = 103
 	LeaseShellCodeStdin          = 104
 	LeaseShellCodeTerminalResize = 105
+	LeaseShellCodeStdinEOF       = 106
+
+	LeasePortForwardCodeData    = 110
+	LeasePortForwardCodeEOF     = 111
+	LeasePortForwardCodeFailure = 112
 )
@@ -20,6 +20,7 @@ import (
 	"github.com/akash-network/node/testutil"
 
 	"github.com/akash-network/provider"
+	"github.com/akash-network/provider/bidengine"
 	pcmock "github.com/akash-network/provider/cluster/mocks"
 	"github.com/akash-network/provider/cluster/operatorclients"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
@@ -60,10 +61,74 @@ func Test_router_Status(t *testing.T) {
 	})
 }
 
+func Test_router_BidDecisions(t *testing.T) {
+	t.Run("provider", func(t *testing.T) {
+		addr := testutil.AccAddress(t)
+		mocks := createMocks()
+
+		price := testutil.AkashDecCoin(t, 200)
+		mocks.decisions.decisions = []bidengine.BidDecision{
+			{
+				OrderID:  testutil.OrderID(t),
+				Decision: bidengine.DecisionDecline,
+				Reason:   bidengine.ReasonPriceTooHigh,
+				Price:    &price,
+			},
+		}
+
+		withServer(t, addr, mocks.pclient, mocks.qclient, nil, operatorclients.NullIPOperatorClient(), func(host string) {
+			cert := testutil.Certificate(t, addr, testutil.CertificateOptionMocks(mocks.qclient))
+			client, err := NewClient(mocks.qclient, addr, cert.Cert)
+			assert.NoError(t, err)
+			result, err := client.BidDecisions(context.Background(), bidengine.DecisionFilter{DSeq: 10})
+			assert.NoError(t, err)
+			assert.Len(t, result, 1)
+			assert.Equal(t, mocks.decisions.decisions[0].OrderID, result[0].OrderID)
+			assert.Equal(t, bidengine.ReasonPriceTooHigh, result[0].Reason)
+			assert.Equal(t, bidengine.DecisionFilter{DSeq: 10}, mocks.decisions.filter)
+		})
+	})
+
+	t.Run("tenant", func(t *testing.T) {
+		addr := testutil.AccAddress(t)
+		tenant := testutil.AccAddress(t)
+		mocks := createMocks()
+
+		withServer(t, addr, mocks.pclient, mocks.qclient, nil, operatorclients.NullIPOperatorClient(), func(host string) {
+			cert := testutil.Certificate(t, tenant, testutil.CertificateOptionMocks(mocks.qclient))
+			client, err := NewClient(mocks.qclient, addr, cert.Cert)
+			assert.NoError(t, err)
+
+			_, err = client.BidDecisions(context.Background(), bidengine.DecisionFilter{})
+			assert.NoError(t, err)
+			assert.Equal(t, bidengine.DecisionFilter{Owner: tenant.String()}, mocks.decisions.filter)
+
+			_, err = client.BidDecisions(context.Background(), bidengine.DecisionFilter{Owner: testutil.AccAddress(t).String()})
+			assert.Error(t, err)
+		})
+	})
+}
+
 func Test_router_Validate(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		expected := provider.ValidateGroupSpecResult{
 			MinBidPrice: testutil.AkashDecCoin(t, 200),
+			Checks: []bidengine.GroupCheck{
+				{Name: bidengine.ReasonProviderAttributes, Passed: true},
+				{Name: provider.ValidateCheckInsufficientCapacity, Passed: false, Message: "insufficient capacity"},
+			},
+			ClusterParams: &v2beta2.ClusterSettings{
+				SchedulerParams: []*v2beta2.SchedulerParams{
+					{
+						Resources: &v2beta2.SchedulerResources{
+							GPU: &v2beta2.SchedulerResourceGPU{
+								Vendor: "nvidia",
+								Model:  "a100",
+							},
+						},
+					},
+				},
+			},
 		}
 		addr := testutil.AccAddress(t)
 		mocks := createMocks()
@@ -104,7 +169,7 @@ func Test_router_Manifest(t *testing.T) {
 		did := testutil.DeploymentIDForAccount(t, caddr)
 		mocks := createMocks()
 
-		mocks.pmclient.On("Submit", mock.Anything, did, akashmanifest.Manifest(nil)).Return(nil)
+		mocks.pmclient.On("Submit", mock.Anything, did, akashmanifest.Manifest(nil), v2beta2.ManifestProbes{}).Return(nil)
 		withServer(t, paddr, mocks.pclient, mocks.qclient, nil, operatorclients.NullIPOperatorClient(), func(host string) {
 			cert := testutil.Certificate(t, caddr, testutil.CertificateOptionMocks(mocks.qclient))
 			client, err := NewClient(mocks.qclient, paddr, cert.Cert)
@@ -123,7 +188,7 @@ func Test_router_Manifest(t *testing.T) {
 
 		mocks := createMocks()
 
-		mocks.pmclient.On("Submit", mock.Anything, did, akashmanifest.Manifest(nil)).Return(errors.New("ded"))
+		mocks.pmclient.On("Submit", mock.Anything, did, akashmanifest.Manifest(nil), v2beta2.ManifestProbes{}).Return(errors.New("ded"))
 		withServer(t, paddr, mocks.pclient, mocks.qclient, nil, operatorclients.NullIPOperatorClient(), func(host string) {
 			cert := testutil.Certificate(t, caddr, testutil.CertificateOptionMocks(mocks.qclient))
 			client, err := NewClient(mocks.qclient, paddr, cert.Cert)
@@ -153,6 +218,7 @@ func mockManifestGroups(m integrationMocks, leaseID mtypes.LeaseID) {
 		AvailableReplicas:  0,
 	}
 	m.pcclient.On("LeaseStatus", mock.Anything, leaseID).Return(status, nil)
+	m.pcclient.On("GetManifestStatus", mock.Anything, leaseID).Return(v2beta2.ManifestStatus{}, nil)
 	m.pcclient.On("GetManifestGroup", mock.Anything, leaseID).Return(true, v2beta2.ManifestGroup{
 		Name: testGroupName,
 		Services: []v2beta2.ManifestService{{
@@ -306,6 +372,17 @@ type integrationMocks struct {
 	qclient        *qmock.QueryClient
 	hostnameClient *pcmock.HostnameServiceClient
 	clusterService *pcmock.Service
+	decisions      *testDecisionClient
+}
+
+type testDecisionClient struct {
+	decisions []bidengine.BidDecision
+	filter    bidengine.DecisionFilter
+}
+
+func (c *testDecisionClient) Decisions(_ context.Context, filter bidengine.DecisionFilter) ([]bidengine.BidDecision, error) {
+	c.filter = filter
+	return c.decisions, nil
 }
 
 func createMocks() integrationMocks {
@@ -316,6 +393,7 @@ func createMocks() integrationMocks {
 		qclient        = &qmock.QueryClient{}
 		hostnameClient = &pcmock.HostnameServiceClient{}
 		clusterService = &pcmock.Service{}
+		decisions      = &testDecisionClient{}
 	)
 
 	pclient.On("Manifest").Return(pmclient)
@@ -324,6 +402,7 @@ func createMocks() integrationMocks {
 	// TODO - return stubs here when tests are added
 	pclient.On("Hostname").Return(hostnameClient)
 	pclient.On("ClusterService").Return(clusterService)
+	pclient.On("Bidengine").Return(decisions)
 
 	return integrationMocks{
 		pmclient:       pmclient,
@@ -332,6 +411,7 @@ func createMocks() integrationMocks {
 		qclient:        qclient,
 		hostnameClient: hostnameClient,
 		clusterService: clusterService,
+		decisions:      decisions,
 	}
 }
 
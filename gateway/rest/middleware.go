@@ -3,8 +3,10 @@ package rest
 import (
 	"crypto/ecdsa"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/gorilla/context"
@@ -18,6 +20,8 @@ import (
 	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
 	mquery "github.com/akash-network/node/x/market/query"
+
+	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
 )
 
 type contextKey int
@@ -31,6 +35,7 @@ const (
 	ownerContextKey
 	providerContextKey
 	servicesContextKey
+	logOptionsContextKey
 )
 
 func requestLeaseID(req *http.Request) mtypes.LeaseID {
@@ -49,6 +54,10 @@ func requestService(req *http.Request) string {
 	return context.Get(req, serviceContextKey).(string)
 }
 
+func requestLogOptions(req *http.Request) cltypes.LeaseLogsOptions {
+	return context.Get(req, logOptionsContextKey).(cltypes.LeaseLogsOptions)
+}
+
 func requestServices(req *http.Request) string {
 	return context.Get(req, servicesContextKey).(string)
 }
@@ -204,15 +213,94 @@ func requestStreamParams() mux.MiddlewareFunc {
 				tailLines = vl
 			}
 
+			logOpts := cltypes.LeaseLogsOptions{
+				Services:  services,
+				Follow:    follow,
+				TailLines: tailLines,
+			}
+
+			if err = parseLogOptions(vars, &logOpts); err != nil {
+				return
+			}
+
 			context.Set(req, logFollowContextKey, follow)
 			context.Set(req, tailLinesContextKey, tailLines)
 			context.Set(req, servicesContextKey, services)
+			context.Set(req, logOptionsContextKey, logOpts)
 
 			next.ServeHTTP(w, req)
 		})
 	}
 }
 
+// parseLogOptions reads parameters which only apply to logs
+func parseLogOptions(vars url.Values, opts *cltypes.LeaseLogsOptions) error {
+	var err error
+
+	if val := vars.Get("since"); val != "" {
+		var since time.Duration
+		if since, err = time.ParseDuration(val); err != nil {
+			return err
+		}
+
+		if since <= 0 {
+			return errors.Errorf("parameter \"since\" must be positive")
+		}
+
+		// kubernetes counts in whole seconds, round up so the last partial second is included
+		seconds := int64((since + time.Second - 1) / time.Second)
+		opts.SinceSeconds = &seconds
+	}
+
+	if val := vars.Get("since_time"); val != "" {
+		if opts.SinceSeconds != nil {
+			return errors.Errorf("parameters \"since\" and \"since_time\" are mutually exclusive")
+		}
+
+		var since time.Time
+		if since, err = time.Parse(time.RFC3339, val); err != nil {
+			return err
+		}
+		opts.SinceTime = &since
+	}
+
+	if val := vars.Get("previous"); val != "" {
+		if opts.Previous, err = strconv.ParseBool(val); err != nil {
+			return err
+		}
+	}
+
+	if val := vars.Get("timestamps"); val != "" {
+		if opts.Timestamps, err = strconv.ParseBool(val); err != nil {
+			return err
+		}
+	}
+
+	if val := vars.Get("limit_bytes"); val != "" {
+		var limit int64
+		if limit, err = strconv.ParseInt(val, 10, 64); err != nil {
+			return err
+		}
+
+		if limit <= 0 {
+			return errors.Errorf("parameter \"limit_bytes\" must be positive")
+		}
+		opts.LimitBytes = &limit
+	}
+
+	if val := vars.Get("replica"); val != "" {
+		var replica uint64
+		if replica, err = strconv.ParseUint(val, 10, 31); err != nil {
+			return err
+		}
+
+		idx := uint(replica)
+		opts.Replica = &idx
+	}
+
+	return nil
+}
+
 func resourceServerAuth(log log.Logger, providerAddr sdk.Address, publicKey *ecdsa.PublicKey) mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -26,6 +26,10 @@ func validatePath() string {
 	return "validate"
 }
 
+func bidDecisionsPath() string {
+	return "bid-decisions"
+}
+
 func leasePath(id mtypes.LeaseID) string {
 	return fmt.Sprintf("lease/%d/%d/%d", id.DSeq, id.GSeq, id.OSeq)
 }
@@ -53,3 +57,31 @@ func serviceStatusPath(id mtypes.LeaseID, service string) string {
 func serviceLogsPath(id mtypes.LeaseID) string {
 	return fmt.Sprintf("%s/logs", leasePath(id))
 }
+
+func leaseSnapshotsPath(id mtypes.LeaseID) string {
+	return fmt.Sprintf("%s/snapshots", leasePath(id))
+}
+
+func serviceSnapshotsPath(id mtypes.LeaseID, service string) string {
+	return fmt.Sprintf("%s/service/%s/snapshots", leasePath(id), service)
+}
+
+func restoreSnapshotPath(id mtypes.LeaseID, service string, name string) string {
+	return fmt.Sprintf("%s/%s/restore", serviceSnapshotsPath(id, service), name)
+}
+
+func leaseCopyPath(id mtypes.LeaseID) string {
+	return fmt.Sprintf("%s/cp", leasePath(id))
+}
+
+func servicePortForwardPath(id mtypes.LeaseID, service string) string {
+	return fmt.Sprintf("%s/service/%s/portforward", leasePath(id), service)
+}
+
+func leaseMetricsPath(id mtypes.LeaseID) string {
+	return fmt.Sprintf("%s/metrics", leasePath(id))
+}
+
+func serviceRestartPath(id mtypes.LeaseID, service string) string {
+	return fmt.Sprintf("%s/service/%s/restart", leasePath(id), service)
+}
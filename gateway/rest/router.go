@@ -34,6 +34,7 @@ import (
 	"github.com/akash-network/node/util/wsutil"
 
 	"github.com/akash-network/provider"
+	"github.com/akash-network/provider/bidengine"
 	"github.com/akash-network/provider/cluster"
 	"github.com/akash-network/provider/cluster/kube/builder"
 	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
@@ -43,6 +44,7 @@ import (
 	"github.com/akash-network/provider/gateway/utils"
 	pmanifest "github.com/akash-network/provider/manifest"
 	ipoptypes "github.com/akash-network/provider/operator/ipoperator/types"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 )
 
 type CtxAuthKey string
@@ -69,12 +71,13 @@ const (
 )
 
 type wsStreamConfig struct {
-	lid       mtypes.LeaseID
-	services  string
-	follow    bool
-	tailLines *int64
-	log       log.Logger
-	client    cluster.ReadClient
+	lid        mtypes.LeaseID
+	services   string
+	follow     bool
+	tailLines  *int64
+	logOptions cltypes.LeaseLogsOptions
+	log        log.Logger
+	client     cluster.ReadClient
 }
 
 func newRouter(log log.Logger, addr sdk.Address, pclient provider.Client, ipopclient operatorclients.IPOperatorClient, ctxConfig map[interface{}]interface{}) *mux.Router {
@@ -122,6 +125,12 @@ func newRouter(log log.Logger, addr sdk.Address, pclient provider.Client, ipopcl
 		validateHandler(log, pclient)).
 		Methods("GET")
 
+	// GET /bid-decisions
+	// provider owner sees all recorded decisions, tenants only decisions on their own orders
+	vrouter.HandleFunc("/bid-decisions",
+		bidDecisionsHandler(log, pclient.Bidengine())).
+		Methods(http.MethodGet)
+
 	hostnameRouter := router.PathPrefix(hostnamePrefix).Subrouter()
 	hostnameRouter.Use(requireOwner())
 	hostnameRouter.HandleFunc(migratePathPrefix, migrateHandler(log, pclient.Hostname(), pclient.ClusterService())).
@@ -173,6 +182,11 @@ func newRouter(log log.Logger, addr sdk.Address, pclient provider.Client, ipopcl
 		leaseLogsHandler(log, pclient.Cluster())).
 		Methods("GET")
 
+	// GET /lease/<lease-id>/metrics
+	lrouter.HandleFunc("/metrics",
+		leaseMetricsHandler(log, pclient.Cluster())).
+		Methods(http.MethodGet)
+
 	srouter := lrouter.PathPrefix("/service/{serviceName}").Subrouter()
 	srouter.Use(
 		requireService(),
@@ -183,10 +197,38 @@ func newRouter(log log.Logger, addr sdk.Address, pclient provider.Client, ipopcl
 		leaseServiceStatusHandler(log, pclient.Cluster())).
 		Methods("GET")
 
+	// GET /lease/<lease-id>/service/<service-name>/portforward
+	srouter.HandleFunc("/portforward",
+		leasePortForwardHandler(log, pclient.Manifest(), pclient.Cluster()))
+
+	// POST /lease/<lease-id>/service/<service-name>/snapshots
+	srouter.HandleFunc("/snapshots",
+		createSnapshotHandler(log, pclient.Cluster(), ctxConfig)).
+		Methods(http.MethodPost)
+
+	// POST /lease/<lease-id>/service/<service-name>/snapshots/<snapshot>/restore
+	srouter.HandleFunc("/snapshots/{snapshot}/restore",
+		restoreSnapshotHandler(log, pclient.Cluster())).
+		Methods(http.MethodPost)
+
+	// POST /lease/<lease-id>/service/<service-name>/restart
+	srouter.HandleFunc("/restart",
+		restartServiceHandler(log, pclient.Manifest(), pclient.Cluster())).
+		Methods(http.MethodPost)
+
+	// GET /lease/<lease-id>/snapshots
+	lrouter.HandleFunc("/snapshots",
+		leaseSnapshotsHandler(log, pclient.Cluster())).
+		Methods(http.MethodGet)
+
 	// POST /lease/<lease-id>/shell
 	lrouter.HandleFunc("/shell",
 		leaseShellHandler(log, pclient.Manifest(), pclient.Cluster()))
 
+	// GET /lease/<lease-id>/cp
+	lrouter.HandleFunc("/cp",
+		leaseCopyHandler(log, pclient.Manifest(), pclient.Cluster()))
+
 	return router
 }
 
@@ -307,17 +349,7 @@ func leaseShellHandler(log log.Logger, mclient pmanifest.Client, cclient cluster
 	return func(rw http.ResponseWriter, req *http.Request) {
 		leaseID := requestLeaseID(req)
 
-		//  check if deployment actually exists in the first place before querying kubernetes
-		active, err := mclient.IsActive(req.Context(), leaseID.DeploymentID())
-		if err != nil {
-			log.Error("failed checking deployment activity", "err", err)
-			rw.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-
-		if !active {
-			log.Info("no active deployment", "lease", leaseID)
-			rw.WriteHeader(http.StatusNotFound)
+		if !checkLeaseActive(log, rw, req, mclient, leaseID) {
 			return
 		}
 
@@ -347,13 +379,6 @@ func leaseShellHandler(log log.Logger, mclient pmanifest.Client, cclient cluster
 		}
 		isTty := tty == "1"
 
-		service := vars.Get("service")
-		if 0 == len(service) {
-			localLog.Error("missing parameter service")
-			rw.WriteHeader(http.StatusBadRequest)
-			return
-		}
-
 		stdin := vars.Get("stdin")
 		if 0 == len(stdin) {
 			localLog.Error("missing parameter stdin")
@@ -362,116 +387,166 @@ func leaseShellHandler(log log.Logger, mclient pmanifest.Client, cclient cluster
 		}
 		connectStdin := stdin == "1"
 
-		podIndexStr := vars.Get("podIndex")
-		if len(podIndexStr) == 0 {
-			localLog.Error("missing parameter podIndex")
-			rw.WriteHeader(http.StatusBadRequest)
+		service, podIndex, valid := requestExecTarget(localLog, rw, vars)
+		if !valid {
 			return
 		}
 
-		podIndex64, err := strconv.ParseUint(podIndexStr, 0, 31)
-		if err != nil {
-			localLog.Error("parameter podIndex invalid", "err", err)
-			rw.WriteHeader(http.StatusBadRequest)
-			return
-		}
-		podIndex := uint(podIndex64)
+		leaseExec(localLog, rw, req, cclient, leaseID, service, podIndex, cmd, isTty, connectStdin)
+	}
+}
 
-		upgrader := websocket.Upgrader{
-			ReadBufferSize:  0,
-			WriteBufferSize: 0,
-		}
+// checkLeaseActive verifies deployment actually exists in the first place before querying kubernetes
+func checkLeaseActive(log log.Logger, rw http.ResponseWriter, req *http.Request, mclient pmanifest.Client, leaseID mtypes.LeaseID) bool {
+	active, err := mclient.IsActive(req.Context(), leaseID.DeploymentID())
+	if err != nil {
+		log.Error("failed checking deployment activity", "err", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return false
+	}
 
-		shellWs, err := upgrader.Upgrade(rw, req, nil)
-		if err != nil {
-			// At this point the connection either has a response sent already
-			// or it has been closed
-			localLog.Error("failed handshake", "err", err)
-			return
-		}
+	if !active {
+		log.Info("no active deployment", "lease", leaseID)
+		rw.WriteHeader(http.StatusNotFound)
+		return false
+	}
 
-		var stdinPipeOut *io.PipeWriter
-		var stdinPipeIn *io.PipeReader
-		wg := &sync.WaitGroup{}
+	return true
+}
 
-		var tsq remotecommand.TerminalSizeQueue
-		var terminalSizeUpdate chan remotecommand.TerminalSize
-		if isTty {
-			terminalSizeUpdate = make(chan remotecommand.TerminalSize, 1)
-			tsq = channelToTerminalSizeQueue(terminalSizeUpdate)
-		}
+// requestExecTarget parses service name and replica index of the exec request
+func requestExecTarget(log log.Logger, rw http.ResponseWriter, vars url.Values) (string, uint, bool) {
+	service := vars.Get("service")
+	if 0 == len(service) {
+		log.Error("missing parameter service")
+		rw.WriteHeader(http.StatusBadRequest)
+		return "", 0, false
+	}
 
-		if connectStdin {
-			stdinPipeIn, stdinPipeOut = io.Pipe()
+	podIndex, valid := requestPodIndex(log, rw, vars)
+	if !valid {
+		return "", 0, false
+	}
 
-			wg.Add(1)
-			go leaseShellWebsocketHandler(localLog, wg, shellWs, stdinPipeOut, terminalSizeUpdate)
+	return service, podIndex, true
+}
 
-		}
+func requestPodIndex(log log.Logger, rw http.ResponseWriter, vars url.Values) (uint, bool) {
+	podIndexStr := vars.Get("podIndex")
+	if len(podIndexStr) == 0 {
+		log.Error("missing parameter podIndex")
+		rw.WriteHeader(http.StatusBadRequest)
+		return 0, false
+	}
 
-		l := &sync.Mutex{}
-		stdout := wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeStdout, l)
-		stderr := wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeStderr, l)
+	podIndex64, err := strconv.ParseUint(podIndexStr, 0, 31)
+	if err != nil {
+		log.Error("parameter podIndex invalid", "err", err)
+		rw.WriteHeader(http.StatusBadRequest)
+		return 0, false
+	}
+
+	return uint(podIndex64), true
+}
+
+// leaseExec upgrades request to websocket and runs cmd in the selected replica of the service,
+// streaming stdio and result of the command over websocket
+func leaseExec(localLog log.Logger, rw http.ResponseWriter, req *http.Request, cclient cluster.Client, leaseID mtypes.LeaseID,
+	service string, podIndex uint, cmd []string, isTty bool, connectStdin bool) {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  0,
+		WriteBufferSize: 0,
+	}
+
+	shellWs, err := upgrader.Upgrade(rw, req, nil)
+	if err != nil {
+		// At this point the connection either has a response sent already
+		// or it has been closed
+		localLog.Error("failed handshake", "err", err)
+		return
+	}
+
+	var stdinPipeOut *io.PipeWriter
+	var stdinPipeIn *io.PipeReader
+	wg := &sync.WaitGroup{}
+
+	var tsq remotecommand.TerminalSizeQueue
+	var terminalSizeUpdate chan remotecommand.TerminalSize
+	if isTty {
+		terminalSizeUpdate = make(chan remotecommand.TerminalSize, 1)
+		tsq = channelToTerminalSizeQueue(terminalSizeUpdate)
+	}
+
+	if connectStdin {
+		stdinPipeIn, stdinPipeOut = io.Pipe()
 
-		subctx, subcancel := context.WithCancel(req.Context())
 		wg.Add(1)
-		go leaseShellPingHandler(subctx, wg, shellWs)
+		go leaseShellWebsocketHandler(localLog, wg, shellWs, stdinPipeOut, terminalSizeUpdate)
 
-		var stdinForExec io.Reader
-		if connectStdin {
-			stdinForExec = stdinPipeIn
-		}
-		result, err := cclient.Exec(subctx, leaseID, service, podIndex, cmd, stdinForExec, stdout, stderr, isTty, tsq)
-		subcancel()
+	}
 
-		responseData := leaseShellResponse{}
-		var resultWriter io.Writer
-		encodeData := true
-		resultWriter = wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeResult, l)
+	l := &sync.Mutex{}
+	stdout := wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeStdout, l)
+	stderr := wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeStderr, l)
 
-		if result != nil {
-			responseData.ExitCode = result.ExitCode()
+	subctx, subcancel := context.WithCancel(req.Context())
+	wg.Add(1)
+	go leaseShellPingHandler(subctx, wg, shellWs)
 
-			localLog.Info("lease shell completed", "exitcode", result.ExitCode())
-		} else {
-			if cluster.ErrorIsOkToSendToClient(err) {
-				responseData.Message = err.Error()
-			} else {
-				resultWriter = wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeFailure, l)
-				// Don't return errors like this to the client, they could contain information
-				// that should not be let out
-				encodeData = false
+	var stdinForExec io.Reader
+	if connectStdin {
+		stdinForExec = stdinPipeIn
+	}
+	result, err := cclient.Exec(subctx, leaseID, service, podIndex, cmd, stdinForExec, stdout, stderr, isTty, tsq)
+	subcancel()
 
-				localLog.Error("lease exec failed", "err", err)
-			}
-		}
+	responseData := leaseShellResponse{}
+	var resultWriter io.Writer
+	encodeData := true
+	resultWriter = wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeResult, l)
 
-		if encodeData {
-			encoder := json.NewEncoder(resultWriter)
-			err = encoder.Encode(responseData)
+	if result != nil {
+		responseData.ExitCode = result.ExitCode()
+
+		localLog.Info("lease shell completed", "exitcode", result.ExitCode())
+	} else {
+		if cluster.ErrorIsOkToSendToClient(err) {
+			responseData.Message = err.Error()
 		} else {
-			// Just send an empty message so the remote knows things are over
-			_, err = resultWriter.Write([]byte{})
+			resultWriter = wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeFailure, l)
+			// Don't return errors like this to the client, they could contain information
+			// that should not be let out
+			encodeData = false
+
+			localLog.Error("lease exec failed", "err", err)
 		}
+	}
 
-		_ = shellWs.Close()
+	if encodeData {
+		encoder := json.NewEncoder(resultWriter)
+		err = encoder.Encode(responseData)
+	} else {
+		// Just send an empty message so the remote knows things are over
+		_, err = resultWriter.Write([]byte{})
+	}
 
-		if err != nil {
-			localLog.Error("failed writing response to client after exec", "err", err)
-		}
+	_ = shellWs.Close()
 
-		wg.Wait()
+	if err != nil {
+		localLog.Error("failed writing response to client after exec", "err", err)
+	}
 
-		if stdinPipeOut != nil {
-			_ = stdinPipeOut.Close()
-		}
-		if stdinPipeIn != nil {
-			_ = stdinPipeIn.Close()
-		}
+	wg.Wait()
 
-		if terminalSizeUpdate != nil {
-			close(terminalSizeUpdate)
-		}
+	if stdinPipeOut != nil {
+		_ = stdinPipeOut.Close()
+	}
+	if stdinPipeIn != nil {
+		_ = stdinPipeIn.Close()
+	}
+
+	if terminalSizeUpdate != nil {
+		close(terminalSizeUpdate)
 	}
 }
 
@@ -555,23 +630,94 @@ func validateHandler(log log.Logger, cl provider.ValidateClient) http.HandlerFun
 	}
 }
 
+func bidDecisionsHandler(log log.Logger, dclient bidengine.DecisionClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := bidengine.DecisionFilter{}
+
+		vars := req.URL.Query()
+
+		if val := vars.Get("owner"); val != "" {
+			if _, err := sdk.AccAddressFromBech32(val); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			filter.Owner = val
+		}
+
+		if val := vars.Get("dseq"); val != "" {
+			dseq, err := strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			filter.DSeq = dseq
+		}
+
+		if val := vars.Get("gseq"); val != "" {
+			gseq, err := strconv.ParseUint(val, 10, 32)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			filter.GSeq = uint32(gseq)
+		}
+
+		if val := vars.Get("oseq"); val != "" {
+			oseq, err := strconv.ParseUint(val, 10, 32)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			filter.OSeq = uint32(oseq)
+		}
+
+		// decisions on orders of other tenants are visible to the provider only
+		if owner := requestOwner(req); !owner.Equals(requestProvider(req)) {
+			if filter.Owner != "" && filter.Owner != owner.String() {
+				http.Error(w, "", http.StatusForbidden)
+				return
+			}
+			filter.Owner = owner.String()
+		}
+
+		decisions, err := dclient.Decisions(req.Context(), filter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(log, w, decisions)
+	}
+}
+
 func createManifestHandler(log log.Logger, mclient pmanifest.Client) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		var mani manifest.Manifest
-		decoder := json.NewDecoder(req.Body)
 		defer func() {
 			_ = req.Body.Close()
 		}()
 
-		if err := decoder.Decode(&mani); err != nil {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var mani manifest.Manifest
+		if err = json.Unmarshal(data, &mani); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
+		probes, err := crd.ParseManifestProbes(data)
+		if err != nil {
 			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 			return
 		}
 
 		subctx, cancel := context.WithTimeout(req.Context(), manifestSubmitTimeout)
 		defer cancel()
-		if err := mclient.Submit(subctx, requestDeploymentID(req), mani); err != nil {
-			if errors.Is(err, manifestValidation.ErrInvalidManifest) {
+		if err = mclient.Submit(subctx, requestDeploymentID(req), mani, probes); err != nil {
+			if errors.Is(err, manifestValidation.ErrInvalidManifest) || errors.Is(err, pmanifest.ErrImageNotAllowed) {
 				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 				return
 			}
@@ -628,6 +774,20 @@ func leaseStatusHandler(log log.Logger, cclient cluster.ReadClient, ipopclient o
 			return
 		}
 
+		// rollback status is informational, lease status is reported without it if it cannot be read
+		mstatus, err := cclient.GetManifestStatus(ctx, leaseID)
+		switch {
+		case err == nil:
+			if mstatus.State == crd.ManifestStateRolledBack {
+				result.Rollback = &LeaseRollbackStatus{
+					Reason: mstatus.Message,
+				}
+			}
+		case errors.Is(err, kubeclienterrors.ErrNoManifestForLease):
+		default:
+			log.Error("reading manifest status", "lease", leaseID, "err", err)
+		}
+
 		hasLeasedIPs := false
 		if ipopclient != nil {
 		ipManifestGroupSearchLoop:
@@ -746,12 +906,13 @@ func leaseLogsHandler(log log.Logger, cclient cluster.ReadClient) http.HandlerFu
 		}
 
 		wsLogWriter(r.Context(), ws, wsStreamConfig{
-			lid:       requestLeaseID(r),
-			services:  requestServices(r),
-			follow:    requestLogFollow(r),
-			tailLines: requestLogTailLines(r),
-			log:       log,
-			client:    cclient,
+			lid:        requestLeaseID(r),
+			services:   requestServices(r),
+			follow:     requestLogFollow(r),
+			tailLines:  requestLogTailLines(r),
+			logOptions: requestLogOptions(r),
+			log:        log,
+			client:     cclient,
 		})
 	}
 }
@@ -799,7 +960,7 @@ func wsLogWriter(ctx context.Context, ws *websocket.Conn, cfg wsStreamConfig) {
 		_ = ws.Close()
 	}()
 
-	logs, err := cfg.client.LeaseLogs(cctx, cfg.lid, cfg.services, cfg.follow, cfg.tailLines)
+	logs, err := cfg.client.LeaseLogs(cctx, cfg.lid, cfg.logOptions)
 	if err != nil {
 		cfg.log.Error("couldn't fetch logs", "error", err.Error())
 		err = ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocketInternalServerErrorCode, ""))
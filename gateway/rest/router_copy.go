@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/akash-network/provider/cluster"
+	pmanifest "github.com/akash-network/provider/manifest"
+)
+
+var (
+	errLeaseCopyInvalidPath = errors.New("path must be absolute")
+	errLeaseCopyInvalidOp   = errors.New("invalid copy operation")
+)
+
+// leaseCopyCommand builds command executed in the container for the copy request.
+// Paths are passed to the shell as positional arguments, so they are never interpreted by it
+func leaseCopyCommand(op LeaseCopyOp, p string, raw bool, offset uint64, length uint64) ([]string, error) {
+	if !path.IsAbs(p) {
+		return nil, errLeaseCopyInvalidPath
+	}
+
+	p = path.Clean(p)
+
+	switch op {
+	case LeaseCopyOpStat:
+		return []string{"sh", "-c", `test -f "$1" && wc -c < "$1"`, "sh", p}, nil
+	case LeaseCopyOpDownload:
+		if !raw {
+			dir, name := path.Split(p)
+			if name == "" {
+				name = "."
+			}
+			return []string{"tar", "cf", "-", "-C", dir, name}, nil
+		}
+
+		if length == 0 {
+			return []string{"sh", "-c", `test -f "$2" && tail -c +"$1" "$2"`, "sh", strconv.FormatUint(offset+1, 10), p}, nil
+		}
+
+		return []string{"sh", "-c", `test -f "$3" && tail -c +"$1" "$3" | head -c "$2"`, "sh",
+			strconv.FormatUint(offset+1, 10), strconv.FormatUint(length, 10), p}, nil
+	case LeaseCopyOpUpload:
+		if !raw {
+			return []string{"sh", "-c", `mkdir -p "$1" && tar xmf - -C "$1"`, "sh", p}, nil
+		}
+
+		if offset == 0 {
+			return []string{"sh", "-c", `mkdir -p "$(dirname "$1")" && cat > "$1"`, "sh", p}, nil
+		}
+
+		// resumed upload continues at offset, anything past it is left from interrupted chunk
+		return []string{"sh", "-c", `test "$(wc -c < "$2")" -ge "$1" && truncate -s "$1" "$2" && cat >> "$2"`, "sh",
+			strconv.FormatUint(offset, 10), p}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", errLeaseCopyInvalidOp, op)
+	}
+}
+
+func leaseCopyHandler(log log.Logger, mclient pmanifest.Client, cclient cluster.Client) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		leaseID := requestLeaseID(req)
+
+		if !checkLeaseActive(log, rw, req, mclient, leaseID) {
+			return
+		}
+
+		localLog := log.With("lease", leaseID.String(), "action", "cp")
+
+		vars := req.URL.Query()
+
+		service, podIndex, valid := requestExecTarget(localLog, rw, vars)
+		if !valid {
+			return
+		}
+
+		raw := vars.Get("raw") == "1"
+
+		var offset, length uint64
+		if raw {
+			var err error
+			if offset, err = strconv.ParseUint(vars.Get("offset"), 10, 64); err != nil {
+				localLog.Error("parameter offset invalid", "err", err)
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			if length, err = strconv.ParseUint(vars.Get("length"), 10, 64); err != nil {
+				localLog.Error("parameter length invalid", "err", err)
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+
+		op := LeaseCopyOp(vars.Get("op"))
+
+		cmd, err := leaseCopyCommand(op, vars.Get("path"), raw, offset, length)
+		if err != nil {
+			localLog.Error("invalid copy request", "err", err)
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		leaseExec(localLog, rw, req, cclient, leaseID, service, podIndex, cmd, false, op == LeaseCopyOpUpload)
+	}
+}
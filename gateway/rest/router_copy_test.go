@@ -0,0 +1,124 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	"github.com/akash-network/node/testutil"
+)
+
+type testExecResult int
+
+func (r testExecResult) ExitCode() int {
+	return int(r)
+}
+
+func TestLeaseCopyCommand(t *testing.T) {
+	cmd, err := leaseCopyCommand(LeaseCopyOpDownload, "/var/lib/data/", false, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tar", "cf", "-", "-C", "/var/lib/", "data"}, cmd)
+
+	cmd, err = leaseCopyCommand(LeaseCopyOpDownload, "/", false, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tar", "cf", "-", "-C", "/", "."}, cmd)
+
+	cmd, err = leaseCopyCommand(LeaseCopyOpDownload, "/data/db.bin", true, 100, 50)
+	require.NoError(t, err)
+	require.Equal(t, []string{"101", "50", "/data/db.bin"}, cmd[4:])
+
+	cmd, err = leaseCopyCommand(LeaseCopyOpUpload, "/data", false, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, "/data", cmd[len(cmd)-1])
+
+	cmd, err = leaseCopyCommand(LeaseCopyOpUpload, "/data/db.bin", true, 100, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"100", "/data/db.bin"}, cmd[4:])
+
+	// paths never end up in the script itself
+	cmd, err = leaseCopyCommand(LeaseCopyOpStat, "/tmp/$(reboot)", false, 0, 0)
+	require.NoError(t, err)
+	require.NotContains(t, cmd[2], "reboot")
+	require.Equal(t, "/tmp/$(reboot)", cmd[4])
+
+	_, err = leaseCopyCommand(LeaseCopyOpStat, "data", false, 0, 0)
+	require.ErrorIs(t, err, errLeaseCopyInvalidPath)
+
+	_, err = leaseCopyCommand("move", "/data", false, 0, 0)
+	require.ErrorIs(t, err, errLeaseCopyInvalidOp)
+}
+
+func TestRouteLeaseCopy(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		ctx := context.Background()
+		lid := mtypes.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),
+			GSeq:     uint32(testutil.RandRangeInt(1, 1000)),
+			OSeq:     uint32(testutil.RandRangeInt(1, 1000)),
+			Provider: test.paddr.String(),
+		}
+
+		test.pmclient.On("IsActive", mock.Anything, lid.DeploymentID()).Return(true, nil)
+
+		uploaded := &bytes.Buffer{}
+		uploadCmd, err := leaseCopyCommand(LeaseCopyOpUpload, "/data/file.bin", true, 0, 0)
+		require.NoError(t, err)
+
+		test.pcclient.On("Exec", mock.Anything, lid, serviceName, uint(1), uploadCmd, mock.Anything, mock.Anything, mock.Anything, false, mock.Anything).
+			Run(func(args mock.Arguments) {
+				// stdin is closed once client has sent all of the data
+				_, err := io.Copy(uploaded, args.Get(5).(io.Reader))
+				require.NoError(t, err)
+			}).
+			Return(testExecResult(0), nil).Once()
+
+		err = test.gwclient.LeaseCopy(ctx, lid, LeaseCopyRequest{
+			Service:  serviceName,
+			PodIndex: 1,
+			Op:       LeaseCopyOpUpload,
+			Path:     "/data/file.bin",
+			Raw:      true,
+		}, io.NopCloser(strings.NewReader("binary\x00data")), nil)
+		require.NoError(t, err)
+		require.Equal(t, "binary\x00data", uploaded.String())
+
+		statCmd, err := leaseCopyCommand(LeaseCopyOpStat, "/data/file.bin", false, 0, 0)
+		require.NoError(t, err)
+
+		test.pcclient.On("Exec", mock.Anything, lid, serviceName, uint(0), statCmd, nil, mock.Anything, mock.Anything, false, mock.Anything).
+			Run(func(args mock.Arguments) {
+				_, _ = args.Get(6).(io.Writer).Write([]byte("11\n"))
+			}).
+			Return(testExecResult(0), nil).Once()
+
+		size, err := test.gwclient.LeaseFileSize(ctx, lid, serviceName, 0, "/data/file.bin")
+		require.NoError(t, err)
+		require.Equal(t, uint64(11), size)
+
+		downloadCmd, err := leaseCopyCommand(LeaseCopyOpDownload, "/data/missing", false, 0, 0)
+		require.NoError(t, err)
+
+		test.pcclient.On("Exec", mock.Anything, lid, serviceName, uint(0), downloadCmd, nil, mock.Anything, mock.Anything, false, mock.Anything).
+			Run(func(args mock.Arguments) {
+				_, _ = args.Get(7).(io.Writer).Write([]byte("tar: missing: No such file or directory\n"))
+			}).
+			Return(testExecResult(2), nil).Once()
+
+		err = test.gwclient.LeaseCopy(ctx, lid, LeaseCopyRequest{
+			Service: serviceName,
+			Op:      LeaseCopyOpDownload,
+			Path:    "/data/missing",
+		}, nil, io.Discard)
+		require.ErrorIs(t, err, errLeaseShell)
+		require.Contains(t, err.Error(), "No such file or directory")
+
+		test.pcclient.AssertExpectations(t)
+	})
+}
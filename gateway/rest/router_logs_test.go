@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	"github.com/akash-network/node/testutil"
+
+	"github.com/akash-network/provider/cluster"
+	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+func TestParseLogOptions(t *testing.T) {
+	opts := cltypes.LeaseLogsOptions{}
+	err := parseLogOptions(url.Values{
+		"since":       []string{"90500ms"},
+		"previous":    []string{"true"},
+		"timestamps":  []string{"1"},
+		"limit_bytes": []string{"4096"},
+		"replica":     []string{"2"},
+	}, &opts)
+	require.NoError(t, err)
+	require.Equal(t, int64(91), *opts.SinceSeconds)
+	require.True(t, opts.Previous)
+	require.True(t, opts.Timestamps)
+	require.Equal(t, int64(4096), *opts.LimitBytes)
+	require.Equal(t, uint(2), *opts.Replica)
+
+	for _, vars := range []url.Values{
+		{"since": []string{"-1m"}},
+		{"since": []string{"1m"}, "since_time": []string{"2023-10-17T12:00:00Z"}},
+		{"since_time": []string{"yesterday"}},
+		{"limit_bytes": []string{"0"}},
+		{"replica": []string{"-1"}},
+	} {
+		require.Error(t, parseLogOptions(vars, &cltypes.LeaseLogsOptions{}), vars.Encode())
+	}
+}
+
+func TestRouteLeaseLogsOptions(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := mtypes.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),
+			GSeq:     uint32(testutil.RandRangeInt(1, 1000)),
+			OSeq:     uint32(testutil.RandRangeInt(1, 1000)),
+			Provider: test.paddr.String(),
+		}
+
+		tail := int64(10)
+		limit := int64(1024)
+		replica := uint(1)
+		since := time.Date(2023, 10, 17, 12, 0, 0, 0, time.UTC)
+
+		opts := cltypes.LeaseLogsOptions{
+			Services:   serviceName,
+			TailLines:  &tail,
+			SinceTime:  &since,
+			Previous:   true,
+			Timestamps: true,
+			LimitBytes: &limit,
+			Replica:    &replica,
+		}
+
+		stream := io.NopCloser(strings.NewReader("2023-10-17T12:00:01Z panic: boom\n"))
+		test.pcclient.On("LeaseLogs", mock.Anything, lid, opts).
+			Return([]*cltypes.ServiceLog{cluster.NewServiceLog(serviceName+"-1", stream)}, nil).Once()
+
+		logs, err := test.gwclient.LeaseLogs(context.Background(), lid, opts)
+		require.NoError(t, err)
+
+		line, ok := <-logs.Stream
+		require.True(t, ok)
+		require.Equal(t, serviceName+"-1", line.Name)
+		require.Equal(t, "2023-10-17T12:00:01Z panic: boom", line.Message)
+
+		for range logs.Stream {
+		}
+
+		seconds := int64(60)
+		opts.SinceSeconds = &seconds
+
+		_, err = test.gwclient.LeaseLogs(context.Background(), lid, opts)
+		var cerr ClientResponseError
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusBadRequest, cerr.Status)
+
+		test.pcclient.AssertExpectations(t)
+	})
+}
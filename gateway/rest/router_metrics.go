@@ -0,0 +1,239 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tendermint/tendermint/libs/log"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+
+	"github.com/akash-network/provider/cluster"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+const (
+	contentTypePrometheus = "text/plain; version=0.0.4; charset=utf-8"
+
+	metricsFormatJSON       = "json"
+	metricsFormatPrometheus = "prometheus"
+
+	// metrics-server scrapes kubelets every 15s by default, no point to poll it much faster
+	metricsStreamDefaultInterval = 10 * time.Second
+	metricsStreamMinInterval     = time.Second
+)
+
+var (
+	errMetricsInvalidFormat   = errors.New("invalid metrics format")
+	errMetricsInvalidInterval = fmt.Errorf("invalid metrics interval, must be at least %s", metricsStreamMinInterval)
+)
+
+// leaseMetricsHandler serves snapshot of lease resource usage, or streams it
+// at requested interval when client upgrades connection to websocket
+func leaseMetricsHandler(log log.Logger, cclient cluster.ReadClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		leaseID := requestLeaseID(req)
+		vars := req.URL.Query()
+
+		if websocket.IsWebSocketUpgrade(req) {
+			interval := metricsStreamDefaultInterval
+			if val := vars.Get("interval"); val != "" {
+				var err error
+				if interval, err = time.ParseDuration(val); err != nil || interval < metricsStreamMinInterval {
+					http.Error(w, errMetricsInvalidInterval.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+
+			upgrader := websocket.Upgrader{
+				ReadBufferSize:  1024,
+				WriteBufferSize: 1024,
+			}
+
+			ws, err := upgrader.Upgrade(w, req, nil)
+			if err != nil {
+				// At this point the connection either has a response sent already
+				// or it has been closed
+				return
+			}
+
+			wsMetricsWriter(req.Context(), ws, log, cclient, leaseID, interval)
+			return
+		}
+
+		format := vars.Get("format")
+		if format != "" && format != metricsFormatJSON && format != metricsFormatPrometheus {
+			http.Error(w, errMetricsInvalidFormat.Error(), http.StatusBadRequest)
+			return
+		}
+
+		metrics, err := cclient.LeaseMetrics(req.Context(), leaseID)
+		if err != nil {
+			if errors.Is(err, kubeclienterrors.ErrLeaseNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			log.Error("reading lease metrics", "lease", leaseID, "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if format != metricsFormatPrometheus {
+			writeJSON(log, w, metrics)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentTypePrometheus)
+		if err = writeLeaseMetricsPrometheus(w, leaseID, metrics); err != nil {
+			log.Error("error writing response", "err", err)
+		}
+	}
+}
+
+func wsMetricsWriter(ctx context.Context, ws *websocket.Conn, log log.Logger, cclient cluster.ReadClient, leaseID mtypes.LeaseID, interval time.Duration) {
+	pingTicker := time.NewTicker(pingPeriod)
+	metricsTicker := time.NewTicker(interval)
+
+	cctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		pingTicker.Stop()
+		metricsTicker.Stop()
+		cancel()
+		_ = ws.Close()
+	}()
+
+	if err := wsSetupPongHandler(ws, cancel); err != nil {
+		return
+	}
+
+	send := func() bool {
+		metrics, err := cclient.LeaseMetrics(cctx, leaseID)
+		if err != nil {
+			if cctx.Err() != nil {
+				return false
+			}
+
+			msg := websocket.FormatCloseMessage(websocketInternalServerErrorCode, "")
+			if errors.Is(err, kubeclienterrors.ErrLeaseNotFound) {
+				msg = websocket.FormatCloseMessage(websocketLeaseNotFound, err.Error())
+			} else {
+				log.Error("reading lease metrics", "lease", leaseID, "err", err)
+			}
+
+			_ = ws.WriteMessage(websocket.CloseMessage, msg)
+			return false
+		}
+
+		return ws.WriteJSON(metrics) == nil
+	}
+
+	if !send() {
+		return
+	}
+
+	for {
+		select {
+		case <-cctx.Done():
+			_ = ws.WriteMessage(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+		case <-metricsTicker.C:
+			if !send() {
+				return
+			}
+		case <-pingTicker.C:
+			if err := ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second)); err != nil {
+				return
+			}
+			if err := ws.SetReadDeadline(time.Now().Add(pongWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeLeaseMetricsPrometheus renders lease metrics in prometheus text exposition format.
+// Every sample carries lease and pod labels, so outputs of many leases can be scraped into single job
+func writeLeaseMetricsPrometheus(w io.Writer, leaseID mtypes.LeaseID, metrics *cltypes.LeaseMetrics) error {
+	type family struct {
+		name  string
+		help  string
+		kind  string
+		value func(cltypes.PodMetrics) *uint64
+	}
+
+	families := []family{
+		{
+			name:  "akash_lease_cpu_usage_millicores",
+			help:  "CPU used by the pod in millicores",
+			kind:  "gauge",
+			value: func(pm cltypes.PodMetrics) *uint64 { return &pm.CPU },
+		},
+		{
+			name:  "akash_lease_memory_usage_bytes",
+			help:  "Working set memory of the pod in bytes",
+			kind:  "gauge",
+			value: func(pm cltypes.PodMetrics) *uint64 { return &pm.Memory },
+		},
+		{
+			name:  "akash_lease_gpu_allocated",
+			help:  "GPU devices allocated to the pod",
+			kind:  "gauge",
+			value: func(pm cltypes.PodMetrics) *uint64 { return &pm.GPU },
+		},
+	}
+
+	// network counters are only reported by kubelet
+	if metrics.Source == cltypes.MetricsSourceKubelet {
+		families = append(families,
+			family{
+				name:  "akash_lease_network_receive_bytes_total",
+				help:  "Bytes received by the pod",
+				kind:  "counter",
+				value: func(pm cltypes.PodMetrics) *uint64 { return pm.NetworkRx },
+			},
+			family{
+				name:  "akash_lease_network_transmit_bytes_total",
+				help:  "Bytes transmitted by the pod",
+				kind:  "counter",
+				value: func(pm cltypes.PodMetrics) *uint64 { return pm.NetworkTx },
+			})
+	}
+
+	services := make([]string, 0, len(metrics.Services))
+	for name := range metrics.Services {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+
+	leaseLabels := fmt.Sprintf("owner=%q,dseq=\"%d\",gseq=\"%d\",oseq=\"%d\"", leaseID.Owner, leaseID.DSeq, leaseID.GSeq, leaseID.OSeq)
+
+	buf := &strings.Builder{}
+	for _, fm := range families {
+		fmt.Fprintf(buf, "# HELP %s %s\n", fm.name, fm.help)
+		fmt.Fprintf(buf, "# TYPE %s %s\n", fm.name, fm.kind)
+
+		for _, name := range services {
+			for _, pod := range metrics.Services[name].Pods {
+				// samples not reported by the pod are omitted
+				val := fm.value(pod)
+				if val == nil {
+					continue
+				}
+				fmt.Fprintf(buf, "%s{%s,service=%q,pod=%q} %d\n", fm.name, leaseLabels, name, pod.Name, *val)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
@@ -0,0 +1,146 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	"github.com/akash-network/node/testutil"
+
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+func metricsTestLeaseID(test *routerTest) mtypes.LeaseID {
+	return mtypes.LeaseID{
+		Owner:    test.caddr.String(),
+		DSeq:     uint64(testutil.RandRangeInt(1, 1000)),
+		GSeq:     uint32(testutil.RandRangeInt(1, 1000)),
+		OSeq:     uint32(testutil.RandRangeInt(1, 1000)),
+		Provider: test.paddr.String(),
+	}
+}
+
+func testLeaseMetrics(source string) *cltypes.LeaseMetrics {
+	pod := cltypes.PodMetrics{
+		Name:      serviceName + "-0",
+		CPU:       250,
+		Memory:    1024,
+		Timestamp: time.Date(2023, 10, 17, 12, 0, 0, 0, time.UTC),
+	}
+
+	service := &cltypes.ServiceMetrics{
+		CPU:    250,
+		Memory: 1024,
+	}
+
+	// network counters are only reported by kubelet
+	if source == cltypes.MetricsSourceKubelet {
+		rx, tx := uint64(10), uint64(20)
+		pod.NetworkRx, pod.NetworkTx = &rx, &tx
+		service.NetworkRx, service.NetworkTx = &rx, &tx
+	}
+
+	service.Pods = []cltypes.PodMetrics{pod}
+
+	return &cltypes.LeaseMetrics{
+		Source: source,
+		Services: map[string]*cltypes.ServiceMetrics{
+			serviceName: service,
+		},
+	}
+}
+
+func TestRouteLeaseMetrics(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		ctx := context.Background()
+		lid := metricsTestLeaseID(test)
+		metrics := testLeaseMetrics(cltypes.MetricsSourceKubelet)
+
+		test.pcclient.On("LeaseMetrics", mock.Anything, lid).Return(metrics, nil)
+
+		res, err := test.gwclient.LeaseMetrics(ctx, lid)
+		require.NoError(t, err)
+		require.Equal(t, metrics, res)
+
+		data, err := test.gwclient.LeaseMetricsPrometheus(ctx, lid)
+		require.NoError(t, err)
+
+		labels := fmt.Sprintf(`owner="%s",dseq="%d",gseq="%d",oseq="%d",service="%s",pod="%s-0"`,
+			lid.Owner, lid.DSeq, lid.GSeq, lid.OSeq, serviceName, serviceName)
+
+		require.Contains(t, string(data), "# TYPE akash_lease_cpu_usage_millicores gauge\n")
+		require.Contains(t, string(data), "akash_lease_cpu_usage_millicores{"+labels+"} 250\n")
+		require.Contains(t, string(data), "akash_lease_memory_usage_bytes{"+labels+"} 1024\n")
+		require.Contains(t, string(data), "akash_lease_network_transmit_bytes_total{"+labels+"} 20\n")
+
+		sctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		stream, err := test.gwclient.LeaseMetricsStream(sctx, lid, time.Second)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			select {
+			case res := <-stream.Stream:
+				require.Equal(t, *metrics, res)
+			case msg := <-stream.OnClose:
+				t.Fatalf("stream closed: %s", msg)
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for metrics")
+			}
+		}
+
+		_, err = test.gwclient.LeaseMetricsStream(ctx, lid, time.Millisecond)
+		var cerr ClientResponseError
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusBadRequest, cerr.Status)
+	})
+}
+
+func TestRouteLeaseMetricsPrometheusAPISource(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := metricsTestLeaseID(test)
+
+		test.pcclient.On("LeaseMetrics", mock.Anything, lid).Return(testLeaseMetrics(cltypes.MetricsSourceAPI), nil)
+
+		data, err := test.gwclient.LeaseMetricsPrometheus(context.Background(), lid)
+		require.NoError(t, err)
+		require.Contains(t, string(data), "akash_lease_gpu_allocated{")
+		require.NotContains(t, string(data), "akash_lease_network")
+
+		// unknown counters are omitted rather than reported as zero
+		res, err := test.gwclient.LeaseMetrics(context.Background(), lid)
+		require.NoError(t, err)
+		require.Nil(t, res.Services[serviceName].NetworkRx)
+		require.Nil(t, res.Services[serviceName].Pods[0].NetworkTx)
+	})
+}
+
+func TestRouteLeaseMetricsNotFound(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		ctx := context.Background()
+		lid := metricsTestLeaseID(test)
+
+		test.pcclient.On("LeaseMetrics", mock.Anything, lid).Return(nil, kubeclienterrors.ErrLeaseNotFound)
+
+		_, err := test.gwclient.LeaseMetrics(ctx, lid)
+		var cerr ClientResponseError
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusNotFound, cerr.Status)
+
+		stream, err := test.gwclient.LeaseMetricsStream(ctx, lid, 0)
+		require.NoError(t, err)
+
+		_, open := <-stream.Stream
+		require.False(t, open)
+		require.Equal(t, kubeclienterrors.ErrLeaseNotFound.Error(), <-stream.OnClose)
+	})
+}
@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/akash-network/node/util/wsutil"
+
+	"github.com/akash-network/provider/cluster"
+	pmanifest "github.com/akash-network/provider/manifest"
+)
+
+func leasePortForwardHandler(log log.Logger, mclient pmanifest.Client, cclient cluster.Client) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		leaseID := requestLeaseID(req)
+		service := requestService(req)
+
+		if !checkLeaseActive(log, rw, req, mclient, leaseID) {
+			return
+		}
+
+		localLog := log.With("lease", leaseID.String(), "service", service, "action", "port-forward")
+
+		vars := req.URL.Query()
+
+		podIndex, valid := requestPodIndex(localLog, rw, vars)
+		if !valid {
+			return
+		}
+
+		port, err := strconv.ParseUint(vars.Get("port"), 10, 16)
+		if err != nil || port == 0 {
+			localLog.Error("parameter port invalid", "port", vars.Get("port"))
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		upgrader := websocket.Upgrader{
+			ReadBufferSize:  0,
+			WriteBufferSize: 0,
+		}
+
+		ws, err := upgrader.Upgrade(rw, req, nil)
+		if err != nil {
+			// At this point the connection either has a response sent already
+			// or it has been closed
+			localLog.Error("failed handshake", "err", err)
+			return
+		}
+
+		inputIn, inputOut := io.Pipe()
+		wg := &sync.WaitGroup{}
+
+		subctx, subcancel := context.WithCancel(req.Context())
+
+		wg.Add(2)
+		go leaseShellPingHandler(subctx, wg, ws)
+		go leasePortForwardWebsocketHandler(localLog, wg, ws, inputOut)
+
+		l := &sync.Mutex{}
+		output := wsutil.NewWsWriterWrapper(ws, LeasePortForwardCodeData, l)
+
+		err = cclient.PortForward(subctx, leaseID, service, podIndex, uint32(port), inputIn, output)
+		subcancel()
+
+		var result []byte
+		resultWriter := wsutil.NewWsWriterWrapper(ws, LeasePortForwardCodeEOF, l)
+
+		if err != nil {
+			resultWriter = wsutil.NewWsWriterWrapper(ws, LeasePortForwardCodeFailure, l)
+
+			if cluster.ErrorIsOkToSendToClient(err) {
+				result = []byte(err.Error())
+			} else {
+				// Don't return errors like this to the client, they could contain information
+				// that should not be let out
+				localLog.Error("port forward failed", "err", err)
+			}
+		}
+
+		if _, err = resultWriter.Write(result); err != nil {
+			localLog.Error("failed writing response to client after port forward", "err", err)
+		}
+
+		_ = ws.Close()
+		_ = inputIn.Close()
+
+		wg.Wait()
+	}
+}
+
+// leasePortForwardWebsocketHandler feeds data received from the client to the forwarded port
+func leasePortForwardWebsocketHandler(log log.Logger, wg *sync.WaitGroup, ws *websocket.Conn, input *io.PipeWriter) {
+	defer wg.Done()
+
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(pingWait))
+	})
+
+	for {
+		msgType, data, err := ws.ReadMessage()
+		if err != nil {
+			_ = input.CloseWithError(err)
+			return
+		}
+
+		// Just ignore anything not a binary message or that is empty
+		if msgType != websocket.BinaryMessage || len(data) == 0 {
+			continue
+		}
+
+		switch data[0] {
+		case LeasePortForwardCodeData:
+			if _, err = input.Write(data[1:]); err != nil {
+				return
+			}
+		case LeasePortForwardCodeEOF:
+			// client is not going to send anything else, connection stays open for the response
+			_ = input.Close()
+		default:
+			log.Error("unknown message ID on websocket", "code", data[0])
+			_ = input.CloseWithError(errLeasePortForwardProtocol)
+			return
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	"github.com/akash-network/node/testutil"
+
+	"github.com/akash-network/provider/cluster"
+)
+
+func TestRoutePortForward(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		ctx := context.Background()
+		lid := mtypes.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),
+			GSeq:     uint32(testutil.RandRangeInt(1, 1000)),
+			OSeq:     uint32(testutil.RandRangeInt(1, 1000)),
+			Provider: test.paddr.String(),
+		}
+
+		test.pmclient.On("IsActive", mock.Anything, lid.DeploymentID()).Return(true, nil)
+
+		// echo everything back once client is done sending
+		test.pcclient.On("PortForward", mock.Anything, lid, serviceName, uint(1), uint32(5432), mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				data, err := io.ReadAll(args.Get(5).(io.Reader))
+				require.NoError(t, err)
+
+				_, err = args.Get(6).(io.Writer).Write(bytes.ToUpper(data))
+				require.NoError(t, err)
+			}).
+			Return(nil).Once()
+
+		output := &bytes.Buffer{}
+		err := test.gwclient.LeasePortForward(ctx, lid, serviceName, 1, 5432, strings.NewReader("select 1;"), output)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT 1;", output.String())
+
+		test.pcclient.On("PortForward", mock.Anything, lid, serviceName, uint(0), uint32(22), mock.Anything, mock.Anything).
+			Return(cluster.ErrExecPortNotExposed).Once()
+
+		err = test.gwclient.LeasePortForward(ctx, lid, serviceName, 0, 22, strings.NewReader(""), io.Discard)
+		require.ErrorIs(t, err, errLeasePortForward)
+		require.Contains(t, err.Error(), cluster.ErrExecPortNotExposed.Error())
+
+		test.pcclient.On("PortForward", mock.Anything, lid, serviceName, uint(0), uint32(80), mock.Anything, mock.Anything).
+			Return(errors.New("kubelet is on fire")).Once()
+
+		err = test.gwclient.LeasePortForward(ctx, lid, serviceName, 0, 80, strings.NewReader(""), io.Discard)
+		require.ErrorIs(t, err, ErrLeasePortForwardProviderError)
+		require.NotContains(t, err.Error(), "fire")
+
+		err = test.gwclient.LeasePortForward(ctx, lid, serviceName, 0, 0, strings.NewReader(""), io.Discard)
+		var cerr ClientResponseError
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusBadRequest, cerr.Status)
+
+		test.pcclient.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/akash-network/provider/cluster"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	pmanifest "github.com/akash-network/provider/manifest"
+)
+
+// restartErrorStatus maps errors of service restart to http status codes
+func restartErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, kubeclienterrors.ErrLeaseNotFound),
+		errors.Is(err, kubeclienterrors.ErrNoServiceForLease):
+		return http.StatusNotFound
+	case errors.Is(err, cluster.ErrRestartPodIndexOutOfRange):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func restartServiceHandler(log log.Logger, mclient pmanifest.Client, cclient cluster.Client) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		leaseID := requestLeaseID(req)
+		service := requestService(req)
+
+		if !checkLeaseActive(log, rw, req, mclient, leaseID) {
+			return
+		}
+
+		localLog := log.With("lease", leaseID.String(), "service", service, "action", "restart")
+
+		// whole service is restarted unless single replica is requested
+		var podIndex *uint
+		if vars := req.URL.Query(); vars.Has("podIndex") {
+			idx, valid := requestPodIndex(localLog, rw, vars)
+			if !valid {
+				return
+			}
+			podIndex = &idx
+		}
+
+		if err := cclient.RestartService(req.Context(), leaseID, service, podIndex); err != nil {
+			localLog.Error("restarting service", "err", err)
+			http.Error(rw, err.Error(), restartErrorStatus(err))
+			return
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	"github.com/akash-network/node/testutil"
+
+	"github.com/akash-network/provider/cluster"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+)
+
+func TestRouteRestartService(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		ctx := context.Background()
+		lid := mtypes.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),
+			GSeq:     uint32(testutil.RandRangeInt(1, 1000)),
+			OSeq:     uint32(testutil.RandRangeInt(1, 1000)),
+			Provider: test.paddr.String(),
+		}
+
+		test.pmclient.On("IsActive", mock.Anything, lid.DeploymentID()).Return(true, nil)
+
+		podIndex := uint(1)
+		outOfRange := uint(5)
+
+		test.pcclient.On("RestartService", mock.Anything, lid, serviceName, (*uint)(nil)).Return(nil).Once()
+		test.pcclient.On("RestartService", mock.Anything, lid, serviceName, &podIndex).Return(nil).Once()
+		test.pcclient.On("RestartService", mock.Anything, lid, serviceName, &outOfRange).
+			Return(cluster.ErrRestartPodIndexOutOfRange).Once()
+		test.pcclient.On("RestartService", mock.Anything, lid, "missing", (*uint)(nil)).
+			Return(kubeclienterrors.ErrNoServiceForLease).Once()
+
+		require.NoError(t, test.gwclient.RestartService(ctx, lid, serviceName, nil))
+		require.NoError(t, test.gwclient.RestartService(ctx, lid, serviceName, &podIndex))
+
+		var cerr ClientResponseError
+
+		err := test.gwclient.RestartService(ctx, lid, serviceName, &outOfRange)
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusBadRequest, cerr.Status)
+
+		err = test.gwclient.RestartService(ctx, lid, "missing", nil)
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusNotFound, cerr.Status)
+
+		test.pcclient.AssertExpectations(t)
+	})
+}
+
+func TestRouteRestartServiceInactiveLease(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := mtypes.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),
+			GSeq:     uint32(testutil.RandRangeInt(1, 1000)),
+			OSeq:     uint32(testutil.RandRangeInt(1, 1000)),
+			Provider: test.paddr.String(),
+		}
+
+		test.pmclient.On("IsActive", mock.Anything, lid.DeploymentID()).Return(false, nil)
+
+		err := test.gwclient.RestartService(context.Background(), lid, serviceName, nil)
+
+		var cerr ClientResponseError
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusNotFound, cerr.Status)
+
+		test.pcclient.AssertNotCalled(t, "RestartService", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
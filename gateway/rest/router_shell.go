@@ -32,7 +32,7 @@ func leaseShellPingHandler(ctx context.Context, wg *sync.WaitGroup, ws *websocke
 	}
 }
 
-func leaseShellWebsocketHandler(log log.Logger, wg *sync.WaitGroup, shellWs *websocket.Conn, stdinPipeOut io.Writer, terminalSizeUpdate chan<- remotecommand.TerminalSize) {
+func leaseShellWebsocketHandler(log log.Logger, wg *sync.WaitGroup, shellWs *websocket.Conn, stdinPipeOut io.WriteCloser, terminalSizeUpdate chan<- remotecommand.TerminalSize) {
 	defer wg.Done()
 	for {
 		shellWs.SetPongHandler(func(string) error {
@@ -57,6 +57,11 @@ func leaseShellWebsocketHandler(log log.Logger, wg *sync.WaitGroup, shellWs *web
 			if err != nil {
 				return
 			}
+		case LeaseShellCodeStdinEOF:
+			// remote stdin has been drained, let the process see end of input
+			if err := stdinPipeOut.Close(); err != nil {
+				return
+			}
 		case LeaseShellCodeTerminalResize:
 			var size remotecommand.TerminalSize
 			r := bytes.NewReader(msg)
@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/akash-network/provider/cluster"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	"github.com/akash-network/provider/cluster/util"
+)
+
+// snapshotErrorStatus maps errors of snapshot operations to http status codes
+func snapshotErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, cluster.ErrSnapshotNotFound),
+		errors.Is(err, kubeclienterrors.ErrNoServiceForLease):
+		return http.StatusNotFound
+	case errors.Is(err, cluster.ErrSnapshotDisabled),
+		errors.Is(err, cluster.ErrSnapshotQuotaExceeded):
+		return http.StatusForbidden
+	case errors.Is(err, cluster.ErrSnapshotNoVolumes),
+		errors.Is(err, cluster.ErrSnapshotNotReady):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, kubeclienterrors.ErrAlreadyExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func leaseSnapshotsHandler(log log.Logger, cclient cluster.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		snapshots, err := cclient.ListSnapshots(req.Context(), requestLeaseID(req), req.URL.Query().Get("service"))
+		if err != nil {
+			log.Error("listing volume snapshots", "err", err)
+			http.Error(w, err.Error(), snapshotErrorStatus(err))
+			return
+		}
+
+		writeJSON(log, w, snapshots)
+	}
+}
+
+func createSnapshotHandler(log log.Logger, cclient cluster.Client, clusterSettings map[interface{}]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := util.ApplyToContext(req.Context(), clusterSettings)
+
+		snapshots, err := cclient.CreateSnapshot(ctx, requestLeaseID(req), requestService(req))
+		if err != nil {
+			log.Error("creating volume snapshot", "err", err)
+			http.Error(w, err.Error(), snapshotErrorStatus(err))
+			return
+		}
+
+		writeJSON(log, w, snapshots)
+	}
+}
+
+func restoreSnapshotHandler(log log.Logger, cclient cluster.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		name := mux.Vars(req)["snapshot"]
+		if name == "" {
+			http.Error(w, "empty snapshot name", http.StatusBadRequest)
+			return
+		}
+
+		if err := cclient.RestoreSnapshot(req.Context(), requestLeaseID(req), requestService(req), name); err != nil {
+			log.Error("restoring volume snapshot", "err", err)
+			http.Error(w, err.Error(), snapshotErrorStatus(err))
+			return
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	types "github.com/akash-network/akash-api/go/node/market/v1beta3"
+	"github.com/akash-network/node/testutil"
+
+	"github.com/akash-network/provider/cluster"
+	clustertypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+func snapshotTestLeaseID(test *routerTest) types.LeaseID {
+	return types.LeaseID{
+		Owner:    test.caddr.String(),
+		DSeq:     uint64(testutil.RandRangeInt(1, 1000)),
+		GSeq:     uint32(testutil.RandRangeInt(4000, 5000)),
+		OSeq:     uint32(testutil.RandRangeInt(2000, 3000)),
+		Provider: test.paddr.String(),
+	}
+}
+
+func TestRouteSnapshots(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		ctx := context.Background()
+		lid := snapshotTestLeaseID(test)
+
+		snapshots := []clustertypes.VolumeSnapshot{
+			{
+				Name:      serviceName + "-20231017120000",
+				Service:   serviceName,
+				Volume:    serviceName + "-data-" + serviceName + "-0",
+				CreatedAt: time.Date(2023, 10, 17, 12, 0, 0, 0, time.UTC),
+			},
+		}
+
+		test.pcclient.On("CreateSnapshot", mock.Anything, lid, serviceName).Return(snapshots, nil).Once()
+		test.pcclient.On("ListSnapshots", mock.Anything, lid, serviceName).Return(snapshots, nil).Once()
+		test.pcclient.On("RestoreSnapshot", mock.Anything, lid, serviceName, snapshots[0].Name).Return(nil).Once()
+
+		res, err := test.gwclient.CreateSnapshot(ctx, lid, serviceName)
+		require.NoError(t, err)
+		require.Equal(t, snapshots, res)
+
+		res, err = test.gwclient.LeaseSnapshots(ctx, lid, serviceName)
+		require.NoError(t, err)
+		require.Equal(t, snapshots, res)
+
+		require.NoError(t, test.gwclient.RestoreSnapshot(ctx, lid, serviceName, snapshots[0].Name))
+
+		test.pcclient.AssertExpectations(t)
+	})
+}
+
+func TestRouteSnapshotErrors(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		ctx := context.Background()
+		lid := snapshotTestLeaseID(test)
+
+		test.pcclient.On("CreateSnapshot", mock.Anything, lid, serviceName).Return(nil, cluster.ErrSnapshotQuotaExceeded)
+		test.pcclient.On("RestoreSnapshot", mock.Anything, lid, serviceName, "missing").Return(cluster.ErrSnapshotNotFound)
+
+		_, err := test.gwclient.CreateSnapshot(ctx, lid, serviceName)
+
+		var cerr ClientResponseError
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusForbidden, cerr.Status)
+
+		err = test.gwclient.RestoreSnapshot(ctx, lid, serviceName, "missing")
+		require.True(t, errors.As(err, &cerr))
+		require.Equal(t, http.StatusNotFound, cerr.Status)
+	})
+}
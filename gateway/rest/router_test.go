@@ -34,6 +34,7 @@ import (
 	clustertypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	"github.com/akash-network/provider/gateway/utils"
+	pmanifest "github.com/akash-network/provider/manifest"
 	pmmock "github.com/akash-network/provider/manifest/mocks"
 	pmock "github.com/akash-network/provider/mocks"
 	"github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
@@ -124,6 +125,7 @@ func testCertHelper(t *testing.T, test *routerTest) {
 		mock.Anything,
 		mock.AnythingOfType("types.DeploymentID"),
 		mock.AnythingOfType("v2beta2.Manifest"),
+		mock.AnythingOfType("v2beta2.ManifestProbes"),
 	).Return(nil)
 
 	dseq := uint64(testutil.RandRangeInt(1, 1000))
@@ -536,6 +538,7 @@ func TestRoutePutManifestOK(t *testing.T) {
 				DSeq:  dseq,
 			},
 			mock.AnythingOfType("v2beta2.Manifest"),
+			mock.AnythingOfType("v2beta2.ManifestProbes"),
 		).Return(nil)
 
 		uri, err := makeURI(test.host, submitManifestPath(dseq))
@@ -565,6 +568,67 @@ func TestRoutePutManifestOK(t *testing.T) {
 	})
 }
 
+func TestRoutePutManifestProbes(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		dseq := uint64(testutil.RandRangeInt(1, 1000))
+
+		sdl, err := sdl.ReadFile(testSDL)
+		require.NoError(t, err)
+
+		mani, err := sdl.Manifest()
+		require.NoError(t, err)
+
+		buf, err := json.Marshal(mani)
+		require.NoError(t, err)
+
+		// probes are set within service params of the manifest
+		var raw []map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf, &raw))
+
+		services := raw[0]["services"].([]interface{})
+		services[0].(map[string]interface{})["params"] = json.RawMessage(`{
+			"probes": {"readiness": {"type": "tcp", "port": 80, "periodSeconds": 5}}
+		}`)
+
+		buf, err = json.Marshal(raw)
+		require.NoError(t, err)
+
+		probes := make([]*v2beta2.ManifestServiceProbes, len(mani[0].Services))
+		probes[0] = &v2beta2.ManifestServiceProbes{
+			Readiness: &v2beta2.ManifestServiceProbe{
+				Type:          v2beta2.ProbeTypeTCP,
+				Port:          80,
+				PeriodSeconds: 5,
+			},
+		}
+
+		test.pmclient.On(
+			"Submit",
+			mock.Anything,
+			dtypes.DeploymentID{
+				Owner: test.caddr.String(),
+				DSeq:  dseq,
+			},
+			mock.AnythingOfType("v2beta2.Manifest"),
+			v2beta2.ManifestProbes{mani[0].Name: probes},
+		).Return(nil)
+
+		uri, err := makeURI(test.host, submitManifestPath(dseq))
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("PUT", uri, bytes.NewBuffer(buf))
+		require.NoError(t, err)
+
+		req.Header.Set("Content-Type", contentTypeJSON)
+
+		resp, err := test.gwclient.hclient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		test.pmclient.AssertExpectations(t)
+	})
+}
+
 func TestRoutePutInvalidManifest(t *testing.T) {
 	_ = dtypes.DeploymentID{}
 	runRouterTest(t, true, func(test *routerTest) {
@@ -577,6 +641,7 @@ func TestRoutePutInvalidManifest(t *testing.T) {
 			},
 
 			mock.AnythingOfType("v2beta2.Manifest"),
+			mock.AnythingOfType("v2beta2.ManifestProbes"),
 		).Return(manifestValidation.ErrInvalidManifest)
 
 		uri, err := makeURI(test.host, submitManifestPath(dseq))
@@ -606,6 +671,50 @@ func TestRoutePutInvalidManifest(t *testing.T) {
 	})
 }
 
+func TestRoutePutManifestImageNotAllowed(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		dseq := uint64(testutil.RandRangeInt(1, 1000))
+
+		sdl, err := sdl.ReadFile(testSDL)
+		require.NoError(t, err)
+
+		mani, err := sdl.Manifest()
+		require.NoError(t, err)
+
+		policy, err := pmanifest.NewImagePolicy(pmanifest.ImagePolicyConfig{RequireDigest: true})
+		require.NoError(t, err)
+
+		test.pmclient.On("Submit",
+			mock.Anything,
+			dtypes.DeploymentID{
+				Owner: test.caddr.String(),
+				DSeq:  dseq,
+			},
+			mock.AnythingOfType("v2beta2.Manifest"),
+			mock.AnythingOfType("v2beta2.ManifestProbes"),
+		).Return(policy.CheckManifest(mani))
+
+		uri, err := makeURI(test.host, submitManifestPath(dseq))
+		require.NoError(t, err)
+
+		buf, err := json.Marshal(mani)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("PUT", uri, bytes.NewBuffer(buf))
+		require.NoError(t, err)
+
+		req.Header.Set("Content-Type", contentTypeJSON)
+
+		resp, err := test.gwclient.hclient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Regexp(t, "^image not allowed: image \"nginx\" must be pinned by digest(?s:.)*$", string(data))
+	})
+}
+
 func mockManifestGroupsForRouterTest(rt *routerTest, leaseID mtypes.LeaseID) {
 	status := make(map[string]*ctypes.ServiceStatus)
 	status[testServiceName] = &ctypes.ServiceStatus{
@@ -620,6 +729,10 @@ func mockManifestGroupsForRouterTest(rt *routerTest, leaseID mtypes.LeaseID) {
 		AvailableReplicas:  0,
 	}
 	rt.pcclient.On("LeaseStatus", mock.Anything, leaseID).Return(status, nil)
+	rt.pcclient.On("GetManifestStatus", mock.Anything, leaseID).Return(v2beta2.ManifestStatus{
+		State:   v2beta2.ManifestStateRolledBack,
+		Message: "rollout failed",
+	}, nil)
 	rt.pcclient.On("GetManifestGroup", mock.Anything, leaseID).Return(true, v2beta2.ManifestGroup{
 		Name: testGroupName,
 		Services: []v2beta2.ManifestService{{
@@ -691,9 +804,40 @@ func TestRouteLeaseStatusOk(t *testing.T) {
 		dec := json.NewDecoder(resp.Body)
 		err = dec.Decode(&data)
 		require.NoError(t, err)
+
+		require.Equal(t, map[string]interface{}{"reason": "rollout failed"}, data["rollback"])
 	})
 }
 
+func TestRouteLeaseStatusWithoutManifestStatus(t *testing.T) {
+	for _, merr := range []error{kubeclienterrors.ErrNoManifestForLease, errors.New("boom")} { // nolint: goerr113
+		runRouterTest(t, true, func(test *routerTest) {
+			leaseID := testutil.LeaseID(t)
+			leaseID.Owner = test.caddr.String()
+			leaseID.Provider = test.paddr.String()
+
+			// rollback status cannot be read, rest of lease status is still reported
+			test.pcclient.On("GetManifestStatus", mock.Anything, leaseID).Return(v2beta2.ManifestStatus{}, merr)
+			mockManifestGroupsForRouterTest(test, leaseID)
+
+			uri, err := makeURI(test.host, leaseStatusPath(leaseID))
+			require.NoError(t, err)
+
+			req, err := http.NewRequest("GET", uri, nil)
+			require.NoError(t, err)
+
+			resp, err := test.gwclient.hclient.Do(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			data := make(map[string]interface{})
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&data))
+			require.NotContains(t, data, "rollback")
+			require.Contains(t, data, "services")
+		})
+	}
+}
+
 func TestRouteLeaseNotInKubernetes(t *testing.T) {
 	runRouterTest(t, true, func(test *routerTest) {
 		leaseID := testutil.LeaseID(t)
@@ -11,8 +11,14 @@ type LeasedIPStatus struct {
 	IP           string
 }
 
+// LeaseRollbackStatus is set when failed update of the lease was reverted to the last good manifest
+type LeaseRollbackStatus struct {
+	Reason string `json:"reason"`
+}
+
 type LeaseStatus struct {
 	Services       map[string]*cltypes.ServiceStatus        `json:"services"`
 	ForwardedPorts map[string][]cltypes.ForwardedPortStatus `json:"forwarded_ports"` // Container services that are externally accessible
 	IPs            map[string][]LeasedIPStatus              `json:"ips"`
+	Rollback       *LeaseRollbackStatus                     `json:"rollback,omitempty"`
 }
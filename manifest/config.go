@@ -7,4 +7,6 @@ type ServiceConfig struct {
 	ManifestTimeout                   time.Duration
 	RPCQueryTimeout                   time.Duration
 	CachedResultMaxAge                time.Duration
+	// ImagePolicy rejects manifests with images provider does not run. Nil policy allows every image
+	ImagePolicy *ImagePolicy
 }
@@ -0,0 +1,232 @@
+package manifest
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	maniv2beta2 "github.com/akash-network/akash-api/go/manifest/v2beta2"
+)
+
+var (
+	// ErrImageNotAllowed indicates that the manifest contains image rejected by provider image policy
+	ErrImageNotAllowed    = errors.New("image not allowed")
+	ErrInvalidImagePolicy = errors.New("invalid image policy")
+)
+
+const (
+	defaultImageRegistry  = "docker.io"
+	defaultImageNamespace = "library"
+	latestImageTag        = "latest"
+)
+
+var (
+	// docker hub is reachable under several hosts, all of them are matched as defaultImageRegistry
+	dockerHubRegistries = map[string]bool{
+		"index.docker.io":      true,
+		"registry-1.docker.io": true,
+	}
+
+	// digest is algorithm:hex, see https://github.com/opencontainers/image-spec/blob/main/descriptor.md#digests
+	imageDigestRegexp = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-f0-9]{32,}$`)
+
+	// hex length of the registered digest algorithms
+	imageDigestLengths = map[string]int{
+		"sha256": 64,
+		"sha512": 128,
+	}
+)
+
+// ImagePolicyConfig is the images section of the provider config file.
+// Registry patterns match registry host, e.g. "ghcr.io" or "*.example.com".
+// Repository patterns match fully qualified repository, e.g. "docker.io/library/*".
+// Patterns are globs, "*" does not match "/". Empty allow lists allow everything which is not denied
+type ImagePolicyConfig struct {
+	AllowedRegistries   []string `json:"allowed_registries,omitempty" yaml:"allowed_registries,omitempty"`
+	DeniedRegistries    []string `json:"denied_registries,omitempty" yaml:"denied_registries,omitempty"`
+	AllowedRepositories []string `json:"allowed_repositories,omitempty" yaml:"allowed_repositories,omitempty"`
+	DeniedRepositories  []string `json:"denied_repositories,omitempty" yaml:"denied_repositories,omitempty"`
+	// RequireDigest rejects images not pinned by digest, e.g. nginx@sha256:...
+	RequireDigest bool `json:"require_digest,omitempty" yaml:"require_digest,omitempty"`
+	// DenyLatest rejects images with latest or no tag
+	DenyLatest bool `json:"deny_latest,omitempty" yaml:"deny_latest,omitempty"`
+}
+
+// ImagePolicy decides which container images tenants can run.
+// Orders carry no image references, so policy is enforced once manifest is submitted.
+// It is safe to update while manifests are being validated
+type ImagePolicy struct {
+	lock sync.RWMutex
+	cfg  ImagePolicyConfig
+}
+
+// imageReference is the container image split into parts
+type imageReference struct {
+	registry   string
+	repository string
+	tag        string
+	digest     string
+}
+
+func NewImagePolicy(cfg ImagePolicyConfig) (*ImagePolicy, error) {
+	ip := &ImagePolicy{}
+
+	if err := ip.Update(cfg); err != nil {
+		return nil, err
+	}
+
+	return ip, nil
+}
+
+// Update replaces policy config. Policy is left unchanged if config is invalid
+func (ip *ImagePolicy) Update(cfg ImagePolicyConfig) error {
+	for _, patterns := range [][]string{cfg.AllowedRegistries, cfg.DeniedRegistries, cfg.AllowedRepositories, cfg.DeniedRepositories} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+				return fmt.Errorf("%w: bad pattern %q", ErrInvalidImagePolicy, pattern)
+			}
+		}
+	}
+
+	ip.lock.Lock()
+	defer ip.lock.Unlock()
+
+	ip.cfg = cfg
+
+	return nil
+}
+
+// CheckManifest returns error wrapping ErrImageNotAllowed for the first service image rejected by policy.
+// Nil policy allows every image
+func (ip *ImagePolicy) CheckManifest(mani maniv2beta2.Manifest) error {
+	if ip == nil {
+		return nil
+	}
+
+	for _, group := range mani {
+		for _, service := range group.Services {
+			if err := ip.CheckImage(service.Image); err != nil {
+				return fmt.Errorf("%w: service %q of group %q", err, service.Name, group.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckImage returns error wrapping ErrImageNotAllowed if image is rejected by policy
+func (ip *ImagePolicy) CheckImage(image string) error {
+	if ip == nil {
+		return nil
+	}
+
+	ip.lock.RLock()
+	defer ip.lock.RUnlock()
+
+	ref, err := parseImageReference(image)
+	if err != nil {
+		return err
+	}
+
+	name := ref.registry + "/" + ref.repository
+
+	if matchAny(ip.cfg.DeniedRegistries, ref.registry) {
+		return fmt.Errorf("%w: registry %q of image %q is denied", ErrImageNotAllowed, ref.registry, image)
+	}
+
+	if matchAny(ip.cfg.DeniedRepositories, name) {
+		return fmt.Errorf("%w: repository %q of image %q is denied", ErrImageNotAllowed, name, image)
+	}
+
+	if len(ip.cfg.AllowedRegistries) != 0 && !matchAny(ip.cfg.AllowedRegistries, ref.registry) {
+		return fmt.Errorf("%w: registry %q of image %q is not allowed", ErrImageNotAllowed, ref.registry, image)
+	}
+
+	if len(ip.cfg.AllowedRepositories) != 0 && !matchAny(ip.cfg.AllowedRepositories, name) {
+		return fmt.Errorf("%w: repository %q of image %q is not allowed", ErrImageNotAllowed, name, image)
+	}
+
+	if ip.cfg.RequireDigest && ref.digest == "" {
+		return fmt.Errorf("%w: image %q must be pinned by digest", ErrImageNotAllowed, image)
+	}
+
+	// image pinned by digest runs the same content regardless of the tag
+	if ip.cfg.DenyLatest && ref.digest == "" && (ref.tag == "" || ref.tag == latestImageTag) {
+		return fmt.Errorf("%w: image %q must have tag other than latest", ErrImageNotAllowed, image)
+	}
+
+	return nil
+}
+
+func matchAny(patterns []string, val string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, val); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseImageReference splits image the same way container runtimes do.
+// First path component is the registry when it looks like a host, docker hub is used otherwise
+func parseImageReference(image string) (imageReference, error) {
+	var ref imageReference
+
+	name := strings.TrimSpace(image)
+
+	if idx := strings.Index(name, "@"); idx != -1 {
+		ref.digest = name[idx+1:]
+		name = name[:idx]
+
+		if !validImageDigest(ref.digest) {
+			return imageReference{}, fmt.Errorf("%w: invalid digest of image %q", ErrImageNotAllowed, image)
+		}
+	}
+
+	// tag separator must be after the last path component, registry may have port
+	if idx := strings.LastIndex(name, ":"); idx != -1 && !strings.Contains(name[idx+1:], "/") {
+		ref.tag = name[idx+1:]
+		name = name[:idx]
+	}
+
+	if name == "" {
+		return imageReference{}, fmt.Errorf("%w: invalid image %q", ErrImageNotAllowed, image)
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.registry = strings.ToLower(parts[0])
+		ref.repository = parts[1]
+
+		if dockerHubRegistries[ref.registry] {
+			ref.registry = defaultImageRegistry
+		}
+	} else {
+		ref.registry = defaultImageRegistry
+		ref.repository = name
+	}
+
+	if ref.registry == defaultImageRegistry && !strings.Contains(ref.repository, "/") {
+		ref.repository = defaultImageNamespace + "/" + ref.repository
+	}
+
+	return ref, nil
+}
+
+func validImageDigest(digest string) bool {
+	if !imageDigestRegexp.MatchString(digest) {
+		return false
+	}
+
+	algo, encoded, _ := strings.Cut(digest, ":")
+	if length, known := imageDigestLengths[algo]; known {
+		return len(encoded) == length
+	}
+
+	return true
+}
@@ -0,0 +1,90 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	maniv2beta2 "github.com/akash-network/akash-api/go/manifest/v2beta2"
+)
+
+const testImageDigest = "sha256:6d4a3d4a2b1d6bb1c6ae6c0f3c1dbb1f7cf1bfb4b1c0d3a0bb8d1f2b69a3f5e1"
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		image string
+		ref   imageReference
+	}{
+		{image: "nginx", ref: imageReference{registry: "docker.io", repository: "library/nginx"}},
+		{image: "nginx:1.25", ref: imageReference{registry: "docker.io", repository: "library/nginx", tag: "1.25"}},
+		{image: "akash/app:v1", ref: imageReference{registry: "docker.io", repository: "akash/app", tag: "v1"}},
+		{image: "ghcr.io/akash/app@" + testImageDigest, ref: imageReference{registry: "ghcr.io", repository: "akash/app", digest: testImageDigest}},
+		{image: "index.docker.io/nginx:1.25", ref: imageReference{registry: "docker.io", repository: "library/nginx", tag: "1.25"}},
+		{image: "registry-1.docker.io/akash/app", ref: imageReference{registry: "docker.io", repository: "akash/app"}},
+		{image: "localhost:5000/app:v1", ref: imageReference{registry: "localhost:5000", repository: "app", tag: "v1"}},
+		{image: "registry.example.com:5000/team/app", ref: imageReference{registry: "registry.example.com:5000", repository: "team/app"}},
+	}
+
+	for _, test := range tests {
+		ref, err := parseImageReference(test.image)
+		require.NoError(t, err, test.image)
+		require.Equal(t, test.ref, ref, test.image)
+	}
+
+	for _, image := range []string{
+		"nginx@sha256:abcd",
+		"nginx@sha256",
+		"nginx@" + testImageDigest + "0",
+		"nginx@SHA256:" + testImageDigest[7:],
+		"nginx@latest",
+	} {
+		_, err := parseImageReference(image)
+		require.ErrorIs(t, err, ErrImageNotAllowed, image)
+	}
+}
+
+func TestImagePolicy(t *testing.T) {
+	policy, err := NewImagePolicy(ImagePolicyConfig{
+		AllowedRegistries:  []string{"docker.io", "*.example.com"},
+		DeniedRepositories: []string{"docker.io/miner/*"},
+		DenyLatest:         true,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, policy.CheckImage("nginx:1.25"))
+	require.NoError(t, policy.CheckImage("registry.example.com/app@"+testImageDigest))
+	require.NoError(t, policy.CheckImage("index.docker.io/nginx:1.25"))
+
+	require.ErrorIs(t, policy.CheckImage("ghcr.io/akash/app:v1"), ErrImageNotAllowed)
+	require.ErrorIs(t, policy.CheckImage("miner/xmrig:6"), ErrImageNotAllowed)
+	require.ErrorIs(t, policy.CheckImage("registry-1.docker.io/miner/xmrig:6"), ErrImageNotAllowed)
+	require.ErrorIs(t, policy.CheckImage("nginx"), ErrImageNotAllowed)
+	require.ErrorIs(t, policy.CheckImage("nginx:latest"), ErrImageNotAllowed)
+
+	mani := maniv2beta2.Manifest{
+		{
+			Name: "westcoast",
+			Services: maniv2beta2.Services{
+				{Name: "web", Image: "nginx:1.25"},
+				{Name: "worker", Image: "ghcr.io/akash/app:v1"},
+			},
+		},
+	}
+
+	err = policy.CheckManifest(mani)
+	require.ErrorIs(t, err, ErrImageNotAllowed)
+	require.Contains(t, err.Error(), "worker")
+
+	// invalid config leaves policy intact
+	require.ErrorIs(t, policy.Update(ImagePolicyConfig{DeniedRegistries: []string{"["}}), ErrInvalidImagePolicy)
+	require.ErrorIs(t, policy.CheckImage("ghcr.io/akash/app:v1"), ErrImageNotAllowed)
+
+	require.NoError(t, policy.Update(ImagePolicyConfig{RequireDigest: true}))
+	require.ErrorIs(t, policy.CheckImage("nginx:1.25"), ErrImageNotAllowed)
+	require.NoError(t, policy.CheckImage("ghcr.io/akash/app@"+testImageDigest))
+	require.ErrorIs(t, policy.CheckImage("ghcr.io/akash/app@sha256:abcd"), ErrImageNotAllowed)
+
+	// nil policy allows everything
+	var none *ImagePolicy
+	require.NoError(t, none.CheckManifest(mani))
+}
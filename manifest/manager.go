@@ -22,6 +22,7 @@ import (
 
 	clustertypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	"github.com/akash-network/provider/event"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 	"github.com/akash-network/provider/session"
 )
 
@@ -80,6 +81,7 @@ type manager struct {
 	requests        []manifestRequest
 	pendingRequests []manifestRequest
 	manifests       []*maniv2beta2.Manifest
+	probes          crd.ManifestProbes
 	versions        [][]byte
 
 	localLeases []event.LeaseWon
@@ -357,6 +359,7 @@ func (m *manager) emitReceivedEvents() {
 			LeaseID:    lease.LeaseID,
 			Group:      lease.Group,
 			Manifest:   latestManifest,
+			Probes:     m.probes,
 			Deployment: copyOfData,
 		}); err != nil {
 			m.log.Error("publishing event", "err", err, "lease", lease.LeaseID)
@@ -376,6 +379,7 @@ func (m *manager) validateRequests() {
 	}
 
 	manifests := make([]*maniv2beta2.Manifest, 0)
+	probes := make([]crd.ManifestProbes, 0)
 	for _, req := range m.requests {
 		// If the request context is complete then skip processing it
 		select {
@@ -389,6 +393,7 @@ func (m *manager) validateRequests() {
 			continue
 		}
 		manifests = append(manifests, &req.value.Manifest)
+		probes = append(probes, req.value.Probes)
 
 		// The manifest has been grabbed from the request but not published yet, store this response
 		m.pendingRequests = append(m.pendingRequests, req)
@@ -401,6 +406,7 @@ func (m *manager) validateRequests() {
 	if len(manifests) > 0 {
 		// XXX: only one version means only one valid manifest
 		m.manifests = append(m.manifests, manifests[0])
+		m.probes = probes[0]
 	}
 }
 
@@ -439,6 +445,10 @@ func (m *manager) validateRequest(req manifestRequest) error {
 		return err
 	}
 
+	if err = m.config.ImagePolicy.CheckManifest(req.value.Manifest); err != nil {
+		return err
+	}
+
 	groupNames := make([]string, 0)
 
 	for _, lease := range m.localLeases {
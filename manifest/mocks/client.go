@@ -9,6 +9,8 @@ import (
 
 	v1beta3 "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
 
+	akash_networkv2beta2 "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+
 	v2beta2 "github.com/akash-network/akash-api/go/manifest/v2beta2"
 )
 
@@ -78,13 +80,13 @@ func (_c *Client_IsActive_Call) RunAndReturn(run func(context.Context, v1beta3.D
 	return _c
 }
 
-// Submit provides a mock function with given fields: _a0, _a1, _a2
-func (_m *Client) Submit(_a0 context.Context, _a1 v1beta3.DeploymentID, _a2 v2beta2.Manifest) error {
-	ret := _m.Called(_a0, _a1, _a2)
+// Submit provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Client) Submit(_a0 context.Context, _a1 v1beta3.DeploymentID, _a2 v2beta2.Manifest, _a3 akash_networkv2beta2.ManifestProbes) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, v1beta3.DeploymentID, v2beta2.Manifest) error); ok {
-		r0 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta3.DeploymentID, v2beta2.Manifest, akash_networkv2beta2.ManifestProbes) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -101,13 +103,14 @@ type Client_Submit_Call struct {
 //   - _a0 context.Context
 //   - _a1 v1beta3.DeploymentID
 //   - _a2 v2beta2.Manifest
-func (_e *Client_Expecter) Submit(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Client_Submit_Call {
-	return &Client_Submit_Call{Call: _e.mock.On("Submit", _a0, _a1, _a2)}
+//   - _a3 akash_networkv2beta2.ManifestProbes
+func (_e *Client_Expecter) Submit(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *Client_Submit_Call {
+	return &Client_Submit_Call{Call: _e.mock.On("Submit", _a0, _a1, _a2, _a3)}
 }
 
-func (_c *Client_Submit_Call) Run(run func(_a0 context.Context, _a1 v1beta3.DeploymentID, _a2 v2beta2.Manifest)) *Client_Submit_Call {
+func (_c *Client_Submit_Call) Run(run func(_a0 context.Context, _a1 v1beta3.DeploymentID, _a2 v2beta2.Manifest, _a3 akash_networkv2beta2.ManifestProbes)) *Client_Submit_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(v1beta3.DeploymentID), args[2].(v2beta2.Manifest))
+		run(args[0].(context.Context), args[1].(v1beta3.DeploymentID), args[2].(v2beta2.Manifest), args[3].(akash_networkv2beta2.ManifestProbes))
 	})
 	return _c
 }
@@ -117,7 +120,7 @@ func (_c *Client_Submit_Call) Return(_a0 error) *Client_Submit_Call {
 	return _c
 }
 
-func (_c *Client_Submit_Call) RunAndReturn(run func(context.Context, v1beta3.DeploymentID, v2beta2.Manifest) error) *Client_Submit_Call {
+func (_c *Client_Submit_Call) RunAndReturn(run func(context.Context, v1beta3.DeploymentID, v2beta2.Manifest, akash_networkv2beta2.ManifestProbes) error) *Client_Submit_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -18,6 +18,7 @@ import (
 
 	clustertypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	"github.com/akash-network/provider/event"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 	"github.com/akash-network/provider/session"
 )
 
@@ -49,7 +50,7 @@ type StatusClient interface {
 //
 //go:generate mockery --name Client
 type Client interface {
-	Submit(context.Context, dtypes.DeploymentID, manifest.Manifest) error
+	Submit(context.Context, dtypes.DeploymentID, manifest.Manifest, crd.ManifestProbes) error
 	IsActive(context.Context, dtypes.DeploymentID) (bool, error)
 }
 
@@ -157,7 +158,7 @@ func (s *service) IsActive(ctx context.Context, dID dtypes.DeploymentID) (bool,
 }
 
 // Submit incoming manifest request.
-func (s *service) Submit(ctx context.Context, did dtypes.DeploymentID, mani manifest.Manifest) error {
+func (s *service) Submit(ctx context.Context, did dtypes.DeploymentID, mani manifest.Manifest, probes crd.ManifestProbes) error {
 	// This needs to be buffered because the goroutine writing to this may get the result
 	// after the context has returned an error
 	ch := make(chan error, 1)
@@ -165,6 +166,7 @@ func (s *service) Submit(ctx context.Context, did dtypes.DeploymentID, mani mani
 		value: &submitRequest{
 			Deployment: did,
 			Manifest:   mani,
+			Probes:     probes,
 		},
 		ch:  ch,
 		ctx: ctx,
@@ -4,6 +4,8 @@ import (
 	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
 
 	maniv2beta1 "github.com/akash-network/akash-api/go/manifest/v2beta2"
+
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 )
 
 // Status is the data structure
@@ -14,4 +16,5 @@ type Status struct {
 type submitRequest struct {
 	Deployment dtypes.DeploymentID  `json:"deployment"`
 	Manifest   maniv2beta1.Manifest `json:"manifest"`
+	Probes     crd.ManifestProbes   `json:"probes,omitempty"`
 }
@@ -5,6 +5,8 @@ package mocks
 import (
 	context "context"
 
+	bidengine "github.com/akash-network/provider/bidengine"
+
 	cluster "github.com/akash-network/provider/cluster"
 
 	deploymentv1beta3 "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
@@ -33,6 +35,49 @@ func (_m *Client) EXPECT() *Client_Expecter {
 	return &Client_Expecter{mock: &_m.Mock}
 }
 
+// Bidengine provides a mock function with given fields:
+func (_m *Client) Bidengine() bidengine.DecisionClient {
+	ret := _m.Called()
+
+	var r0 bidengine.DecisionClient
+	if rf, ok := ret.Get(0).(func() bidengine.DecisionClient); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(bidengine.DecisionClient)
+		}
+	}
+
+	return r0
+}
+
+// Client_Bidengine_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Bidengine'
+type Client_Bidengine_Call struct {
+	*mock.Call
+}
+
+// Bidengine is a helper method to define mock.On call
+func (_e *Client_Expecter) Bidengine() *Client_Bidengine_Call {
+	return &Client_Bidengine_Call{Call: _e.mock.On("Bidengine")}
+}
+
+func (_c *Client_Bidengine_Call) Run(run func()) *Client_Bidengine_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Client_Bidengine_Call) Return(_a0 bidengine.DecisionClient) *Client_Bidengine_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_Bidengine_Call) RunAndReturn(run func() bidengine.DecisionClient) *Client_Bidengine_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Cluster provides a mock function with given fields:
 func (_m *Client) Cluster() cluster.Client {
 	ret := _m.Called()
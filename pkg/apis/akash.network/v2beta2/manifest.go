@@ -1,6 +1,7 @@
 package v2beta2
 
 import (
+	"encoding/json"
 	"fmt"
 
 	mani "github.com/akash-network/akash-api/go/manifest/v2beta2"
@@ -64,8 +65,16 @@ type ManifestGroup struct {
 type ManifestSpec struct {
 	LeaseID LeaseID       `json:"lease_id"`
 	Group   ManifestGroup `json:"group"`
+	// LastGood is the last group which was deployed and became healthy
+	LastGood *ManifestGroup `json:"last_good,omitempty"`
 }
 
+// States of the manifest
+const (
+	// ManifestStateRolledBack is set when failed update of the group was reverted to the last good group
+	ManifestStateRolledBack = "rolled-back"
+)
+
 // ManifestStatus stores state and message of manifest
 type ManifestStatus struct {
 	State   string `json:"state,omitempty"`
@@ -78,8 +87,37 @@ type ManifestStorageParams struct {
 	ReadOnly bool   `json:"readOnly" yaml:"readOnly"`
 }
 
+// Types of the service health probes
+const (
+	ProbeTypeHTTP = "http"
+	ProbeTypeTCP  = "tcp"
+	ProbeTypeExec = "exec"
+)
+
+// ManifestServiceProbe describes single health probe of the service container.
+// Zero timings are left to kubernetes defaults
+type ManifestServiceProbe struct {
+	Type                string   `json:"type" yaml:"type"`
+	Port                uint16   `json:"port,omitempty" yaml:"port,omitempty"`
+	Path                string   `json:"path,omitempty" yaml:"path,omitempty"`
+	Command             []string `json:"command,omitempty" yaml:"command,omitempty"`
+	InitialDelaySeconds int32    `json:"initialDelaySeconds,omitempty" yaml:"initialDelaySeconds,omitempty"`
+	PeriodSeconds       int32    `json:"periodSeconds,omitempty" yaml:"periodSeconds,omitempty"`
+	TimeoutSeconds      int32    `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty"`
+	SuccessThreshold    int32    `json:"successThreshold,omitempty" yaml:"successThreshold,omitempty"`
+	FailureThreshold    int32    `json:"failureThreshold,omitempty" yaml:"failureThreshold,omitempty"`
+}
+
+// ManifestServiceProbes stores health probes of the service container
+type ManifestServiceProbes struct {
+	Liveness  *ManifestServiceProbe `json:"liveness,omitempty" yaml:"liveness,omitempty"`
+	Readiness *ManifestServiceProbe `json:"readiness,omitempty" yaml:"readiness,omitempty"`
+	Startup   *ManifestServiceProbe `json:"startup,omitempty" yaml:"startup,omitempty"`
+}
+
 type ManifestServiceParams struct {
 	Storage []ManifestStorageParams `json:"storage,omitempty"`
+	Probes  *ManifestServiceProbes  `json:"probes,omitempty"`
 }
 
 // type NodeAffinity struct {
@@ -107,6 +145,69 @@ type SchedulerParams struct {
 
 type ClusterSettings struct {
 	SchedulerParams []*SchedulerParams `json:"scheduler_params"`
+	// Probes are health probes of the services, indexed same way as SchedulerParams.
+	// Nil or missing entry means service has no probes set
+	Probes []*ManifestServiceProbes `json:"probes,omitempty"`
+}
+
+func (cs ClusterSettings) probes(idx int) *ManifestServiceProbes {
+	if idx < len(cs.Probes) {
+		return cs.Probes[idx]
+	}
+
+	return nil
+}
+
+// WithProbes returns copy of the settings with probes replaced by provided ones
+func (cs ClusterSettings) WithProbes(probes []*ManifestServiceProbes) ClusterSettings {
+	res := *cs.DeepCopy()
+	res.Probes = probes
+
+	return res
+}
+
+// ManifestProbes stores health probes of the tenant manifest, keyed by group name.
+// Probes of the group are indexed same way as group services
+type ManifestProbes map[string][]*ManifestServiceProbes
+
+// ParseManifestProbes reads health probes from params of the services of tenant manifest json.
+// Probes are not part of the manifest api yet, so they are dropped when manifest itself is decoded.
+// Groups without probes are omitted from the result
+func ParseManifestProbes(data []byte) (ManifestProbes, error) {
+	var groups []struct {
+		Name     string `json:"name"`
+		Services []struct {
+			Params *struct {
+				Probes *ManifestServiceProbes `json:"probes"`
+			} `json:"params"`
+		} `json:"services"`
+	}
+
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil, err
+	}
+
+	res := make(ManifestProbes)
+	for _, group := range groups {
+		probes := make([]*ManifestServiceProbes, 0, len(group.Services))
+		hasProbes := false
+
+		for _, svc := range group.Services {
+			var sprobes *ManifestServiceProbes
+			if svc.Params != nil && svc.Params.Probes != nil {
+				sprobes = svc.Params.Probes
+				hasProbes = true
+			}
+
+			probes = append(probes, sprobes)
+		}
+
+		if hasProbes {
+			res[group.Name] = probes
+		}
+	}
+
+	return res, nil
 }
 
 // ManifestServiceExpose stores exposed ports and accepted hosts details
@@ -141,6 +242,14 @@ func NewManifest(ns string, lid mtypes.LeaseID, mgroup *mani.Group, settings Clu
 		)
 	}
 
+	if len(settings.Probes) != 0 && len(mgroup.Services) != len(settings.Probes) {
+		return nil, fmt.Errorf("%w: group services don't not match probes count (%d) != (%d)",
+			ErrInvalidArgs,
+			len(mgroup.Services),
+			len(settings.Probes),
+		)
+	}
+
 	group, err := manifestGroupToCRD(mgroup, settings)
 	if err != nil {
 		return nil, err
@@ -169,7 +278,7 @@ func (m *Manifest) Deployment() (ctypes.IDeployment, error) {
 		return nil, err
 	}
 
-	group, schedulerParams, err := m.Spec.Group.fromCRD()
+	group, settings, err := m.Spec.Group.fromCRD()
 	if err != nil {
 		return nil, err
 	}
@@ -177,30 +286,71 @@ func (m *Manifest) Deployment() (ctypes.IDeployment, error) {
 	return &deployment{
 		lid:     lid,
 		group:   group,
-		cparams: schedulerParams,
+		cparams: settings,
+	}, nil
+}
+
+// LastGoodDeployment returns the cluster.Deployment of the last group which became healthy,
+// or nil if there is no such group
+func (m *Manifest) LastGoodDeployment() (ctypes.IDeployment, error) {
+	if m.Spec.LastGood == nil {
+		return nil, nil
+	}
+
+	lid, err := m.Spec.LeaseID.FromCRD()
+	if err != nil {
+		return nil, err
+	}
+
+	group, settings, err := m.Spec.LastGood.fromCRD()
+	if err != nil {
+		return nil, err
+	}
+
+	return &deployment{
+		lid:     lid,
+		group:   group,
+		cparams: settings,
 	}, nil
 }
 
 // toAkash returns akash group details formatted from manifest group
-func (m *ManifestGroup) fromCRD() (mani.Group, []*SchedulerParams, error) {
+func (m *ManifestGroup) fromCRD() (mani.Group, ClusterSettings, error) {
 	am := mani.Group{
 		Name:     m.Name,
 		Services: make([]mani.Service, 0, len(m.Services)),
 	}
 
-	scheduleParams := make([]*SchedulerParams, 0, len(m.Services))
+	settings := ClusterSettings{
+		SchedulerParams: make([]*SchedulerParams, 0, len(m.Services)),
+	}
+
+	probes := make([]*ManifestServiceProbes, 0, len(m.Services))
+	hasProbes := false
 
 	for _, svc := range m.Services {
 		asvc, err := svc.fromCRD()
 		if err != nil {
-			return am, nil, err
+			return am, ClusterSettings{}, err
 		}
 		am.Services = append(am.Services, asvc)
 
-		scheduleParams = append(scheduleParams, svc.SchedulerParams)
+		settings.SchedulerParams = append(settings.SchedulerParams, svc.SchedulerParams)
+
+		var sprobes *ManifestServiceProbes
+		if svc.Params != nil && svc.Params.Probes != nil {
+			sprobes = svc.Params.Probes
+			hasProbes = true
+		}
+
+		probes = append(probes, sprobes)
+	}
+
+	if hasProbes {
+		settings.Probes = probes
 	}
 
-	return am, scheduleParams, nil
+	return am, settings, nil
 }
 
 // manifestGroupToCRD returns manifest group instance from akash group
@@ -211,7 +361,7 @@ func manifestGroupToCRD(m *mani.Group, settings ClusterSettings) (ManifestGroup,
 	}
 
 	for i, svc := range m.Services {
-		service, err := manifestServiceFromProvider(svc, settings.SchedulerParams[i])
+		service, err := manifestServiceFromProvider(svc, settings.SchedulerParams[i], settings.probes(i))
 		if err != nil {
 			return ManifestGroup{}, err
 		}
@@ -254,7 +404,8 @@ func (ms *ManifestService) fromCRD() (mani.Service, error) {
 		}
 	}
 
-	if ms.Params != nil {
+	// params holding probes only are provider side settings and have no manifest counterpart
+	if ms.Params != nil && (len(ms.Params.Storage) != 0 || ms.Params.Probes == nil) {
 		ams.Params = &mani.ServiceParams{
 			Storage: make([]mani.StorageParams, 0, len(ms.Params.Storage)),
 		}
@@ -271,7 +422,7 @@ func (ms *ManifestService) fromCRD() (mani.Service, error) {
 	return *ams, nil
 }
 
-func manifestServiceFromProvider(ams mani.Service, schedulerParams *SchedulerParams) (ManifestService, error) {
+func manifestServiceFromProvider(ams mani.Service, schedulerParams *SchedulerParams, probes *ManifestServiceProbes) (ManifestService, error) {
 	resources, err := resourceUnitsFromAkash(ams.Resources)
 	if err != nil {
 		return ManifestService{}, err
@@ -307,6 +458,14 @@ func manifestServiceFromProvider(ams mani.Service, schedulerParams *SchedulerPar
 		}
 	}
 
+	if probes != nil {
+		if ms.Params == nil {
+			ms.Params = &ManifestServiceParams{}
+		}
+
+		ms.Params.Probes = probes
+	}
+
 	return ms, nil
 }
 
@@ -11,6 +11,9 @@ import (
 	mani "github.com/akash-network/akash-api/go/manifest/v2beta2"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
 	types "github.com/akash-network/akash-api/go/node/types/v1beta3"
+	"github.com/akash-network/node/sdl"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 )
 
 var (
@@ -87,8 +90,28 @@ func LeaseIDFromAkash(id mtypes.LeaseID) LeaseID {
 }
 
 type ResourceUnitsStorage struct {
-	Name string `json:"name"`
-	Size string `json:"size"`
+	Name  string `json:"name"`
+	Size  string `json:"size"`
+	Class string `json:"class,omitempty"`
+}
+
+// attributes restores storage attributes from class of the volume.
+// SDL allows class on persistent volumes only, except memory backed ones
+func (st ResourceUnitsStorage) attributes() types.Attributes {
+	if st.Class == "" {
+		return nil
+	}
+
+	return types.Attributes{
+		{
+			Key:   sdl.StorageAttributeClass,
+			Value: st.Class,
+		},
+		{
+			Key:   sdl.StorageAttributePersistent,
+			Value: strconv.FormatBool(st.Class != ctypes.StorageClassRAM),
+		},
+	}
 }
 
 // ResourceUnits stores cpu, memory and storage details
@@ -113,8 +136,9 @@ func (ru ResourceUnits) fromCRD() (types.ResourceUnits, error) {
 		}
 
 		storage = append(storage, types.Storage{
-			Name:     st.Name,
-			Quantity: types.NewResourceValue(size),
+			Name:       st.Name,
+			Quantity:   types.NewResourceValue(size),
+			Attributes: st.attributes(),
 		})
 	}
 
@@ -154,9 +178,12 @@ func resourceUnitsFromAkash(aru types.ResourceUnits) (ResourceUnits, error) {
 
 	res.Storage = make([]ResourceUnitsStorage, 0, len(aru.Storage))
 	for _, storage := range aru.Storage {
+		class, _ := storage.Attributes.Find(sdl.StorageAttributeClass).AsString()
+
 		res.Storage = append(res.Storage, ResourceUnitsStorage{
-			Name: storage.Name,
-			Size: strconv.FormatUint(storage.Quantity.Value(), 10),
+			Name:  storage.Name,
+			Size:  strconv.FormatUint(storage.Quantity.Value(), 10),
+			Class: class,
 		})
 	}
 
@@ -1,11 +1,13 @@
 package v2beta2
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	mani "github.com/akash-network/akash-api/go/manifest/v2beta2"
 	atestutil "github.com/akash-network/node/testutil"
 
 	mtestutil "github.com/akash-network/provider/testutil/manifest/v2beta2"
@@ -29,3 +31,102 @@ func Test_Manifest_encoding(t *testing.T) {
 		assert.Equal(t, &mgroup, deployment.ManifestGroup(), spec.Name)
 	}
 }
+
+func Test_Manifest_probes(t *testing.T) {
+	lid := atestutil.LeaseID(t)
+	mgroup := mtestutil.AppManifestGenerator.Group(t)
+	sparams := make([]*SchedulerParams, len(mgroup.Services))
+	probes := make([]*ManifestServiceProbes, len(mgroup.Services))
+	probes[0] = &ManifestServiceProbes{
+		Readiness: &ManifestServiceProbe{
+			Type: ProbeTypeHTTP,
+			Port: 8080,
+			Path: "/ready",
+		},
+	}
+
+	_, err := NewManifest("foo", lid, &mgroup, ClusterSettings{SchedulerParams: sparams, Probes: probes[:0:0]})
+	require.NoError(t, err)
+
+	_, err = NewManifest("foo", lid, &mgroup, ClusterSettings{SchedulerParams: sparams, Probes: append(probes, nil)})
+	require.ErrorIs(t, err, ErrInvalidArgs)
+
+	kmani, err := NewManifest("foo", lid, &mgroup, ClusterSettings{SchedulerParams: sparams, Probes: probes})
+	require.NoError(t, err)
+	require.NotNil(t, kmani.Spec.Group.Services[0].Params)
+	require.Equal(t, probes[0], kmani.Spec.Group.Services[0].Params.Probes)
+
+	deployment, err := kmani.Deployment()
+	require.NoError(t, err)
+	assert.Equal(t, &mgroup, deployment.ManifestGroup())
+
+	settings, valid := deployment.ClusterParams().(ClusterSettings)
+	require.True(t, valid)
+	require.Equal(t, probes, settings.Probes)
+}
+
+func Test_Manifest_probesFromTenantManifest(t *testing.T) {
+	lid := atestutil.LeaseID(t)
+	mgroup := mtestutil.AppManifestGenerator.Group(t)
+
+	data, err := json.Marshal(mani.Manifest{mgroup})
+	require.NoError(t, err)
+
+	// tenant sets probes within service params of the manifest
+	var raw []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &raw))
+
+	services := raw[0]["services"].([]interface{})
+	services[0].(map[string]interface{})["params"] = json.RawMessage(`{
+		"probes": {
+			"liveness": {"type": "http", "port": 8080, "path": "/health", "initialDelaySeconds": 10, "failureThreshold": 5}
+		}
+	}`)
+
+	data, err = json.Marshal(raw)
+	require.NoError(t, err)
+
+	probes, err := ParseManifestProbes(data)
+	require.NoError(t, err)
+	require.Len(t, probes[mgroup.Name], len(mgroup.Services))
+
+	expected := &ManifestServiceProbes{
+		Liveness: &ManifestServiceProbe{
+			Type:                ProbeTypeHTTP,
+			Port:                8080,
+			Path:                "/health",
+			InitialDelaySeconds: 10,
+			FailureThreshold:    5,
+		},
+	}
+	require.Equal(t, expected, probes[mgroup.Name][0])
+
+	reserved := ClusterSettings{SchedulerParams: make([]*SchedulerParams, len(mgroup.Services))}
+
+	kmani, err := NewManifest("foo", lid, &mgroup, reserved.WithProbes(probes[mgroup.Name]))
+	require.NoError(t, err)
+	require.Nil(t, reserved.Probes)
+
+	crdData, err := json.Marshal(kmani)
+	require.NoError(t, err)
+	require.Contains(t, string(crdData), `"initialDelaySeconds":10`)
+	require.Contains(t, string(crdData), `"failureThreshold":5`)
+
+	deployment, err := kmani.Deployment()
+	require.NoError(t, err)
+
+	settings, valid := deployment.ClusterParams().(ClusterSettings)
+	require.True(t, valid)
+	require.Equal(t, expected, settings.Probes[0])
+
+	// update without probes removes them
+	kmani, err = NewManifest("foo", lid, &mgroup, settings.WithProbes(nil))
+	require.NoError(t, err)
+
+	deployment, err = kmani.Deployment()
+	require.NoError(t, err)
+
+	settings, valid = deployment.ClusterParams().(ClusterSettings)
+	require.True(t, valid)
+	require.Empty(t, settings.Probes)
+}
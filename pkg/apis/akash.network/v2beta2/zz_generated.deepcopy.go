@@ -39,6 +39,17 @@ func (in *ClusterSettings) DeepCopyInto(out *ClusterSettings) {
 			}
 		}
 	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]*ManifestServiceProbes, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ManifestServiceProbes)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	return
 }
 
@@ -481,6 +492,11 @@ func (in *ManifestServiceParams) DeepCopyInto(out *ManifestServiceParams) {
 		*out = make([]ManifestStorageParams, len(*in))
 		copy(*out, *in)
 	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ManifestServiceProbes)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -494,11 +510,68 @@ func (in *ManifestServiceParams) DeepCopy() *ManifestServiceParams {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestServiceProbe) DeepCopyInto(out *ManifestServiceProbe) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestServiceProbe.
+func (in *ManifestServiceProbe) DeepCopy() *ManifestServiceProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestServiceProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestServiceProbes) DeepCopyInto(out *ManifestServiceProbes) {
+	*out = *in
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(ManifestServiceProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ManifestServiceProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(ManifestServiceProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestServiceProbes.
+func (in *ManifestServiceProbes) DeepCopy() *ManifestServiceProbes {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestServiceProbes)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManifestSpec) DeepCopyInto(out *ManifestSpec) {
 	*out = *in
 	out.LeaseID = in.LeaseID
 	in.Group.DeepCopyInto(&out.Group)
+	if in.LastGood != nil {
+		in, out := &in.LastGood, &out.LastGood
+		*out = new(ManifestGroup)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
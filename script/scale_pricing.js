@@ -0,0 +1,67 @@
+// Example pricing script for the jsScript bid pricing strategy.
+//
+// The provider calls calculatePrice(data, env) for each order, where data is the
+// same JSON document shellScript strategy receives on stdin and env contains
+// "owner" and "denom" of the order. The function must return price as a number
+// or a numeric string.
+//
+// Script runs inside sandbox without access to filesystem, network or environment,
+// so values such as exchange rates have to be embedded into the script.
+
+// These are the variables one can modify to change the scale for each resource kind
+const CPU_SCALE = 0.1; // per millicpu
+const MEMORY_SCALE = 0.02; // per megabyte
+const ENDPOINT_SCALE = 0.02;
+const IP_LEASE_SCALE = 5;
+const GPU_SCALE = {
+  "nvidia/a100": 100,
+  "*": 50,
+};
+const STORAGE_SCALE = {
+  ephemeral: 0.01,
+  default: 0.02,
+  beta1: 0.02,
+  beta2: 0.03,
+  beta3: 0.04,
+};
+
+const MEBIBYTE = 1024 * 1024;
+
+function gpuUnitPrice(gpu) {
+  let price = GPU_SCALE["*"];
+  const vendors = (gpu.attributes && gpu.attributes.vendor) || {};
+
+  for (const vendor in vendors) {
+    const key = vendor + "/" + vendors[vendor].model;
+    if (key in GPU_SCALE && GPU_SCALE[key] > price) {
+      price = GPU_SCALE[key];
+    }
+  }
+
+  return price;
+}
+
+function calculatePrice(data, env) {
+  let total = 0;
+
+  for (const group of data) {
+    let price = group.cpu * CPU_SCALE;
+    price += (group.memory / MEBIBYTE) * MEMORY_SCALE;
+    price += group.endpoint_quantity * ENDPOINT_SCALE;
+
+    if (group.gpu.units > 0) {
+      price += group.gpu.units * gpuUnitPrice(group.gpu);
+    }
+
+    for (const storage of group.storage) {
+      if (!(storage.class in STORAGE_SCALE)) {
+        throw new Error('requests unsupported storage class "' + storage.class + '"');
+      }
+      price += (storage.size / MEBIBYTE) * STORAGE_SCALE[storage.class];
+    }
+
+    total += price * group.count + group.ip_lease_quantity * IP_LEASE_SCALE;
+  }
+
+  return Math.ceil(total);
+}
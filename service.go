@@ -2,8 +2,8 @@ package provider
 
 import (
 	"context"
+	"fmt"
 
-	atypes "github.com/akash-network/akash-api/go/node/types/v1beta3"
 	"github.com/boz/go-lifecycle"
 	"github.com/pkg/errors"
 
@@ -12,6 +12,7 @@ import (
 	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 
 	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta3"
 	aclient "github.com/akash-network/node/client"
 	"github.com/akash-network/node/pubsub"
 
@@ -21,6 +22,7 @@ import (
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	"github.com/akash-network/provider/manifest"
 	"github.com/akash-network/provider/operator/waiter"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 	"github.com/akash-network/provider/session"
 )
 
@@ -42,6 +44,7 @@ type Client interface {
 	ValidateClient
 	Manifest() manifest.Client
 	Cluster() cluster.Client
+	Bidengine() bidengine.DecisionClient
 	Hostname() ctypes.HostnameServiceClient
 	ClusterService() cluster.Service
 }
@@ -82,6 +85,8 @@ func NewService(ctx context.Context,
 	clusterConfig.DeploymentIngressStaticHosts = cfg.DeploymentIngressStaticHosts
 	clusterConfig.DeploymentIngressDomain = cfg.DeploymentIngressDomain
 	clusterConfig.ClusterSettings = cfg.ClusterSettings
+	clusterConfig.OrphanSweepPeriod = cfg.OrphanSweepPeriod
+	clusterConfig.OrphanSweepMinAge = cfg.OrphanSweepMinAge
 
 	bc, err := newBalanceChecker(ctx, bankTypes.NewQueryClient(cctx), aclient.NewQueryClientFromCtx(cctx), accAddr, session, bus, cfg.BalanceCheckerCfg)
 	if err != nil {
@@ -97,12 +102,24 @@ func NewService(ctx context.Context,
 		return nil, err
 	}
 
+	pricing, err := bidengine.MakePricingPipelineFromConfig(cfg.BidPricingStrategy, cfg.BidPricingPipeline, cluster)
+	if err != nil {
+		session.Log().Error("creating bid pricing pipeline", "err", err)
+		cancel()
+		<-cluster.Done()
+		<-bc.lc.Done()
+		return nil, err
+	}
+
 	bidengine, err := bidengine.NewService(ctx, session, cluster, bus, waiter, bidengine.Config{
-		PricingStrategy: cfg.BidPricingStrategy,
-		Deposit:         cfg.BidDeposit,
-		BidTimeout:      cfg.BidTimeout,
-		Attributes:      cfg.Attributes,
-		MaxGroupVolumes: cfg.MaxGroupVolumes,
+		PricingStrategy:   pricing,
+		Deposit:           cfg.BidDeposit,
+		BidTimeout:        cfg.BidTimeout,
+		Attributes:        cfg.Attributes,
+		MaxGroupVolumes:   cfg.MaxGroupVolumes,
+		DecisionLogSize:   cfg.BidDecisionLogSize,
+		TenantPolicy:      cfg.TenantPolicy,
+		MaxInFlightOrders: cfg.BidMaxInFlightOrders,
 	})
 	if err != nil {
 		errmsg := "creating bidengine service"
@@ -118,6 +135,7 @@ func NewService(ctx context.Context,
 		ManifestTimeout:                   cfg.ManifestTimeout,
 		RPCQueryTimeout:                   cfg.RPCQueryTimeout,
 		CachedResultMaxAge:                cfg.CachedResultMaxAge,
+		ImagePolicy:                       cfg.ImagePolicy,
 	}
 
 	manifest, err := manifest.NewService(ctx, session, bus, cluster.HostnameService(), manifestConfig)
@@ -132,6 +150,7 @@ func NewService(ctx context.Context,
 
 	svc := &service{
 		session:   session,
+		pricing:   pricing,
 		bus:       bus,
 		cluster:   cluster,
 		cclient:   cclient,
@@ -158,6 +177,7 @@ type service struct {
 
 	cluster   cluster.Service
 	bidengine bidengine.Service
+	pricing   bidengine.BidPricingStrategy
 	manifest  manifest.Service
 	bc        *balanceChecker
 
@@ -191,6 +211,10 @@ func (s *service) Cluster() cluster.Client {
 	return s.cclient
 }
 
+func (s *service) Bidengine() bidengine.DecisionClient {
+	return s.bidengine
+}
+
 func (s *service) Status(ctx context.Context) (*Status, error) {
 	cluster, err := s.cluster.Status(ctx)
 	if err != nil {
@@ -213,34 +237,81 @@ func (s *service) Status(ctx context.Context) (*Status, error) {
 }
 
 func (s *service) Validate(ctx context.Context, owner sdk.Address, gspec dtypes.GroupSpec) (ValidateGroupSpecResult, error) {
-	// FUTURE - pass owner here
-	req := bidengine.Request{
-		Owner: owner.String(),
-		GSpec: &gspec,
+	group := &dtypes.Group{
+		GroupID: dtypes.GroupID{
+			Owner: owner.String(),
+		},
+		GroupSpec: gspec,
 	}
 
-	inv, err := s.cclient.Inventory(ctx)
+	checks, err := s.bidengine.CheckGroup(ctx, group)
 	if err != nil {
 		return ValidateGroupSpecResult{}, err
 	}
 
-	res := &reservation{
-		resources:     nil,
-		clusterParams: nil,
-	}
+	result := ValidateGroupSpecResult{}
 
-	if err = inv.Adjust(res, ctypes.WithDryRun()); err != nil {
+	// dry run reservation against current inventory, existing reservations included
+	res, err := s.cluster.DryRunReserve(ctx, mtypes.OrderID{Owner: group.GroupID.Owner}, group)
+	if errors.Is(err, cluster.ErrNotRunning) {
 		return ValidateGroupSpecResult{}, err
 	}
 
-	price, err := s.config.BidPricingStrategy.CalculatePrice(ctx, req)
+	capacity := bidengine.GroupCheck{
+		Name:   ValidateCheckInsufficientCapacity,
+		Passed: err == nil,
+	}
+
 	if err != nil {
-		return ValidateGroupSpecResult{}, err
+		capacity.Message = err.Error()
+	} else if cparams, valid := res.ClusterParams().(crd.ClusterSettings); valid {
+		result.ClusterParams = &cparams
 	}
 
-	return ValidateGroupSpecResult{
-		MinBidPrice: price,
-	}, nil
+	checks = append(checks, capacity)
+	checks = append(checks, s.validatePrice(ctx, group, &result.MinBidPrice)...)
+
+	result.Checks = checks
+	result.Bid = bidengine.FailedGroupCheck(checks) == nil
+
+	return result, nil
+}
+
+// validatePrice calculates bid price the same way order does and checks it against group max price
+func (s *service) validatePrice(ctx context.Context, group *dtypes.Group, price *sdk.DecCoin) []bidengine.GroupCheck {
+	var err error
+
+	*price, err = s.pricing.CalculatePrice(ctx, bidengine.Request{
+		Owner: group.GroupID.Owner,
+		GSpec: &group.GroupSpec,
+	})
+	if err != nil {
+		return []bidengine.GroupCheck{{
+			Name:    ValidateCheckPricing,
+			Message: err.Error(),
+		}}
+	}
+
+	checks := []bidengine.GroupCheck{
+		{Name: ValidateCheckPricing, Passed: true},
+		{Name: ValidateCheckUnsupportedDenom, Passed: true},
+		{Name: ValidateCheckPriceTooHigh, Passed: true},
+	}
+
+	maxPrice := group.GroupSpec.Price()
+
+	if maxPrice.GetDenom() != price.GetDenom() {
+		checks[1].Passed = false
+		checks[1].Message = fmt.Sprintf("calculated price denom %q does not match max price denom %q", price.GetDenom(), maxPrice.GetDenom())
+		return checks[:2]
+	}
+
+	if maxPrice.IsLT(*price) {
+		checks[2].Passed = false
+		checks[2].Message = fmt.Sprintf("calculated price %s exceeds max price %s", price.String(), maxPrice.String())
+	}
+
+	return checks
 }
 
 func (s *service) run() {
@@ -266,27 +337,3 @@ func (s *service) run() {
 
 	s.session.Log().Info("shutdown complete")
 }
-
-type reservation struct {
-	resources         atypes.ResourceGroup
-	adjustedResources []atypes.Resources
-	clusterParams     interface{}
-}
-
-var _ ctypes.ReservationGroup = (*reservation)(nil)
-
-func (r *reservation) Resources() atypes.ResourceGroup {
-	return r.resources
-}
-
-func (r *reservation) SetAllocatedResources(val []atypes.Resources) {
-	r.adjustedResources = val
-}
-
-func (r *reservation) SetClusterParams(val interface{}) {
-	r.clusterParams = val
-}
-
-func (r *reservation) ClusterParams() interface{} {
-	return r.clusterParams
-}
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	"github.com/akash-network/node/testutil"
+
+	"github.com/akash-network/provider/bidengine"
+	"github.com/akash-network/provider/cluster"
+	cmocks "github.com/akash-network/provider/cluster/mocks"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+type validateBidengine struct {
+	bidengine.Service
+	checks []bidengine.GroupCheck
+}
+
+func (vb validateBidengine) CheckGroup(_ context.Context, _ *dtypes.Group) ([]bidengine.GroupCheck, error) {
+	return append([]bidengine.GroupCheck{}, vb.checks...), nil
+}
+
+type fixedPrice sdk.DecCoin
+
+func (fp fixedPrice) CalculatePrice(_ context.Context, _ bidengine.Request) (sdk.DecCoin, error) {
+	return sdk.DecCoin(fp), nil
+}
+
+func TestValidate(t *testing.T) {
+	owner := testutil.AccAddress(t)
+	gspec := testutil.GroupSpec(t)
+	maxPrice := gspec.Price()
+
+	cparams := crd.ClusterSettings{
+		SchedulerParams: make([]*crd.SchedulerParams, len(gspec.Resources)),
+	}
+
+	reservation := &cmocks.Reservation{}
+	reservation.On("ClusterParams").Return(cparams)
+
+	passed := []bidengine.GroupCheck{{Name: bidengine.ReasonOrderAttributes, Passed: true}}
+
+	tests := []struct {
+		name    string
+		checks  []bidengine.GroupCheck
+		reserve error
+		price   sdk.DecCoin
+		err     error
+		bid     bool
+		failed  string
+		nchecks int
+	}{
+		{
+			name:    "bid",
+			checks:  passed,
+			price:   sdk.NewDecCoin(maxPrice.Denom, sdk.NewInt(1)),
+			bid:     true,
+			nchecks: 5,
+		},
+		{
+			name:    "bid engine check failed",
+			checks:  []bidengine.GroupCheck{{Name: bidengine.ReasonOrderAttributes, Message: "attributes"}},
+			price:   sdk.NewDecCoin(maxPrice.Denom, sdk.NewInt(1)),
+			failed:  bidengine.ReasonOrderAttributes,
+			nchecks: 5,
+		},
+		{
+			name:    "insufficient capacity",
+			checks:  passed,
+			reserve: ctypes.ErrInsufficientCapacity,
+			price:   sdk.NewDecCoin(maxPrice.Denom, sdk.NewInt(1)),
+			failed:  ValidateCheckInsufficientCapacity,
+			nchecks: 5,
+		},
+		{
+			name:    "cluster not running",
+			checks:  passed,
+			reserve: cluster.ErrNotRunning,
+			err:     cluster.ErrNotRunning,
+		},
+		{
+			name:    "denom mismatch",
+			checks:  passed,
+			price:   sdk.NewDecCoin("ufoo", sdk.NewInt(1)),
+			failed:  ValidateCheckUnsupportedDenom,
+			nchecks: 4,
+		},
+		{
+			name:    "price too high",
+			checks:  passed,
+			price:   sdk.NewDecCoinFromDec(maxPrice.Denom, maxPrice.Amount.Add(sdk.OneDec())),
+			failed:  ValidateCheckPriceTooHigh,
+			nchecks: 5,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl := &cmocks.Service{}
+			if test.reserve != nil {
+				cl.On("DryRunReserve", mock.Anything, mock.Anything, mock.Anything).Return(nil, test.reserve)
+			} else {
+				cl.On("DryRunReserve", mock.Anything, mock.Anything, mock.Anything).Return(reservation, nil)
+			}
+
+			svc := &service{
+				cluster:   cl,
+				bidengine: validateBidengine{checks: test.checks},
+				pricing:   fixedPrice(test.price),
+			}
+
+			res, err := svc.Validate(context.Background(), owner, gspec)
+			if test.err != nil {
+				require.True(t, errors.Is(err, test.err))
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, test.bid, res.Bid)
+			require.Len(t, res.Checks, test.nchecks)
+
+			if test.failed != "" {
+				failed := bidengine.FailedGroupCheck(res.Checks)
+				require.NotNil(t, failed)
+				require.Equal(t, test.failed, failed.Name)
+			}
+
+			// params are reported whenever resources fit, regardless of other checks
+			if test.reserve == nil {
+				require.Equal(t, &cparams, res.ClusterParams)
+			} else {
+				require.Nil(t, res.ClusterParams)
+			}
+
+			if test.nchecks == 5 {
+				require.Equal(t, test.price, res.MinBidPrice)
+			}
+
+			// dry run is bound to the request context
+			cl.AssertCalled(t, "DryRunReserve", context.Background(), mock.Anything, mock.Anything)
+		})
+	}
+}
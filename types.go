@@ -6,6 +6,7 @@ import (
 	"github.com/akash-network/provider/bidengine"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	"github.com/akash-network/provider/manifest"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 )
 
 // Status is the data structure that stores Cluster, Bidengine and Manifest details.
@@ -16,6 +17,19 @@ type Status struct {
 	ClusterPublicHostname string            `json:"cluster_public_hostname,omitempty"`
 }
 
+// Names of the checks validate runs in addition to bid engine ones
+const (
+	ValidateCheckInsufficientCapacity = bidengine.ReasonInsufficientCapacity
+	ValidateCheckPricing              = bidengine.ReasonPricingFailed
+	ValidateCheckUnsupportedDenom     = bidengine.ReasonUnsupportedDenom
+	ValidateCheckPriceTooHigh         = bidengine.ReasonPriceTooHigh
+)
+
+// ValidateGroupSpecResult is the outcome of running group spec through the bidding pipeline.
+// Bid is true only if every check passed
 type ValidateGroupSpecResult struct {
-	MinBidPrice sdk.DecCoin `json:"min_bid_price"`
+	MinBidPrice   sdk.DecCoin            `json:"min_bid_price"`
+	Bid           bool                   `json:"bid"`
+	Checks        []bidengine.GroupCheck `json:"checks,omitempty"`
+	ClusterParams *crd.ClusterSettings   `json:"cluster_params,omitempty"`
 }